@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dancodery/algorand-state-channels/payment"
+)
+
+// abortChannelOpen refunds my deposit of a channel app the partner node has not accepted on chain.
+// It reports partner_accepted if the partner node accepted the channel after all, and the fee the refund paid.
+// The phases of the refund are recorded into recorder, which may be nil.
+func (s *server) abortChannelOpen(app_id uint64, recorder *payment.PhaseRecorder) (partner_accepted bool, fee uint64, err error) {
+	// 1. read the channel app from the blockchain
+	app_state, err := payment.ReadChannelAppState(s.algod_client, app_id)
+	if err != nil {
+		return false, 0, err
+	}
+
+	// 2. only alice can abort the channel
+	if app_state.AliceAddress != s.account().Address() {
+		return false, 0, fmt.Errorf("app_id %d was not created by me", app_id)
+	}
+
+	// 3. the partner node must not have accepted the channel
+	if app_state.BobAccepted {
+		return true, 0, errors.New("partner node already accepted the channel")
+	}
+
+	// 4. refund my deposit
+	fee, err = payment.AbortChannelOpen(s.algod_client, payment.WithPhaseRecorder(s.account(), recorder), app_id, app_state.AssetID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	s.recordChannelFee(app_id, app_state.BobAddress.String(), fee)
+
+	fmt.Printf("Aborted opening the payment channel with app_id %d, the deposit was refunded\n\n", app_id)
+	return false, fee, nil
+}
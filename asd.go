@@ -21,7 +21,7 @@ func loadMain(out io.Writer) error {
 	}
 
 	// initialize server environment
-	server, err := initializeServer(loadedConfig.PeerPort, loadedConfig.GRPCPort)
+	server, err := initializeServer(loadedConfig)
 	if err != nil {
 		log.Fatalf("failed to create server: %v\n", err)
 		return err
@@ -31,7 +31,9 @@ func loadMain(out io.Writer) error {
 	}
 
 	// start grpc server
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(server.rpcServer.lockInterceptor), // refuse rpcs while the wallet is locked
+	}
 	grpcServer := grpc.NewServer(opts...)
 	asrpc.RegisterASRPCServer(grpcServer, server.rpcServer)
 	fmt.Printf("Started grpc server on port %d\n", loadedConfig.GRPCPort)
@@ -21,6 +21,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Invoice_InvoiceState int32
+
+const (
+	Invoice_OPEN    Invoice_InvoiceState = 0
+	Invoice_SETTLED Invoice_InvoiceState = 1
+	Invoice_EXPIRED Invoice_InvoiceState = 2
+)
+
+// Enum value maps for Invoice_InvoiceState.
+var (
+	Invoice_InvoiceState_name = map[int32]string{
+		0: "OPEN",
+		1: "SETTLED",
+		2: "EXPIRED",
+	}
+	Invoice_InvoiceState_value = map[string]int32{
+		"OPEN":    0,
+		"SETTLED": 1,
+		"EXPIRED": 2,
+	}
+)
+
+func (x Invoice_InvoiceState) Enum() *Invoice_InvoiceState {
+	p := new(Invoice_InvoiceState)
+	*p = x
+	return p
+}
+
+func (x Invoice_InvoiceState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Invoice_InvoiceState) Descriptor() protoreflect.EnumDescriptor {
+	return file_asrpc_proto_enumTypes[0].Descriptor()
+}
+
+func (Invoice_InvoiceState) Type() protoreflect.EnumType {
+	return &file_asrpc_proto_enumTypes[0]
+}
+
+func (x Invoice_InvoiceState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Invoice_InvoiceState.Descriptor instead.
+func (Invoice_InvoiceState) EnumDescriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{31, 0}
+}
+
 type StateChannelNodeAddress struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -83,7 +132,8 @@ type RuntimeRecording struct {
 
 	TimestampStart *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp_start,json=timestampStart,proto3" json:"timestamp_start,omitempty"`
 	TimestampEnd   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp_end,json=timestampEnd,proto3" json:"timestamp_end,omitempty"`
-	BlockchainFee  uint64                 `protobuf:"varint,3,opt,name=blockchain_fee,json=blockchainFee,proto3" json:"blockchain_fee,omitempty"`
+	BlockchainFee  uint64                 `protobuf:"varint,3,opt,name=blockchain_fee,json=blockchainFee,proto3" json:"blockchain_fee,omitempty"` // microalgos paid for the transactions of the call, including inner transaction fees
+	PhaseSpans     []*PhaseSpan           `protobuf:"bytes,4,rep,name=phase_spans,json=phaseSpans,proto3" json:"phase_spans,omitempty"`           // phases of the call in the order they started, phases can repeat
 }
 
 func (x *RuntimeRecording) Reset() {
@@ -139,14 +189,25 @@ func (x *RuntimeRecording) GetBlockchainFee() uint64 {
 	return 0
 }
 
-type ResetRequest struct {
+func (x *RuntimeRecording) GetPhaseSpans() []*PhaseSpan {
+	if x != nil {
+		return x.PhaseSpans
+	}
+	return nil
+}
+
+type PhaseSpan struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // signing, p2p_round_trip, partner_verification, simulation, transaction_submission or confirmation_wait
+	TimestampStart *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp_start,json=timestampStart,proto3" json:"timestamp_start,omitempty"`
+	TimestampEnd   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp_end,json=timestampEnd,proto3" json:"timestamp_end,omitempty"`
 }
 
-func (x *ResetRequest) Reset() {
-	*x = ResetRequest{}
+func (x *PhaseSpan) Reset() {
+	*x = PhaseSpan{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -154,13 +215,13 @@ func (x *ResetRequest) Reset() {
 	}
 }
 
-func (x *ResetRequest) String() string {
+func (x *PhaseSpan) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetRequest) ProtoMessage() {}
+func (*PhaseSpan) ProtoMessage() {}
 
-func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+func (x *PhaseSpan) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -172,21 +233,43 @@ func (x *ResetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
-func (*ResetRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PhaseSpan.ProtoReflect.Descriptor instead.
+func (*PhaseSpan) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{2}
 }
 
-type ResetResponse struct {
+func (x *PhaseSpan) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PhaseSpan) GetTimestampStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TimestampStart
+	}
+	return nil
+}
+
+func (x *PhaseSpan) GetTimestampEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TimestampEnd
+	}
+	return nil
+}
+
+type CreateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	Password string `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	Mnemonic string `protobuf:"bytes,2,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"` // optional, restores this account instead of generating a new one
 }
 
-func (x *ResetResponse) Reset() {
-	*x = ResetResponse{}
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -194,13 +277,13 @@ func (x *ResetResponse) Reset() {
 	}
 }
 
-func (x *ResetResponse) String() string {
+func (x *CreateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetResponse) ProtoMessage() {}
+func (*CreateRequest) ProtoMessage() {}
 
-func (x *ResetResponse) ProtoReflect() protoreflect.Message {
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -212,26 +295,37 @@ func (x *ResetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
-func (*ResetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ResetResponse) GetRuntimeRecording() *RuntimeRecording {
+func (x *CreateRequest) GetPassword() string {
 	if x != nil {
-		return x.RuntimeRecording
+		return x.Password
 	}
-	return nil
+	return ""
 }
 
-type GetInfoRequest struct {
+func (x *CreateRequest) GetMnemonic() string {
+	if x != nil {
+		return x.Mnemonic
+	}
+	return ""
+}
+
+type CreateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress      string            `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	Mnemonic         string            `protobuf:"bytes,2,opt,name=mnemonic,proto3" json:"mnemonic,omitempty"` // backup of the generated account
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *GetInfoRequest) Reset() {
-	*x = GetInfoRequest{}
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -239,13 +333,13 @@ func (x *GetInfoRequest) Reset() {
 	}
 }
 
-func (x *GetInfoRequest) String() string {
+func (x *CreateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoRequest) ProtoMessage() {}
+func (*CreateResponse) ProtoMessage() {}
 
-func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -257,23 +351,42 @@ func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{4}
 }
 
-type GetInfoResponse struct {
+func (x *CreateResponse) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *CreateResponse) GetMnemonic() string {
+	if x != nil {
+		return x.Mnemonic
+	}
+	return ""
+}
+
+func (x *CreateResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type UnlockRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AlgoAddress      string            `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
-	AlgoBalance      uint64            `protobuf:"varint,2,opt,name=algo_balance,json=algoBalance,proto3" json:"algo_balance,omitempty"`
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	Password string `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
 }
 
-func (x *GetInfoResponse) Reset() {
-	*x = GetInfoResponse{}
+func (x *UnlockRequest) Reset() {
+	*x = UnlockRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -281,13 +394,13 @@ func (x *GetInfoResponse) Reset() {
 	}
 }
 
-func (x *GetInfoResponse) String() string {
+func (x *UnlockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoResponse) ProtoMessage() {}
+func (*UnlockRequest) ProtoMessage() {}
 
-func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+func (x *UnlockRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -299,45 +412,29 @@ func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UnlockRequest.ProtoReflect.Descriptor instead.
+func (*UnlockRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetInfoResponse) GetAlgoAddress() string {
+func (x *UnlockRequest) GetPassword() string {
 	if x != nil {
-		return x.AlgoAddress
+		return x.Password
 	}
 	return ""
 }
 
-func (x *GetInfoResponse) GetAlgoBalance() uint64 {
-	if x != nil {
-		return x.AlgoBalance
-	}
-	return 0
-}
-
-func (x *GetInfoResponse) GetRuntimeRecording() *RuntimeRecording {
-	if x != nil {
-		return x.RuntimeRecording
-	}
-	return nil
-}
-
-type OpenChannelRequest struct {
+type UnlockResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PartnerNode    *StateChannelNodeAddress `protobuf:"bytes,1,opt,name=partner_node,json=partnerNode,proto3" json:"partner_node,omitempty"`
-	FundingAmount  uint64                   `protobuf:"varint,2,opt,name=funding_amount,json=fundingAmount,proto3" json:"funding_amount,omitempty"`
-	PenaltyReserve uint64                   `protobuf:"varint,3,opt,name=penalty_reserve,json=penaltyReserve,proto3" json:"penalty_reserve,omitempty"`
-	DisputeWindow  uint64                   `protobuf:"varint,4,opt,name=dispute_window,json=disputeWindow,proto3" json:"dispute_window,omitempty"`
+	AlgoAddress      string            `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *OpenChannelRequest) Reset() {
-	*x = OpenChannelRequest{}
+func (x *UnlockResponse) Reset() {
+	*x = UnlockResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -345,13 +442,13 @@ func (x *OpenChannelRequest) Reset() {
 	}
 }
 
-func (x *OpenChannelRequest) String() string {
+func (x *UnlockResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OpenChannelRequest) ProtoMessage() {}
+func (*UnlockResponse) ProtoMessage() {}
 
-func (x *OpenChannelRequest) ProtoReflect() protoreflect.Message {
+func (x *UnlockResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -363,50 +460,33 @@ func (x *OpenChannelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OpenChannelRequest.ProtoReflect.Descriptor instead.
-func (*OpenChannelRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UnlockResponse.ProtoReflect.Descriptor instead.
+func (*UnlockResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *OpenChannelRequest) GetPartnerNode() *StateChannelNodeAddress {
-	if x != nil {
-		return x.PartnerNode
-	}
-	return nil
-}
-
-func (x *OpenChannelRequest) GetFundingAmount() uint64 {
-	if x != nil {
-		return x.FundingAmount
-	}
-	return 0
-}
-
-func (x *OpenChannelRequest) GetPenaltyReserve() uint64 {
+func (x *UnlockResponse) GetAlgoAddress() string {
 	if x != nil {
-		return x.PenaltyReserve
+		return x.AlgoAddress
 	}
-	return 0
+	return ""
 }
 
-func (x *OpenChannelRequest) GetDisputeWindow() uint64 {
+func (x *UnlockResponse) GetRuntimeRecording() *RuntimeRecording {
 	if x != nil {
-		return x.DisputeWindow
+		return x.RuntimeRecording
 	}
-	return 0
+	return nil
 }
 
-type OpenChannelResponse struct {
+type ResetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	AppId            uint64            `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *OpenChannelResponse) Reset() {
-	*x = OpenChannelResponse{}
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -414,13 +494,13 @@ func (x *OpenChannelResponse) Reset() {
 	}
 }
 
-func (x *OpenChannelResponse) String() string {
+func (x *ResetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OpenChannelResponse) ProtoMessage() {}
+func (*ResetRequest) ProtoMessage() {}
 
-func (x *OpenChannelResponse) ProtoReflect() protoreflect.Message {
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -432,36 +512,21 @@ func (x *OpenChannelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OpenChannelResponse.ProtoReflect.Descriptor instead.
-func (*OpenChannelResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *OpenChannelResponse) GetAppId() uint64 {
-	if x != nil {
-		return x.AppId
-	}
-	return 0
-}
-
-func (x *OpenChannelResponse) GetRuntimeRecording() *RuntimeRecording {
-	if x != nil {
-		return x.RuntimeRecording
-	}
-	return nil
-}
-
-type PayRequest struct {
+type ResetResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
-	Amount      uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *PayRequest) Reset() {
-	*x = PayRequest{}
+func (x *ResetResponse) Reset() {
+	*x = ResetResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -469,13 +534,13 @@ func (x *PayRequest) Reset() {
 	}
 }
 
-func (x *PayRequest) String() string {
+func (x *ResetResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PayRequest) ProtoMessage() {}
+func (*ResetResponse) ProtoMessage() {}
 
-func (x *PayRequest) ProtoReflect() protoreflect.Message {
+func (x *ResetResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -487,35 +552,26 @@ func (x *PayRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PayRequest.ProtoReflect.Descriptor instead.
-func (*PayRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
+func (*ResetResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *PayRequest) GetAlgoAddress() string {
-	if x != nil {
-		return x.AlgoAddress
-	}
-	return ""
-}
-
-func (x *PayRequest) GetAmount() uint64 {
+func (x *ResetResponse) GetRuntimeRecording() *RuntimeRecording {
 	if x != nil {
-		return x.Amount
+		return x.RuntimeRecording
 	}
-	return 0
+	return nil
 }
 
-type PayResponse struct {
+type GetInfoRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *PayResponse) Reset() {
-	*x = PayResponse{}
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -523,13 +579,13 @@ func (x *PayResponse) Reset() {
 	}
 }
 
-func (x *PayResponse) String() string {
+func (x *GetInfoRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PayResponse) ProtoMessage() {}
+func (*GetInfoRequest) ProtoMessage() {}
 
-func (x *PayResponse) ProtoReflect() protoreflect.Message {
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -541,28 +597,23 @@ func (x *PayResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PayResponse.ProtoReflect.Descriptor instead.
-func (*PayResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *PayResponse) GetRuntimeRecording() *RuntimeRecording {
-	if x != nil {
-		return x.RuntimeRecording
-	}
-	return nil
-}
-
-type CooperativeCloseChannelRequest struct {
+type GetInfoResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	AlgoAddress      string            `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	AlgoBalance      uint64            `protobuf:"varint,2,opt,name=algo_balance,json=algoBalance,proto3" json:"algo_balance,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *CooperativeCloseChannelRequest) Reset() {
-	*x = CooperativeCloseChannelRequest{}
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -570,13 +621,13 @@ func (x *CooperativeCloseChannelRequest) Reset() {
 	}
 }
 
-func (x *CooperativeCloseChannelRequest) String() string {
+func (x *GetInfoResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CooperativeCloseChannelRequest) ProtoMessage() {}
+func (*GetInfoResponse) ProtoMessage() {}
 
-func (x *CooperativeCloseChannelRequest) ProtoReflect() protoreflect.Message {
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -588,28 +639,46 @@ func (x *CooperativeCloseChannelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CooperativeCloseChannelRequest.ProtoReflect.Descriptor instead.
-func (*CooperativeCloseChannelRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *CooperativeCloseChannelRequest) GetAlgoAddress() string {
+func (x *GetInfoResponse) GetAlgoAddress() string {
 	if x != nil {
 		return x.AlgoAddress
 	}
 	return ""
 }
 
-type CooperativeCloseChannelResponse struct {
+func (x *GetInfoResponse) GetAlgoBalance() uint64 {
+	if x != nil {
+		return x.AlgoBalance
+	}
+	return 0
+}
+
+func (x *GetInfoResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type OpenChannelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	PartnerNode    *StateChannelNodeAddress `protobuf:"bytes,1,opt,name=partner_node,json=partnerNode,proto3" json:"partner_node,omitempty"`
+	FundingAmount  uint64                   `protobuf:"varint,2,opt,name=funding_amount,json=fundingAmount,proto3" json:"funding_amount,omitempty"`
+	PenaltyReserve uint64                   `protobuf:"varint,3,opt,name=penalty_reserve,json=penaltyReserve,proto3" json:"penalty_reserve,omitempty"`
+	DisputeWindow  uint64                   `protobuf:"varint,4,opt,name=dispute_window,json=disputeWindow,proto3" json:"dispute_window,omitempty"`
+	AssetId        uint64                   `protobuf:"varint,5,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"` // optional, asa the channel is denominated in, 0 for algo
 }
 
-func (x *CooperativeCloseChannelResponse) Reset() {
-	*x = CooperativeCloseChannelResponse{}
+func (x *OpenChannelRequest) Reset() {
+	*x = OpenChannelRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -617,13 +686,13 @@ func (x *CooperativeCloseChannelResponse) Reset() {
 	}
 }
 
-func (x *CooperativeCloseChannelResponse) String() string {
+func (x *OpenChannelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CooperativeCloseChannelResponse) ProtoMessage() {}
+func (*OpenChannelRequest) ProtoMessage() {}
 
-func (x *CooperativeCloseChannelResponse) ProtoReflect() protoreflect.Message {
+func (x *OpenChannelRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -635,28 +704,57 @@ func (x *CooperativeCloseChannelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CooperativeCloseChannelResponse.ProtoReflect.Descriptor instead.
-func (*CooperativeCloseChannelResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use OpenChannelRequest.ProtoReflect.Descriptor instead.
+func (*OpenChannelRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CooperativeCloseChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+func (x *OpenChannelRequest) GetPartnerNode() *StateChannelNodeAddress {
 	if x != nil {
-		return x.RuntimeRecording
+		return x.PartnerNode
 	}
 	return nil
 }
 
-type InitiateCloseChannelRequest struct {
+func (x *OpenChannelRequest) GetFundingAmount() uint64 {
+	if x != nil {
+		return x.FundingAmount
+	}
+	return 0
+}
+
+func (x *OpenChannelRequest) GetPenaltyReserve() uint64 {
+	if x != nil {
+		return x.PenaltyReserve
+	}
+	return 0
+}
+
+func (x *OpenChannelRequest) GetDisputeWindow() uint64 {
+	if x != nil {
+		return x.DisputeWindow
+	}
+	return 0
+}
+
+func (x *OpenChannelRequest) GetAssetId() uint64 {
+	if x != nil {
+		return x.AssetId
+	}
+	return 0
+}
+
+type OpenChannelResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	AppId            uint64            `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *InitiateCloseChannelRequest) Reset() {
-	*x = InitiateCloseChannelRequest{}
+func (x *OpenChannelResponse) Reset() {
+	*x = OpenChannelResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -664,13 +762,13 @@ func (x *InitiateCloseChannelRequest) Reset() {
 	}
 }
 
-func (x *InitiateCloseChannelRequest) String() string {
+func (x *OpenChannelResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InitiateCloseChannelRequest) ProtoMessage() {}
+func (*OpenChannelResponse) ProtoMessage() {}
 
-func (x *InitiateCloseChannelRequest) ProtoReflect() protoreflect.Message {
+func (x *OpenChannelResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -682,28 +780,38 @@ func (x *InitiateCloseChannelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InitiateCloseChannelRequest.ProtoReflect.Descriptor instead.
-func (*InitiateCloseChannelRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use OpenChannelResponse.ProtoReflect.Descriptor instead.
+func (*OpenChannelResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *InitiateCloseChannelRequest) GetAlgoAddress() string {
+func (x *OpenChannelResponse) GetAppId() uint64 {
 	if x != nil {
-		return x.AlgoAddress
+		return x.AppId
 	}
-	return ""
+	return 0
 }
 
-type InitiateCloseChannelResponse struct {
+func (x *OpenChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type PayRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	Amount      uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaymentHash []byte `protobuf:"bytes,3,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"` // optional, pays the invoice of the partner node with this payment hash
+	Memo        string `protobuf:"bytes,4,opt,name=memo,proto3" json:"memo,omitempty"`                                  // optional description, e.g. an order id
 }
 
-func (x *InitiateCloseChannelResponse) Reset() {
-	*x = InitiateCloseChannelResponse{}
+func (x *PayRequest) Reset() {
+	*x = PayRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -711,13 +819,13 @@ func (x *InitiateCloseChannelResponse) Reset() {
 	}
 }
 
-func (x *InitiateCloseChannelResponse) String() string {
+func (x *PayRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InitiateCloseChannelResponse) ProtoMessage() {}
+func (*PayRequest) ProtoMessage() {}
 
-func (x *InitiateCloseChannelResponse) ProtoReflect() protoreflect.Message {
+func (x *PayRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -729,28 +837,50 @@ func (x *InitiateCloseChannelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InitiateCloseChannelResponse.ProtoReflect.Descriptor instead.
-func (*InitiateCloseChannelResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use PayRequest.ProtoReflect.Descriptor instead.
+func (*PayRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *InitiateCloseChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+func (x *PayRequest) GetAlgoAddress() string {
 	if x != nil {
-		return x.RuntimeRecording
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *PayRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PayRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
 	}
 	return nil
 }
 
-type FinalizeCloseChannelRequest struct {
+func (x *PayRequest) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type PayResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	Preimage         []byte            `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"` // proof of payment if an invoice was paid
 }
 
-func (x *FinalizeCloseChannelRequest) Reset() {
-	*x = FinalizeCloseChannelRequest{}
+func (x *PayResponse) Reset() {
+	*x = PayResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -758,13 +888,13 @@ func (x *FinalizeCloseChannelRequest) Reset() {
 	}
 }
 
-func (x *FinalizeCloseChannelRequest) String() string {
+func (x *PayResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FinalizeCloseChannelRequest) ProtoMessage() {}
+func (*PayResponse) ProtoMessage() {}
 
-func (x *FinalizeCloseChannelRequest) ProtoReflect() protoreflect.Message {
+func (x *PayResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -776,28 +906,35 @@ func (x *FinalizeCloseChannelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FinalizeCloseChannelRequest.ProtoReflect.Descriptor instead.
-func (*FinalizeCloseChannelRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PayResponse.ProtoReflect.Descriptor instead.
+func (*PayResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *FinalizeCloseChannelRequest) GetAlgoAddress() string {
+func (x *PayResponse) GetRuntimeRecording() *RuntimeRecording {
 	if x != nil {
-		return x.AlgoAddress
+		return x.RuntimeRecording
 	}
-	return ""
+	return nil
 }
 
-type FinalizeCloseChannelResponse struct {
+func (x *PayResponse) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+type CooperativeCloseChannelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
 }
 
-func (x *FinalizeCloseChannelResponse) Reset() {
-	*x = FinalizeCloseChannelResponse{}
+func (x *CooperativeCloseChannelRequest) Reset() {
+	*x = CooperativeCloseChannelRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -805,13 +942,13 @@ func (x *FinalizeCloseChannelResponse) Reset() {
 	}
 }
 
-func (x *FinalizeCloseChannelResponse) String() string {
+func (x *CooperativeCloseChannelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FinalizeCloseChannelResponse) ProtoMessage() {}
+func (*CooperativeCloseChannelRequest) ProtoMessage() {}
 
-func (x *FinalizeCloseChannelResponse) ProtoReflect() protoreflect.Message {
+func (x *CooperativeCloseChannelRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -823,28 +960,28 @@ func (x *FinalizeCloseChannelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FinalizeCloseChannelResponse.ProtoReflect.Descriptor instead.
-func (*FinalizeCloseChannelResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CooperativeCloseChannelRequest.ProtoReflect.Descriptor instead.
+func (*CooperativeCloseChannelRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *FinalizeCloseChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+func (x *CooperativeCloseChannelRequest) GetAlgoAddress() string {
 	if x != nil {
-		return x.RuntimeRecording
+		return x.AlgoAddress
 	}
-	return nil
+	return ""
 }
 
-type TryToCheatRequest struct {
+type CooperativeCloseChannelResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
 }
 
-func (x *TryToCheatRequest) Reset() {
-	*x = TryToCheatRequest{}
+func (x *CooperativeCloseChannelResponse) Reset() {
+	*x = CooperativeCloseChannelResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -852,13 +989,13 @@ func (x *TryToCheatRequest) Reset() {
 	}
 }
 
-func (x *TryToCheatRequest) String() string {
+func (x *CooperativeCloseChannelResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TryToCheatRequest) ProtoMessage() {}
+func (*CooperativeCloseChannelResponse) ProtoMessage() {}
 
-func (x *TryToCheatRequest) ProtoReflect() protoreflect.Message {
+func (x *CooperativeCloseChannelResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -870,28 +1007,28 @@ func (x *TryToCheatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TryToCheatRequest.ProtoReflect.Descriptor instead.
-func (*TryToCheatRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CooperativeCloseChannelResponse.ProtoReflect.Descriptor instead.
+func (*CooperativeCloseChannelResponse) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *TryToCheatRequest) GetAlgoAddress() string {
+func (x *CooperativeCloseChannelResponse) GetRuntimeRecording() *RuntimeRecording {
 	if x != nil {
-		return x.AlgoAddress
+		return x.RuntimeRecording
 	}
-	return ""
+	return nil
 }
 
-type TryToCheatResponse struct {
+type InitiateCloseChannelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
 }
 
-func (x *TryToCheatResponse) Reset() {
-	*x = TryToCheatResponse{}
+func (x *InitiateCloseChannelRequest) Reset() {
+	*x = InitiateCloseChannelRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_asrpc_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -899,13 +1036,13 @@ func (x *TryToCheatResponse) Reset() {
 	}
 }
 
-func (x *TryToCheatResponse) String() string {
+func (x *InitiateCloseChannelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TryToCheatResponse) ProtoMessage() {}
+func (*InitiateCloseChannelRequest) ProtoMessage() {}
 
-func (x *TryToCheatResponse) ProtoReflect() protoreflect.Message {
+func (x *InitiateCloseChannelRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_asrpc_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -917,16 +1054,2050 @@ func (x *TryToCheatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TryToCheatResponse.ProtoReflect.Descriptor instead.
-func (*TryToCheatResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use InitiateCloseChannelRequest.ProtoReflect.Descriptor instead.
+func (*InitiateCloseChannelRequest) Descriptor() ([]byte, []int) {
 	return file_asrpc_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *TryToCheatResponse) GetRuntimeRecording() *RuntimeRecording {
+func (x *InitiateCloseChannelRequest) GetAlgoAddress() string {
 	if x != nil {
-		return x.RuntimeRecording
+		return x.AlgoAddress
 	}
-	return nil
+	return ""
+}
+
+type InitiateCloseChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *InitiateCloseChannelResponse) Reset() {
+	*x = InitiateCloseChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InitiateCloseChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitiateCloseChannelResponse) ProtoMessage() {}
+
+func (x *InitiateCloseChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitiateCloseChannelResponse.ProtoReflect.Descriptor instead.
+func (*InitiateCloseChannelResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *InitiateCloseChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type FinalizeCloseChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+}
+
+func (x *FinalizeCloseChannelRequest) Reset() {
+	*x = FinalizeCloseChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeCloseChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeCloseChannelRequest) ProtoMessage() {}
+
+func (x *FinalizeCloseChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeCloseChannelRequest.ProtoReflect.Descriptor instead.
+func (*FinalizeCloseChannelRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FinalizeCloseChannelRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+type FinalizeCloseChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *FinalizeCloseChannelResponse) Reset() {
+	*x = FinalizeCloseChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeCloseChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeCloseChannelResponse) ProtoMessage() {}
+
+func (x *FinalizeCloseChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeCloseChannelResponse.ProtoReflect.Descriptor instead.
+func (*FinalizeCloseChannelResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FinalizeCloseChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type TryToCheatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+}
+
+func (x *TryToCheatRequest) Reset() {
+	*x = TryToCheatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TryToCheatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TryToCheatRequest) ProtoMessage() {}
+
+func (x *TryToCheatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TryToCheatRequest.ProtoReflect.Descriptor instead.
+func (*TryToCheatRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TryToCheatRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+type TryToCheatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *TryToCheatResponse) Reset() {
+	*x = TryToCheatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TryToCheatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TryToCheatResponse) ProtoMessage() {}
+
+func (x *TryToCheatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TryToCheatResponse.ProtoReflect.Descriptor instead.
+func (*TryToCheatResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TryToCheatResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type AddHTLCRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	Amount      uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaymentHash []byte `protobuf:"bytes,3,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"` // sha256 hash of the preimage
+	Expiry      uint64 `protobuf:"varint,4,opt,name=expiry,proto3" json:"expiry,omitempty"`                             // number of rounds until the htlc can be refunded
+}
+
+func (x *AddHTLCRequest) Reset() {
+	*x = AddHTLCRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddHTLCRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddHTLCRequest) ProtoMessage() {}
+
+func (x *AddHTLCRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddHTLCRequest.ProtoReflect.Descriptor instead.
+func (*AddHTLCRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AddHTLCRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *AddHTLCRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AddHTLCRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *AddHTLCRequest) GetExpiry() uint64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+type AddHTLCResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *AddHTLCResponse) Reset() {
+	*x = AddHTLCResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddHTLCResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddHTLCResponse) ProtoMessage() {}
+
+func (x *AddHTLCResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddHTLCResponse.ProtoReflect.Descriptor instead.
+func (*AddHTLCResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AddHTLCResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type SettleHTLCRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	Preimage    []byte `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"`
+}
+
+func (x *SettleHTLCRequest) Reset() {
+	*x = SettleHTLCRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettleHTLCRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettleHTLCRequest) ProtoMessage() {}
+
+func (x *SettleHTLCRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettleHTLCRequest.ProtoReflect.Descriptor instead.
+func (*SettleHTLCRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SettleHTLCRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *SettleHTLCRequest) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+type SettleHTLCResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *SettleHTLCResponse) Reset() {
+	*x = SettleHTLCResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettleHTLCResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettleHTLCResponse) ProtoMessage() {}
+
+func (x *SettleHTLCResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettleHTLCResponse.ProtoReflect.Descriptor instead.
+func (*SettleHTLCResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SettleHTLCResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type FailHTLCRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+}
+
+func (x *FailHTLCRequest) Reset() {
+	*x = FailHTLCRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailHTLCRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailHTLCRequest) ProtoMessage() {}
+
+func (x *FailHTLCRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailHTLCRequest.ProtoReflect.Descriptor instead.
+func (*FailHTLCRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *FailHTLCRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *FailHTLCRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+type FailHTLCResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *FailHTLCResponse) Reset() {
+	*x = FailHTLCResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FailHTLCResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailHTLCResponse) ProtoMessage() {}
+
+func (x *FailHTLCResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailHTLCResponse.ProtoReflect.Descriptor instead.
+func (*FailHTLCResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *FailHTLCResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type SendPaymentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DestinationAddress string                     `protobuf:"bytes,1,opt,name=destination_address,json=destinationAddress,proto3" json:"destination_address,omitempty"`
+	Amount             uint64                     `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Route              []*StateChannelNodeAddress `protobuf:"bytes,3,rep,name=route,proto3" json:"route,omitempty"`                                 // hops from my channel partner to the destination
+	PaymentHash        []byte                     `protobuf:"bytes,4,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`  // empty for a spontaneous payment
+	FinalExpiry        uint64                     `protobuf:"varint,5,opt,name=final_expiry,json=finalExpiry,proto3" json:"final_expiry,omitempty"` // number of rounds until the htlc of the destination can be refunded
+}
+
+func (x *SendPaymentRequest) Reset() {
+	*x = SendPaymentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendPaymentRequest) ProtoMessage() {}
+
+func (x *SendPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendPaymentRequest.ProtoReflect.Descriptor instead.
+func (*SendPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SendPaymentRequest) GetDestinationAddress() string {
+	if x != nil {
+		return x.DestinationAddress
+	}
+	return ""
+}
+
+func (x *SendPaymentRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SendPaymentRequest) GetRoute() []*StateChannelNodeAddress {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *SendPaymentRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *SendPaymentRequest) GetFinalExpiry() uint64 {
+	if x != nil {
+		return x.FinalExpiry
+	}
+	return 0
+}
+
+type SendPaymentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Preimage         []byte            `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	Fee              uint64            `protobuf:"varint,2,opt,name=fee,proto3" json:"fee,omitempty"` // forwarding fees paid to the intermediate hops
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *SendPaymentResponse) Reset() {
+	*x = SendPaymentResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendPaymentResponse) ProtoMessage() {}
+
+func (x *SendPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendPaymentResponse.ProtoReflect.Descriptor instead.
+func (*SendPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SendPaymentResponse) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *SendPaymentResponse) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *SendPaymentResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type Invoice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaymentHash    []byte                 `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	Preimage       []byte                 `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	Amount         uint64                 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Memo           string                 `protobuf:"bytes,4,opt,name=memo,proto3" json:"memo,omitempty"`
+	State          Invoice_InvoiceState   `protobuf:"varint,5,opt,name=state,proto3,enum=Invoice_InvoiceState" json:"state,omitempty"`
+	CreationDate   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	Expiry         uint64                 `protobuf:"varint,7,opt,name=expiry,proto3" json:"expiry,omitempty"` // seconds after the creation date
+	SettleDate     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=settle_date,json=settleDate,proto3" json:"settle_date,omitempty"`
+	AmountPaid     uint64                 `protobuf:"varint,9,opt,name=amount_paid,json=amountPaid,proto3" json:"amount_paid,omitempty"`
+	PaymentRequest string                 `protobuf:"bytes,10,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+}
+
+func (x *Invoice) Reset() {
+	*x = Invoice{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Invoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Invoice) ProtoMessage() {}
+
+func (x *Invoice) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Invoice.ProtoReflect.Descriptor instead.
+func (*Invoice) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Invoice) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *Invoice) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *Invoice) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Invoice) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *Invoice) GetState() Invoice_InvoiceState {
+	if x != nil {
+		return x.State
+	}
+	return Invoice_OPEN
+}
+
+func (x *Invoice) GetCreationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationDate
+	}
+	return nil
+}
+
+func (x *Invoice) GetExpiry() uint64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+func (x *Invoice) GetSettleDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SettleDate
+	}
+	return nil
+}
+
+func (x *Invoice) GetAmountPaid() uint64 {
+	if x != nil {
+		return x.AmountPaid
+	}
+	return 0
+}
+
+func (x *Invoice) GetPaymentRequest() string {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return ""
+}
+
+type AddInvoiceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Amount uint64 `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Memo   string `protobuf:"bytes,2,opt,name=memo,proto3" json:"memo,omitempty"`
+	Expiry uint64 `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"` // seconds until the invoice expires
+}
+
+func (x *AddInvoiceRequest) Reset() {
+	*x = AddInvoiceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInvoiceRequest) ProtoMessage() {}
+
+func (x *AddInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*AddInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AddInvoiceRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AddInvoiceRequest) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *AddInvoiceRequest) GetExpiry() uint64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+type AddInvoiceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaymentHash      []byte            `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	PaymentRequest   string            `protobuf:"bytes,2,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *AddInvoiceResponse) Reset() {
+	*x = AddInvoiceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddInvoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInvoiceResponse) ProtoMessage() {}
+
+func (x *AddInvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInvoiceResponse.ProtoReflect.Descriptor instead.
+func (*AddInvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AddInvoiceResponse) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *AddInvoiceResponse) GetPaymentRequest() string {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return ""
+}
+
+func (x *AddInvoiceResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type LookupInvoiceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+}
+
+func (x *LookupInvoiceRequest) Reset() {
+	*x = LookupInvoiceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupInvoiceRequest) ProtoMessage() {}
+
+func (x *LookupInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*LookupInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LookupInvoiceRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+type LookupInvoiceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Invoice          *Invoice          `protobuf:"bytes,1,opt,name=invoice,proto3" json:"invoice,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *LookupInvoiceResponse) Reset() {
+	*x = LookupInvoiceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupInvoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupInvoiceResponse) ProtoMessage() {}
+
+func (x *LookupInvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupInvoiceResponse.ProtoReflect.Descriptor instead.
+func (*LookupInvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *LookupInvoiceResponse) GetInvoice() *Invoice {
+	if x != nil {
+		return x.Invoice
+	}
+	return nil
+}
+
+func (x *LookupInvoiceResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type ListInvoicesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListInvoicesRequest) Reset() {
+	*x = ListInvoicesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListInvoicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvoicesRequest) ProtoMessage() {}
+
+func (x *ListInvoicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvoicesRequest.ProtoReflect.Descriptor instead.
+func (*ListInvoicesRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{36}
+}
+
+type ListInvoicesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Invoices         []*Invoice        `protobuf:"bytes,1,rep,name=invoices,proto3" json:"invoices,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *ListInvoicesResponse) Reset() {
+	*x = ListInvoicesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListInvoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvoicesResponse) ProtoMessage() {}
+
+func (x *ListInvoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvoicesResponse.ProtoReflect.Descriptor instead.
+func (*ListInvoicesResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListInvoicesResponse) GetInvoices() []*Invoice {
+	if x != nil {
+		return x.Invoices
+	}
+	return nil
+}
+
+func (x *ListInvoicesResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type PayInvoiceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PaymentRequest string                     `protobuf:"bytes,1,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	Route          []*StateChannelNodeAddress `protobuf:"bytes,2,rep,name=route,proto3" json:"route,omitempty"` // optional, hops to the invoice node if there is no direct channel
+}
+
+func (x *PayInvoiceRequest) Reset() {
+	*x = PayInvoiceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayInvoiceRequest) ProtoMessage() {}
+
+func (x *PayInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*PayInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PayInvoiceRequest) GetPaymentRequest() string {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return ""
+}
+
+func (x *PayInvoiceRequest) GetRoute() []*StateChannelNodeAddress {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+type PayInvoiceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Preimage         []byte            `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	Fee              uint64            `protobuf:"varint,2,opt,name=fee,proto3" json:"fee,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *PayInvoiceResponse) Reset() {
+	*x = PayInvoiceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayInvoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayInvoiceResponse) ProtoMessage() {}
+
+func (x *PayInvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayInvoiceResponse.ProtoReflect.Descriptor instead.
+func (*PayInvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PayInvoiceResponse) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *PayInvoiceResponse) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *PayInvoiceResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type Payment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PartnerAddress string                 `protobuf:"bytes,1,opt,name=partner_address,json=partnerAddress,proto3" json:"partner_address,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Amount         uint64                 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Outgoing       bool                   `protobuf:"varint,4,opt,name=outgoing,proto3" json:"outgoing,omitempty"`
+	Memo           string                 `protobuf:"bytes,5,opt,name=memo,proto3" json:"memo,omitempty"`
+	AliceBalance   uint64                 `protobuf:"varint,6,opt,name=alice_balance,json=aliceBalance,proto3" json:"alice_balance,omitempty"`
+	BobBalance     uint64                 `protobuf:"varint,7,opt,name=bob_balance,json=bobBalance,proto3" json:"bob_balance,omitempty"`
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Payment) GetPartnerAddress() string {
+	if x != nil {
+		return x.PartnerAddress
+	}
+	return ""
+}
+
+func (x *Payment) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Payment) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetOutgoing() bool {
+	if x != nil {
+		return x.Outgoing
+	}
+	return false
+}
+
+func (x *Payment) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *Payment) GetAliceBalance() uint64 {
+	if x != nil {
+		return x.AliceBalance
+	}
+	return 0
+}
+
+func (x *Payment) GetBobBalance() uint64 {
+	if x != nil {
+		return x.BobBalance
+	}
+	return 0
+}
+
+type ListPaymentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"` // optional, only list payments of the channel with this partner
+}
+
+func (x *ListPaymentsRequest) Reset() {
+	*x = ListPaymentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsRequest) ProtoMessage() {}
+
+func (x *ListPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListPaymentsRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+type ListPaymentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Payments         []*Payment        `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *ListPaymentsResponse) Reset() {
+	*x = ListPaymentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsResponse) ProtoMessage() {}
+
+func (x *ListPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListPaymentsResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *ListPaymentsResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type DepositToChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	Amount      uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *DepositToChannelRequest) Reset() {
+	*x = DepositToChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepositToChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositToChannelRequest) ProtoMessage() {}
+
+func (x *DepositToChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositToChannelRequest.ProtoReflect.Descriptor instead.
+func (*DepositToChannelRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DepositToChannelRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *DepositToChannelRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type DepositToChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalDeposit     uint64            `protobuf:"varint,1,opt,name=total_deposit,json=totalDeposit,proto3" json:"total_deposit,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *DepositToChannelResponse) Reset() {
+	*x = DepositToChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepositToChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositToChannelResponse) ProtoMessage() {}
+
+func (x *DepositToChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositToChannelResponse.ProtoReflect.Descriptor instead.
+func (*DepositToChannelResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DepositToChannelResponse) GetTotalDeposit() uint64 {
+	if x != nil {
+		return x.TotalDeposit
+	}
+	return 0
+}
+
+func (x *DepositToChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type WithdrawFromChannelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"`
+	Amount      uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *WithdrawFromChannelRequest) Reset() {
+	*x = WithdrawFromChannelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WithdrawFromChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawFromChannelRequest) ProtoMessage() {}
+
+func (x *WithdrawFromChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawFromChannelRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawFromChannelRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *WithdrawFromChannelRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+func (x *WithdrawFromChannelRequest) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type WithdrawFromChannelResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalDeposit     uint64            `protobuf:"varint,1,opt,name=total_deposit,json=totalDeposit,proto3" json:"total_deposit,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *WithdrawFromChannelResponse) Reset() {
+	*x = WithdrawFromChannelResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WithdrawFromChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawFromChannelResponse) ProtoMessage() {}
+
+func (x *WithdrawFromChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawFromChannelResponse.ProtoReflect.Descriptor instead.
+func (*WithdrawFromChannelResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *WithdrawFromChannelResponse) GetTotalDeposit() uint64 {
+	if x != nil {
+		return x.TotalDeposit
+	}
+	return 0
+}
+
+func (x *WithdrawFromChannelResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type AbortChannelOpenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AppId uint64 `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+}
+
+func (x *AbortChannelOpenRequest) Reset() {
+	*x = AbortChannelOpenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AbortChannelOpenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortChannelOpenRequest) ProtoMessage() {}
+
+func (x *AbortChannelOpenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortChannelOpenRequest.ProtoReflect.Descriptor instead.
+func (*AbortChannelOpenRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *AbortChannelOpenRequest) GetAppId() uint64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+type AbortChannelOpenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,1,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *AbortChannelOpenResponse) Reset() {
+	*x = AbortChannelOpenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AbortChannelOpenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortChannelOpenResponse) ProtoMessage() {}
+
+func (x *AbortChannelOpenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortChannelOpenResponse.ProtoReflect.Descriptor instead.
+func (*AbortChannelOpenResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AbortChannelOpenResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type CleanupClosedChannelsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CleanupClosedChannelsRequest) Reset() {
+	*x = CleanupClosedChannelsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanupClosedChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupClosedChannelsRequest) ProtoMessage() {}
+
+func (x *CleanupClosedChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupClosedChannelsRequest.ProtoReflect.Descriptor instead.
+func (*CleanupClosedChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{49}
+}
+
+type CleanupClosedChannelsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeletedAppIds    []uint64          `protobuf:"varint,1,rep,packed,name=deleted_app_ids,json=deletedAppIds,proto3" json:"deleted_app_ids,omitempty"`
+	RuntimeRecording *RuntimeRecording `protobuf:"bytes,2,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *CleanupClosedChannelsResponse) Reset() {
+	*x = CleanupClosedChannelsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanupClosedChannelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupClosedChannelsResponse) ProtoMessage() {}
+
+func (x *CleanupClosedChannelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupClosedChannelsResponse.ProtoReflect.Descriptor instead.
+func (*CleanupClosedChannelsResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CleanupClosedChannelsResponse) GetDeletedAppIds() []uint64 {
+	if x != nil {
+		return x.DeletedAppIds
+	}
+	return nil
+}
+
+func (x *CleanupClosedChannelsResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
+}
+
+type ChannelFee struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AppId          uint64 `protobuf:"varint,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	PartnerAddress string `protobuf:"bytes,2,opt,name=partner_address,json=partnerAddress,proto3" json:"partner_address,omitempty"`
+	BlockchainFee  uint64 `protobuf:"varint,3,opt,name=blockchain_fee,json=blockchainFee,proto3" json:"blockchain_fee,omitempty"` // cumulative microalgos I paid for on-chain operations of the channel
+}
+
+func (x *ChannelFee) Reset() {
+	*x = ChannelFee{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelFee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelFee) ProtoMessage() {}
+
+func (x *ChannelFee) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelFee.ProtoReflect.Descriptor instead.
+func (*ChannelFee) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ChannelFee) GetAppId() uint64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *ChannelFee) GetPartnerAddress() string {
+	if x != nil {
+		return x.PartnerAddress
+	}
+	return ""
+}
+
+func (x *ChannelFee) GetBlockchainFee() uint64 {
+	if x != nil {
+		return x.BlockchainFee
+	}
+	return 0
+}
+
+type ListChannelFeesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AlgoAddress string `protobuf:"bytes,1,opt,name=algo_address,json=algoAddress,proto3" json:"algo_address,omitempty"` // optional, only list fees of the channel with this partner
+}
+
+func (x *ListChannelFeesRequest) Reset() {
+	*x = ListChannelFeesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChannelFeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChannelFeesRequest) ProtoMessage() {}
+
+func (x *ListChannelFeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChannelFeesRequest.ProtoReflect.Descriptor instead.
+func (*ListChannelFeesRequest) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListChannelFeesRequest) GetAlgoAddress() string {
+	if x != nil {
+		return x.AlgoAddress
+	}
+	return ""
+}
+
+type ListChannelFeesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelFees        []*ChannelFee     `protobuf:"bytes,1,rep,name=channel_fees,json=channelFees,proto3" json:"channel_fees,omitempty"`
+	TotalBlockchainFee uint64            `protobuf:"varint,2,opt,name=total_blockchain_fee,json=totalBlockchainFee,proto3" json:"total_blockchain_fee,omitempty"`
+	RuntimeRecording   *RuntimeRecording `protobuf:"bytes,3,opt,name=runtime_recording,json=runtimeRecording,proto3" json:"runtime_recording,omitempty"`
+}
+
+func (x *ListChannelFeesResponse) Reset() {
+	*x = ListChannelFeesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_asrpc_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChannelFeesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChannelFeesResponse) ProtoMessage() {}
+
+func (x *ListChannelFeesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_asrpc_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChannelFeesResponse.ProtoReflect.Descriptor instead.
+func (*ListChannelFeesResponse) Descriptor() ([]byte, []int) {
+	return file_asrpc_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListChannelFeesResponse) GetChannelFees() []*ChannelFee {
+	if x != nil {
+		return x.ChannelFees
+	}
+	return nil
+}
+
+func (x *ListChannelFeesResponse) GetTotalBlockchainFee() uint64 {
+	if x != nil {
+		return x.TotalBlockchainFee
+	}
+	return 0
+}
+
+func (x *ListChannelFeesResponse) GetRuntimeRecording() *RuntimeRecording {
+	if x != nil {
+		return x.RuntimeRecording
+	}
+	return nil
 }
 
 var File_asrpc_proto protoreflect.FileDescriptor
@@ -940,7 +3111,7 @@ var file_asrpc_proto_rawDesc = []byte{
 	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x21, 0x0a,
 	0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x22, 0xbf, 0x01, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
+	0x22, 0xec, 0x01, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
 	0x72, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x43, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
 	0x6d, 0x70, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
 	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
@@ -952,52 +3123,96 @@ var file_asrpc_proto_rawDesc = []byte{
 	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x45, 0x6e, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x62,
 	0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x04, 0x52, 0x0d, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x46,
-	0x65, 0x65, 0x22, 0x0e, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0x4f, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
-	0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e,
-	0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x69, 0x6e, 0x67, 0x22, 0x10, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x97, 0x01, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67,
-	0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c,
-	0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
-	0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e,
-	0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72,
-	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22,
-	0xc8, 0x01, 0x0a, 0x12, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3b, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x6e, 0x65,
-	0x72, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x41,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x6e, 0x65, 0x72, 0x4e,
-	0x6f, 0x64, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x66, 0x75, 0x6e,
-	0x64, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x65,
-	0x6e, 0x61, 0x6c, 0x74, 0x79, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x0e, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x5f, 0x77,
-	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x64, 0x69, 0x73,
-	0x70, 0x75, 0x74, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x22, 0x6c, 0x0a, 0x13, 0x4f, 0x70,
-	0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x15, 0x0a, 0x06, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x05, 0x61, 0x70, 0x70, 0x49, 0x64, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74,
-	0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x47, 0x0a, 0x0a, 0x50, 0x61, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c,
-	0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
-	0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
-	0x74, 0x22, 0x4d, 0x0a, 0x0b, 0x50, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f,
-	0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75,
-	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10,
-	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x65, 0x65, 0x12, 0x2b, 0x0a, 0x0b, 0x70, 0x68, 0x61, 0x73, 0x65, 0x5f, 0x73, 0x70, 0x61, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x50, 0x68, 0x61, 0x73, 0x65, 0x53,
+	0x70, 0x61, 0x6e, 0x52, 0x0a, 0x70, 0x68, 0x61, 0x73, 0x65, 0x53, 0x70, 0x61, 0x6e, 0x73, 0x22,
+	0xa5, 0x01, 0x0a, 0x09, 0x50, 0x68, 0x61, 0x73, 0x65, 0x53, 0x70, 0x61, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x43, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x3f, 0x0a, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x45, 0x6e, 0x64, 0x22, 0x47, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x6e, 0x65, 0x6d, 0x6f, 0x6e, 0x69, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x6e, 0x65, 0x6d, 0x6f, 0x6e, 0x69, 0x63,
+	0x22, 0x8f, 0x01, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x6e, 0x65, 0x6d, 0x6f, 0x6e,
+	0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x6e, 0x65, 0x6d, 0x6f, 0x6e,
+	0x69, 0x63, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x22, 0x2b, 0x0a, 0x0d, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22,
+	0x73, 0x0a, 0x0e, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x22, 0x0e, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x4f, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x10, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x97, 0x01, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x61,
+	0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x67, 0x22, 0xe3, 0x01, 0x0a, 0x12, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3b, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74,
+	0x6e, 0x65, 0x72, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4e, 0x6f, 0x64,
+	0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x6e, 0x65,
+	0x72, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x66,
+	0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f,
+	0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x5f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x52, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65,
+	0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x64,
+	0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x19, 0x0a, 0x08,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x22, 0x6c, 0x0a, 0x13, 0x4f, 0x70, 0x65, 0x6e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x15,
+	0x0a, 0x06, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
+	0x61, 0x70, 0x70, 0x49, 0x64, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x7e, 0x0a, 0x0a, 0x50, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x6d, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6d, 0x65, 0x6d, 0x6f, 0x22, 0x69, 0x0a, 0x0b, 0x50, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
 	0x22, 0x43, 0x0a, 0x1e, 0x43, 0x6f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43,
 	0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65,
@@ -1037,44 +3252,340 @@ var file_asrpc_proto_rawDesc = []byte{
 	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52,
 	0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
-	0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x32, 0x88, 0x04, 0x0a, 0x05, 0x41,
-	0x53, 0x52, 0x50, 0x43, 0x12, 0x28, 0x0a, 0x05, 0x52, 0x65, 0x73, 0x65, 0x74, 0x12, 0x0d, 0x2e,
-	0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x52,
-	0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2e,
-	0x0a, 0x07, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0f, 0x2e, 0x47, 0x65, 0x74, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x47, 0x65, 0x74,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3a,
-	0x0a, 0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x13, 0x2e,
-	0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x22, 0x0a, 0x03, 0x50, 0x61,
-	0x79, 0x12, 0x0b, 0x2e, 0x50, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c,
-	0x2e, 0x50, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5e,
-	0x0a, 0x17, 0x43, 0x6f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6c, 0x6f,
-	0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1f, 0x2e, 0x43, 0x6f, 0x6f, 0x70,
+	0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x86, 0x01, 0x0a, 0x0e, 0x41,
+	0x64, 0x64, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x79, 0x22, 0x51, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x52, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65,
+	0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61,
+	0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0x54, 0x0a, 0x12, 0x53, 0x65,
+	0x74, 0x74, 0x6c, 0x65, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10,
+	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x22, 0x57, 0x0a, 0x0f, 0x46, 0x61, 0x69, 0x6c, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x52, 0x0a, 0x10, 0x46, 0x61, 0x69,
+	0x6c, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a,
+	0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0xd3, 0x01,
+	0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a,
+	0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68,
+	0x12, 0x21, 0x0a, 0x0c, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x45, 0x78, 0x70,
+	0x69, 0x72, 0x79, 0x22, 0x83, 0x01, 0x0a, 0x13, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70,
+	0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x66, 0x65, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x66, 0x65, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0xb5, 0x03, 0x0a, 0x07, 0x49, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6d, 0x65, 0x6d, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x65, 0x6d, 0x6f,
+	0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3f, 0x0a,
+	0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x3b, 0x0a, 0x0b, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x44,
+	0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x61,
+	0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x50, 0x61, 0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a,
+	0x0c, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x08, 0x0a,
+	0x04, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x45, 0x54, 0x54, 0x4c,
+	0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x10,
+	0x02, 0x22, 0x57, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6d, 0x65, 0x6d, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x65,
+	0x6d, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x22, 0xa0, 0x01, 0x0a, 0x12, 0x41,
+	0x64, 0x64, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3e, 0x0a,
+	0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x39, 0x0a,
+	0x14, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x7b, 0x0a, 0x15, 0x4c, 0x6f, 0x6f, 0x6b,
+	0x75, 0x70, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x22, 0x0a, 0x07, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x08, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x07, 0x69, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x15, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x7c, 0x0a, 0x14,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x08, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x52, 0x08, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x6c, 0x0a, 0x11, 0x50, 0x61,
+	0x79, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x27, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0x82, 0x01, 0x0a, 0x12, 0x50, 0x61, 0x79,
+	0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x66,
+	0x65, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x66, 0x65, 0x65, 0x12, 0x3e, 0x0a,
+	0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0xfa, 0x01,
+	0x0a, 0x07, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x72,
+	0x74, 0x6e, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x72, 0x74, 0x6e, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
+	0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x6d, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6d, 0x65, 0x6d, 0x6f, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x69, 0x63, 0x65, 0x5f, 0x62, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x61, 0x6c, 0x69,
+	0x63, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6f, 0x62,
+	0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x62, 0x6f, 0x62, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x38, 0x0a, 0x13, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x22, 0x7c, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x08,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08,
+	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x22, 0x54, 0x0a, 0x17, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x54, 0x6f, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x7f, 0x0a, 0x18, 0x44, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x57, 0x0a, 0x1a, 0x57, 0x69, 0x74,
+	0x68, 0x64, 0x72, 0x61, 0x77, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61,
+	0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x1b, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x46,
+	0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x64, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x30, 0x0a, 0x17, 0x41, 0x62, 0x6f, 0x72, 0x74,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x61, 0x70, 0x70, 0x49, 0x64, 0x22, 0x5a, 0x0a, 0x18, 0x41, 0x62, 0x6f,
+	0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x1e, 0x0a, 0x1c, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x75, 0x70,
+	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x87, 0x01, 0x0a, 0x1d, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x75,
+	0x70, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04,
+	0x52, 0x0d, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x70, 0x70, 0x49, 0x64, 0x73, 0x12,
+	0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x22,
+	0x73, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65, 0x12, 0x15, 0x0a,
+	0x06, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x61,
+	0x70, 0x70, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x6e, 0x65, 0x72, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70,
+	0x61, 0x72, 0x74, 0x6e, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x25, 0x0a,
+	0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x46, 0x65, 0x65, 0x22, 0x3b, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x61, 0x6c, 0x67, 0x6f, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x67, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x22, 0xbb, 0x01, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a,
+	0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x66, 0x65, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65,
+	0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65, 0x73, 0x12, 0x30, 0x0a,
+	0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69,
+	0x6e, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x46, 0x65, 0x65, 0x12,
+	0x3e, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x52, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x72,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x32,
+	0xf8, 0x0b, 0x0a, 0x05, 0x41, 0x53, 0x52, 0x50, 0x43, 0x12, 0x2b, 0x0a, 0x06, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x12, 0x0e, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2b, 0x0a, 0x06, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x0e, 0x2e, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0f, 0x2e, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x28, 0x0a, 0x05, 0x52, 0x65, 0x73, 0x65, 0x74, 0x12, 0x0d, 0x2e, 0x52,
+	0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x52, 0x65,
+	0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2e, 0x0a,
+	0x07, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0f, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3a, 0x0a,
+	0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x13, 0x2e, 0x4f,
+	0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x14, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x22, 0x0a, 0x03, 0x50, 0x61, 0x79,
+	0x12, 0x0b, 0x2e, 0x50, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e,
+	0x50, 0x61, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a,
+	0x17, 0x43, 0x6f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6c, 0x6f, 0x73,
+	0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1f, 0x2e, 0x43, 0x6f, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x43, 0x6f, 0x6f, 0x70,
 	0x65, 0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x43, 0x6f, 0x6f,
-	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x76, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55,
-	0x0a, 0x14, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74,
-	0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x43,
-	0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x14, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x2e,
-	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x46, 0x69,
-	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a,
-	0x54, 0x72, 0x79, 0x54, 0x6f, 0x43, 0x68, 0x65, 0x61, 0x74, 0x12, 0x12, 0x2e, 0x54, 0x72, 0x79,
-	0x54, 0x6f, 0x43, 0x68, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
-	0x2e, 0x54, 0x72, 0x79, 0x54, 0x6f, 0x43, 0x68, 0x65, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x61, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x79, 0x2f, 0x61, 0x6c,
-	0x67, 0x6f, 0x72, 0x61, 0x6e, 0x64, 0x2d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2d, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x2f, 0x61, 0x73, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a,
+	0x14, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65,
+	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x14, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x2e, 0x46,
+	0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a, 0x54,
+	0x72, 0x79, 0x54, 0x6f, 0x43, 0x68, 0x65, 0x61, 0x74, 0x12, 0x12, 0x2e, 0x54, 0x72, 0x79, 0x54,
+	0x6f, 0x43, 0x68, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x54, 0x72, 0x79, 0x54, 0x6f, 0x43, 0x68, 0x65, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x48, 0x54, 0x4c, 0x43, 0x12,
+	0x0f, 0x2e, 0x41, 0x64, 0x64, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x10, 0x2e, 0x41, 0x64, 0x64, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x48, 0x54,
+	0x4c, 0x43, 0x12, 0x12, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x48, 0x54, 0x4c, 0x43, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x48,
+	0x54, 0x4c, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x31, 0x0a,
+	0x08, 0x46, 0x61, 0x69, 0x6c, 0x48, 0x54, 0x4c, 0x43, 0x12, 0x10, 0x2e, 0x46, 0x61, 0x69, 0x6c,
+	0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x46, 0x61,
+	0x69, 0x6c, 0x48, 0x54, 0x4c, 0x43, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x3a, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x13, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a,
+	0x41, 0x64, 0x64, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x12, 0x2e, 0x41, 0x64, 0x64,
+	0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0d, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x49,
+	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x15, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x49,
+	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x49,
+	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x14, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0a, 0x50, 0x61, 0x79, 0x49, 0x6e, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x12, 0x12, 0x2e, 0x50, 0x61, 0x79, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x50, 0x61, 0x79, 0x49, 0x6e,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x3d, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x14, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49,
+	0x0a, 0x10, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x12, 0x18, 0x2e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x54, 0x6f, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x44,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x13, 0x57, 0x69, 0x74,
+	0x68, 0x64, 0x72, 0x61, 0x77, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x12, 0x1b, 0x2e, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x46, 0x72, 0x6f, 0x6d, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a,
+	0x10, 0x41, 0x62, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4f, 0x70, 0x65,
+	0x6e, 0x12, 0x18, 0x2e, 0x41, 0x62, 0x6f, 0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x41, 0x62,
+	0x6f, 0x72, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x15, 0x43, 0x6c, 0x65, 0x61,
+	0x6e, 0x75, 0x70, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x73, 0x12, 0x1d, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x75, 0x70, 0x43, 0x6c, 0x6f, 0x73, 0x65,
+	0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1e, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x75, 0x70, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x46, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x46, 0x65, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x46, 0x65, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x61, 0x6e, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x79, 0x2f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x61, 0x6e, 0x64, 0x2d, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x2d, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x2f, 0x61, 0x73, 0x72, 0x70, 0x63,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1089,61 +3600,160 @@ func file_asrpc_proto_rawDescGZIP() []byte {
 	return file_asrpc_proto_rawDescData
 }
 
-var file_asrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_asrpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_asrpc_proto_msgTypes = make([]protoimpl.MessageInfo, 54)
 var file_asrpc_proto_goTypes = []interface{}{
-	(*StateChannelNodeAddress)(nil),         // 0: StateChannelNodeAddress
-	(*RuntimeRecording)(nil),                // 1: RuntimeRecording
-	(*ResetRequest)(nil),                    // 2: ResetRequest
-	(*ResetResponse)(nil),                   // 3: ResetResponse
-	(*GetInfoRequest)(nil),                  // 4: GetInfoRequest
-	(*GetInfoResponse)(nil),                 // 5: GetInfoResponse
-	(*OpenChannelRequest)(nil),              // 6: OpenChannelRequest
-	(*OpenChannelResponse)(nil),             // 7: OpenChannelResponse
-	(*PayRequest)(nil),                      // 8: PayRequest
-	(*PayResponse)(nil),                     // 9: PayResponse
-	(*CooperativeCloseChannelRequest)(nil),  // 10: CooperativeCloseChannelRequest
-	(*CooperativeCloseChannelResponse)(nil), // 11: CooperativeCloseChannelResponse
-	(*InitiateCloseChannelRequest)(nil),     // 12: InitiateCloseChannelRequest
-	(*InitiateCloseChannelResponse)(nil),    // 13: InitiateCloseChannelResponse
-	(*FinalizeCloseChannelRequest)(nil),     // 14: FinalizeCloseChannelRequest
-	(*FinalizeCloseChannelResponse)(nil),    // 15: FinalizeCloseChannelResponse
-	(*TryToCheatRequest)(nil),               // 16: TryToCheatRequest
-	(*TryToCheatResponse)(nil),              // 17: TryToCheatResponse
-	(*timestamppb.Timestamp)(nil),           // 18: google.protobuf.Timestamp
+	(Invoice_InvoiceState)(0),               // 0: Invoice.InvoiceState
+	(*StateChannelNodeAddress)(nil),         // 1: StateChannelNodeAddress
+	(*RuntimeRecording)(nil),                // 2: RuntimeRecording
+	(*PhaseSpan)(nil),                       // 3: PhaseSpan
+	(*CreateRequest)(nil),                   // 4: CreateRequest
+	(*CreateResponse)(nil),                  // 5: CreateResponse
+	(*UnlockRequest)(nil),                   // 6: UnlockRequest
+	(*UnlockResponse)(nil),                  // 7: UnlockResponse
+	(*ResetRequest)(nil),                    // 8: ResetRequest
+	(*ResetResponse)(nil),                   // 9: ResetResponse
+	(*GetInfoRequest)(nil),                  // 10: GetInfoRequest
+	(*GetInfoResponse)(nil),                 // 11: GetInfoResponse
+	(*OpenChannelRequest)(nil),              // 12: OpenChannelRequest
+	(*OpenChannelResponse)(nil),             // 13: OpenChannelResponse
+	(*PayRequest)(nil),                      // 14: PayRequest
+	(*PayResponse)(nil),                     // 15: PayResponse
+	(*CooperativeCloseChannelRequest)(nil),  // 16: CooperativeCloseChannelRequest
+	(*CooperativeCloseChannelResponse)(nil), // 17: CooperativeCloseChannelResponse
+	(*InitiateCloseChannelRequest)(nil),     // 18: InitiateCloseChannelRequest
+	(*InitiateCloseChannelResponse)(nil),    // 19: InitiateCloseChannelResponse
+	(*FinalizeCloseChannelRequest)(nil),     // 20: FinalizeCloseChannelRequest
+	(*FinalizeCloseChannelResponse)(nil),    // 21: FinalizeCloseChannelResponse
+	(*TryToCheatRequest)(nil),               // 22: TryToCheatRequest
+	(*TryToCheatResponse)(nil),              // 23: TryToCheatResponse
+	(*AddHTLCRequest)(nil),                  // 24: AddHTLCRequest
+	(*AddHTLCResponse)(nil),                 // 25: AddHTLCResponse
+	(*SettleHTLCRequest)(nil),               // 26: SettleHTLCRequest
+	(*SettleHTLCResponse)(nil),              // 27: SettleHTLCResponse
+	(*FailHTLCRequest)(nil),                 // 28: FailHTLCRequest
+	(*FailHTLCResponse)(nil),                // 29: FailHTLCResponse
+	(*SendPaymentRequest)(nil),              // 30: SendPaymentRequest
+	(*SendPaymentResponse)(nil),             // 31: SendPaymentResponse
+	(*Invoice)(nil),                         // 32: Invoice
+	(*AddInvoiceRequest)(nil),               // 33: AddInvoiceRequest
+	(*AddInvoiceResponse)(nil),              // 34: AddInvoiceResponse
+	(*LookupInvoiceRequest)(nil),            // 35: LookupInvoiceRequest
+	(*LookupInvoiceResponse)(nil),           // 36: LookupInvoiceResponse
+	(*ListInvoicesRequest)(nil),             // 37: ListInvoicesRequest
+	(*ListInvoicesResponse)(nil),            // 38: ListInvoicesResponse
+	(*PayInvoiceRequest)(nil),               // 39: PayInvoiceRequest
+	(*PayInvoiceResponse)(nil),              // 40: PayInvoiceResponse
+	(*Payment)(nil),                         // 41: Payment
+	(*ListPaymentsRequest)(nil),             // 42: ListPaymentsRequest
+	(*ListPaymentsResponse)(nil),            // 43: ListPaymentsResponse
+	(*DepositToChannelRequest)(nil),         // 44: DepositToChannelRequest
+	(*DepositToChannelResponse)(nil),        // 45: DepositToChannelResponse
+	(*WithdrawFromChannelRequest)(nil),      // 46: WithdrawFromChannelRequest
+	(*WithdrawFromChannelResponse)(nil),     // 47: WithdrawFromChannelResponse
+	(*AbortChannelOpenRequest)(nil),         // 48: AbortChannelOpenRequest
+	(*AbortChannelOpenResponse)(nil),        // 49: AbortChannelOpenResponse
+	(*CleanupClosedChannelsRequest)(nil),    // 50: CleanupClosedChannelsRequest
+	(*CleanupClosedChannelsResponse)(nil),   // 51: CleanupClosedChannelsResponse
+	(*ChannelFee)(nil),                      // 52: ChannelFee
+	(*ListChannelFeesRequest)(nil),          // 53: ListChannelFeesRequest
+	(*ListChannelFeesResponse)(nil),         // 54: ListChannelFeesResponse
+	(*timestamppb.Timestamp)(nil),           // 55: google.protobuf.Timestamp
 }
 var file_asrpc_proto_depIdxs = []int32{
-	18, // 0: RuntimeRecording.timestamp_start:type_name -> google.protobuf.Timestamp
-	18, // 1: RuntimeRecording.timestamp_end:type_name -> google.protobuf.Timestamp
-	1,  // 2: ResetResponse.runtime_recording:type_name -> RuntimeRecording
-	1,  // 3: GetInfoResponse.runtime_recording:type_name -> RuntimeRecording
-	0,  // 4: OpenChannelRequest.partner_node:type_name -> StateChannelNodeAddress
-	1,  // 5: OpenChannelResponse.runtime_recording:type_name -> RuntimeRecording
-	1,  // 6: PayResponse.runtime_recording:type_name -> RuntimeRecording
-	1,  // 7: CooperativeCloseChannelResponse.runtime_recording:type_name -> RuntimeRecording
-	1,  // 8: InitiateCloseChannelResponse.runtime_recording:type_name -> RuntimeRecording
-	1,  // 9: FinalizeCloseChannelResponse.runtime_recording:type_name -> RuntimeRecording
-	1,  // 10: TryToCheatResponse.runtime_recording:type_name -> RuntimeRecording
-	2,  // 11: ASRPC.Reset:input_type -> ResetRequest
-	4,  // 12: ASRPC.GetInfo:input_type -> GetInfoRequest
-	6,  // 13: ASRPC.OpenChannel:input_type -> OpenChannelRequest
-	8,  // 14: ASRPC.Pay:input_type -> PayRequest
-	10, // 15: ASRPC.CooperativeCloseChannel:input_type -> CooperativeCloseChannelRequest
-	12, // 16: ASRPC.InitiateCloseChannel:input_type -> InitiateCloseChannelRequest
-	14, // 17: ASRPC.FinalizeCloseChannel:input_type -> FinalizeCloseChannelRequest
-	16, // 18: ASRPC.TryToCheat:input_type -> TryToCheatRequest
-	3,  // 19: ASRPC.Reset:output_type -> ResetResponse
-	5,  // 20: ASRPC.GetInfo:output_type -> GetInfoResponse
-	7,  // 21: ASRPC.OpenChannel:output_type -> OpenChannelResponse
-	9,  // 22: ASRPC.Pay:output_type -> PayResponse
-	11, // 23: ASRPC.CooperativeCloseChannel:output_type -> CooperativeCloseChannelResponse
-	13, // 24: ASRPC.InitiateCloseChannel:output_type -> InitiateCloseChannelResponse
-	15, // 25: ASRPC.FinalizeCloseChannel:output_type -> FinalizeCloseChannelResponse
-	17, // 26: ASRPC.TryToCheat:output_type -> TryToCheatResponse
-	19, // [19:27] is the sub-list for method output_type
-	11, // [11:19] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	55, // 0: RuntimeRecording.timestamp_start:type_name -> google.protobuf.Timestamp
+	55, // 1: RuntimeRecording.timestamp_end:type_name -> google.protobuf.Timestamp
+	3,  // 2: RuntimeRecording.phase_spans:type_name -> PhaseSpan
+	55, // 3: PhaseSpan.timestamp_start:type_name -> google.protobuf.Timestamp
+	55, // 4: PhaseSpan.timestamp_end:type_name -> google.protobuf.Timestamp
+	2,  // 5: CreateResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 6: UnlockResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 7: ResetResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 8: GetInfoResponse.runtime_recording:type_name -> RuntimeRecording
+	1,  // 9: OpenChannelRequest.partner_node:type_name -> StateChannelNodeAddress
+	2,  // 10: OpenChannelResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 11: PayResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 12: CooperativeCloseChannelResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 13: InitiateCloseChannelResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 14: FinalizeCloseChannelResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 15: TryToCheatResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 16: AddHTLCResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 17: SettleHTLCResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 18: FailHTLCResponse.runtime_recording:type_name -> RuntimeRecording
+	1,  // 19: SendPaymentRequest.route:type_name -> StateChannelNodeAddress
+	2,  // 20: SendPaymentResponse.runtime_recording:type_name -> RuntimeRecording
+	0,  // 21: Invoice.state:type_name -> Invoice.InvoiceState
+	55, // 22: Invoice.creation_date:type_name -> google.protobuf.Timestamp
+	55, // 23: Invoice.settle_date:type_name -> google.protobuf.Timestamp
+	2,  // 24: AddInvoiceResponse.runtime_recording:type_name -> RuntimeRecording
+	32, // 25: LookupInvoiceResponse.invoice:type_name -> Invoice
+	2,  // 26: LookupInvoiceResponse.runtime_recording:type_name -> RuntimeRecording
+	32, // 27: ListInvoicesResponse.invoices:type_name -> Invoice
+	2,  // 28: ListInvoicesResponse.runtime_recording:type_name -> RuntimeRecording
+	1,  // 29: PayInvoiceRequest.route:type_name -> StateChannelNodeAddress
+	2,  // 30: PayInvoiceResponse.runtime_recording:type_name -> RuntimeRecording
+	55, // 31: Payment.timestamp:type_name -> google.protobuf.Timestamp
+	41, // 32: ListPaymentsResponse.payments:type_name -> Payment
+	2,  // 33: ListPaymentsResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 34: DepositToChannelResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 35: WithdrawFromChannelResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 36: AbortChannelOpenResponse.runtime_recording:type_name -> RuntimeRecording
+	2,  // 37: CleanupClosedChannelsResponse.runtime_recording:type_name -> RuntimeRecording
+	52, // 38: ListChannelFeesResponse.channel_fees:type_name -> ChannelFee
+	2,  // 39: ListChannelFeesResponse.runtime_recording:type_name -> RuntimeRecording
+	4,  // 40: ASRPC.Create:input_type -> CreateRequest
+	6,  // 41: ASRPC.Unlock:input_type -> UnlockRequest
+	8,  // 42: ASRPC.Reset:input_type -> ResetRequest
+	10, // 43: ASRPC.GetInfo:input_type -> GetInfoRequest
+	12, // 44: ASRPC.OpenChannel:input_type -> OpenChannelRequest
+	14, // 45: ASRPC.Pay:input_type -> PayRequest
+	16, // 46: ASRPC.CooperativeCloseChannel:input_type -> CooperativeCloseChannelRequest
+	18, // 47: ASRPC.InitiateCloseChannel:input_type -> InitiateCloseChannelRequest
+	20, // 48: ASRPC.FinalizeCloseChannel:input_type -> FinalizeCloseChannelRequest
+	22, // 49: ASRPC.TryToCheat:input_type -> TryToCheatRequest
+	24, // 50: ASRPC.AddHTLC:input_type -> AddHTLCRequest
+	26, // 51: ASRPC.SettleHTLC:input_type -> SettleHTLCRequest
+	28, // 52: ASRPC.FailHTLC:input_type -> FailHTLCRequest
+	30, // 53: ASRPC.SendPayment:input_type -> SendPaymentRequest
+	33, // 54: ASRPC.AddInvoice:input_type -> AddInvoiceRequest
+	35, // 55: ASRPC.LookupInvoice:input_type -> LookupInvoiceRequest
+	37, // 56: ASRPC.ListInvoices:input_type -> ListInvoicesRequest
+	39, // 57: ASRPC.PayInvoice:input_type -> PayInvoiceRequest
+	42, // 58: ASRPC.ListPayments:input_type -> ListPaymentsRequest
+	44, // 59: ASRPC.DepositToChannel:input_type -> DepositToChannelRequest
+	46, // 60: ASRPC.WithdrawFromChannel:input_type -> WithdrawFromChannelRequest
+	48, // 61: ASRPC.AbortChannelOpen:input_type -> AbortChannelOpenRequest
+	50, // 62: ASRPC.CleanupClosedChannels:input_type -> CleanupClosedChannelsRequest
+	53, // 63: ASRPC.ListChannelFees:input_type -> ListChannelFeesRequest
+	5,  // 64: ASRPC.Create:output_type -> CreateResponse
+	7,  // 65: ASRPC.Unlock:output_type -> UnlockResponse
+	9,  // 66: ASRPC.Reset:output_type -> ResetResponse
+	11, // 67: ASRPC.GetInfo:output_type -> GetInfoResponse
+	13, // 68: ASRPC.OpenChannel:output_type -> OpenChannelResponse
+	15, // 69: ASRPC.Pay:output_type -> PayResponse
+	17, // 70: ASRPC.CooperativeCloseChannel:output_type -> CooperativeCloseChannelResponse
+	19, // 71: ASRPC.InitiateCloseChannel:output_type -> InitiateCloseChannelResponse
+	21, // 72: ASRPC.FinalizeCloseChannel:output_type -> FinalizeCloseChannelResponse
+	23, // 73: ASRPC.TryToCheat:output_type -> TryToCheatResponse
+	25, // 74: ASRPC.AddHTLC:output_type -> AddHTLCResponse
+	27, // 75: ASRPC.SettleHTLC:output_type -> SettleHTLCResponse
+	29, // 76: ASRPC.FailHTLC:output_type -> FailHTLCResponse
+	31, // 77: ASRPC.SendPayment:output_type -> SendPaymentResponse
+	34, // 78: ASRPC.AddInvoice:output_type -> AddInvoiceResponse
+	36, // 79: ASRPC.LookupInvoice:output_type -> LookupInvoiceResponse
+	38, // 80: ASRPC.ListInvoices:output_type -> ListInvoicesResponse
+	40, // 81: ASRPC.PayInvoice:output_type -> PayInvoiceResponse
+	43, // 82: ASRPC.ListPayments:output_type -> ListPaymentsResponse
+	45, // 83: ASRPC.DepositToChannel:output_type -> DepositToChannelResponse
+	47, // 84: ASRPC.WithdrawFromChannel:output_type -> WithdrawFromChannelResponse
+	49, // 85: ASRPC.AbortChannelOpen:output_type -> AbortChannelOpenResponse
+	51, // 86: ASRPC.CleanupClosedChannels:output_type -> CleanupClosedChannelsResponse
+	54, // 87: ASRPC.ListChannelFees:output_type -> ListChannelFeesResponse
+	64, // [64:88] is the sub-list for method output_type
+	40, // [40:64] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
 }
 
 func init() { file_asrpc_proto_init() }
@@ -1164,8 +3774,188 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuntimeRecording); i {
+		file_asrpc_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeRecording); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PhaseSpan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnlockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnlockResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenChannelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PayRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PayResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CooperativeCloseChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CooperativeCloseChannelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1176,8 +3966,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResetRequest); i {
+		file_asrpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitiateCloseChannelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1188,8 +3978,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResetResponse); i {
+		file_asrpc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitiateCloseChannelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1200,8 +3990,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInfoRequest); i {
+		file_asrpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeCloseChannelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1212,8 +4002,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInfoResponse); i {
+		file_asrpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeCloseChannelResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1224,8 +4014,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OpenChannelRequest); i {
+		file_asrpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TryToCheatRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1236,8 +4026,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OpenChannelResponse); i {
+		file_asrpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TryToCheatResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1248,8 +4038,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PayRequest); i {
+		file_asrpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddHTLCRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1260,8 +4050,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PayResponse); i {
+		file_asrpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddHTLCResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1272,8 +4062,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CooperativeCloseChannelRequest); i {
+		file_asrpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettleHTLCRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1284,8 +4074,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CooperativeCloseChannelResponse); i {
+		file_asrpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettleHTLCResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1296,8 +4086,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitiateCloseChannelRequest); i {
+		file_asrpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailHTLCRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1308,8 +4098,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitiateCloseChannelResponse); i {
+		file_asrpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FailHTLCResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1320,8 +4110,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalizeCloseChannelRequest); i {
+		file_asrpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendPaymentRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1332,8 +4122,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalizeCloseChannelResponse); i {
+		file_asrpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendPaymentResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1344,8 +4134,8 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TryToCheatRequest); i {
+		file_asrpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Invoice); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1356,8 +4146,260 @@ func file_asrpc_proto_init() {
 				return nil
 			}
 		}
-		file_asrpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TryToCheatResponse); i {
+		file_asrpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInvoiceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInvoiceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupInvoiceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LookupInvoiceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListInvoicesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListInvoicesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PayInvoiceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PayInvoiceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Payment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPaymentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPaymentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepositToChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepositToChannelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WithdrawFromChannelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WithdrawFromChannelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AbortChannelOpenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AbortChannelOpenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanupClosedChannelsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanupClosedChannelsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelFee); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListChannelFeesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_asrpc_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListChannelFeesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1374,13 +4416,14 @@ func file_asrpc_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_asrpc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   18,
+			NumEnums:      1,
+			NumMessages:   54,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_asrpc_proto_goTypes,
 		DependencyIndexes: file_asrpc_proto_depIdxs,
+		EnumInfos:         file_asrpc_proto_enumTypes,
 		MessageInfos:      file_asrpc_proto_msgTypes,
 	}.Build()
 	File_asrpc_proto = out.File
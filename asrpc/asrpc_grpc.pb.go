@@ -22,6 +22,8 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ASRPCClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error)
 	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error)
 	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
 	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*OpenChannelResponse, error)
@@ -30,6 +32,20 @@ type ASRPCClient interface {
 	InitiateCloseChannel(ctx context.Context, in *InitiateCloseChannelRequest, opts ...grpc.CallOption) (*InitiateCloseChannelResponse, error)
 	FinalizeCloseChannel(ctx context.Context, in *FinalizeCloseChannelRequest, opts ...grpc.CallOption) (*FinalizeCloseChannelResponse, error)
 	TryToCheat(ctx context.Context, in *TryToCheatRequest, opts ...grpc.CallOption) (*TryToCheatResponse, error)
+	AddHTLC(ctx context.Context, in *AddHTLCRequest, opts ...grpc.CallOption) (*AddHTLCResponse, error)
+	SettleHTLC(ctx context.Context, in *SettleHTLCRequest, opts ...grpc.CallOption) (*SettleHTLCResponse, error)
+	FailHTLC(ctx context.Context, in *FailHTLCRequest, opts ...grpc.CallOption) (*FailHTLCResponse, error)
+	SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*SendPaymentResponse, error)
+	AddInvoice(ctx context.Context, in *AddInvoiceRequest, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	LookupInvoice(ctx context.Context, in *LookupInvoiceRequest, opts ...grpc.CallOption) (*LookupInvoiceResponse, error)
+	ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error)
+	PayInvoice(ctx context.Context, in *PayInvoiceRequest, opts ...grpc.CallOption) (*PayInvoiceResponse, error)
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	DepositToChannel(ctx context.Context, in *DepositToChannelRequest, opts ...grpc.CallOption) (*DepositToChannelResponse, error)
+	WithdrawFromChannel(ctx context.Context, in *WithdrawFromChannelRequest, opts ...grpc.CallOption) (*WithdrawFromChannelResponse, error)
+	AbortChannelOpen(ctx context.Context, in *AbortChannelOpenRequest, opts ...grpc.CallOption) (*AbortChannelOpenResponse, error)
+	CleanupClosedChannels(ctx context.Context, in *CleanupClosedChannelsRequest, opts ...grpc.CallOption) (*CleanupClosedChannelsResponse, error)
+	ListChannelFees(ctx context.Context, in *ListChannelFeesRequest, opts ...grpc.CallOption) (*ListChannelFeesResponse, error)
 }
 
 type aSRPCClient struct {
@@ -40,6 +56,24 @@ func NewASRPCClient(cc grpc.ClientConnInterface) ASRPCClient {
 	return &aSRPCClient{cc}
 }
 
+func (c *aSRPCClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) Unlock(ctx context.Context, in *UnlockRequest, opts ...grpc.CallOption) (*UnlockResponse, error) {
+	out := new(UnlockResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/Unlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aSRPCClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*ResetResponse, error) {
 	out := new(ResetResponse)
 	err := c.cc.Invoke(ctx, "/ASRPC/Reset", in, out, opts...)
@@ -112,10 +146,138 @@ func (c *aSRPCClient) TryToCheat(ctx context.Context, in *TryToCheatRequest, opt
 	return out, nil
 }
 
+func (c *aSRPCClient) AddHTLC(ctx context.Context, in *AddHTLCRequest, opts ...grpc.CallOption) (*AddHTLCResponse, error) {
+	out := new(AddHTLCResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/AddHTLC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) SettleHTLC(ctx context.Context, in *SettleHTLCRequest, opts ...grpc.CallOption) (*SettleHTLCResponse, error) {
+	out := new(SettleHTLCResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/SettleHTLC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) FailHTLC(ctx context.Context, in *FailHTLCRequest, opts ...grpc.CallOption) (*FailHTLCResponse, error) {
+	out := new(FailHTLCResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/FailHTLC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*SendPaymentResponse, error) {
+	out := new(SendPaymentResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/SendPayment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) AddInvoice(ctx context.Context, in *AddInvoiceRequest, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+	out := new(AddInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/AddInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) LookupInvoice(ctx context.Context, in *LookupInvoiceRequest, opts ...grpc.CallOption) (*LookupInvoiceResponse, error) {
+	out := new(LookupInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/LookupInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error) {
+	out := new(ListInvoicesResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/ListInvoices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) PayInvoice(ctx context.Context, in *PayInvoiceRequest, opts ...grpc.CallOption) (*PayInvoiceResponse, error) {
+	out := new(PayInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/PayInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/ListPayments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) DepositToChannel(ctx context.Context, in *DepositToChannelRequest, opts ...grpc.CallOption) (*DepositToChannelResponse, error) {
+	out := new(DepositToChannelResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/DepositToChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) WithdrawFromChannel(ctx context.Context, in *WithdrawFromChannelRequest, opts ...grpc.CallOption) (*WithdrawFromChannelResponse, error) {
+	out := new(WithdrawFromChannelResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/WithdrawFromChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) AbortChannelOpen(ctx context.Context, in *AbortChannelOpenRequest, opts ...grpc.CallOption) (*AbortChannelOpenResponse, error) {
+	out := new(AbortChannelOpenResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/AbortChannelOpen", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) CleanupClosedChannels(ctx context.Context, in *CleanupClosedChannelsRequest, opts ...grpc.CallOption) (*CleanupClosedChannelsResponse, error) {
+	out := new(CleanupClosedChannelsResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/CleanupClosedChannels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aSRPCClient) ListChannelFees(ctx context.Context, in *ListChannelFeesRequest, opts ...grpc.CallOption) (*ListChannelFeesResponse, error) {
+	out := new(ListChannelFeesResponse)
+	err := c.cc.Invoke(ctx, "/ASRPC/ListChannelFees", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ASRPCServer is the server API for ASRPC service.
 // All implementations must embed UnimplementedASRPCServer
 // for forward compatibility
 type ASRPCServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error)
 	Reset(context.Context, *ResetRequest) (*ResetResponse, error)
 	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
 	OpenChannel(context.Context, *OpenChannelRequest) (*OpenChannelResponse, error)
@@ -124,6 +286,20 @@ type ASRPCServer interface {
 	InitiateCloseChannel(context.Context, *InitiateCloseChannelRequest) (*InitiateCloseChannelResponse, error)
 	FinalizeCloseChannel(context.Context, *FinalizeCloseChannelRequest) (*FinalizeCloseChannelResponse, error)
 	TryToCheat(context.Context, *TryToCheatRequest) (*TryToCheatResponse, error)
+	AddHTLC(context.Context, *AddHTLCRequest) (*AddHTLCResponse, error)
+	SettleHTLC(context.Context, *SettleHTLCRequest) (*SettleHTLCResponse, error)
+	FailHTLC(context.Context, *FailHTLCRequest) (*FailHTLCResponse, error)
+	SendPayment(context.Context, *SendPaymentRequest) (*SendPaymentResponse, error)
+	AddInvoice(context.Context, *AddInvoiceRequest) (*AddInvoiceResponse, error)
+	LookupInvoice(context.Context, *LookupInvoiceRequest) (*LookupInvoiceResponse, error)
+	ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error)
+	PayInvoice(context.Context, *PayInvoiceRequest) (*PayInvoiceResponse, error)
+	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
+	DepositToChannel(context.Context, *DepositToChannelRequest) (*DepositToChannelResponse, error)
+	WithdrawFromChannel(context.Context, *WithdrawFromChannelRequest) (*WithdrawFromChannelResponse, error)
+	AbortChannelOpen(context.Context, *AbortChannelOpenRequest) (*AbortChannelOpenResponse, error)
+	CleanupClosedChannels(context.Context, *CleanupClosedChannelsRequest) (*CleanupClosedChannelsResponse, error)
+	ListChannelFees(context.Context, *ListChannelFeesRequest) (*ListChannelFeesResponse, error)
 	mustEmbedUnimplementedASRPCServer()
 }
 
@@ -131,6 +307,12 @@ type ASRPCServer interface {
 type UnimplementedASRPCServer struct {
 }
 
+func (UnimplementedASRPCServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedASRPCServer) Unlock(context.Context, *UnlockRequest) (*UnlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
 func (UnimplementedASRPCServer) Reset(context.Context, *ResetRequest) (*ResetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Reset not implemented")
 }
@@ -155,6 +337,48 @@ func (UnimplementedASRPCServer) FinalizeCloseChannel(context.Context, *FinalizeC
 func (UnimplementedASRPCServer) TryToCheat(context.Context, *TryToCheatRequest) (*TryToCheatResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TryToCheat not implemented")
 }
+func (UnimplementedASRPCServer) AddHTLC(context.Context, *AddHTLCRequest) (*AddHTLCResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddHTLC not implemented")
+}
+func (UnimplementedASRPCServer) SettleHTLC(context.Context, *SettleHTLCRequest) (*SettleHTLCResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SettleHTLC not implemented")
+}
+func (UnimplementedASRPCServer) FailHTLC(context.Context, *FailHTLCRequest) (*FailHTLCResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FailHTLC not implemented")
+}
+func (UnimplementedASRPCServer) SendPayment(context.Context, *SendPaymentRequest) (*SendPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPayment not implemented")
+}
+func (UnimplementedASRPCServer) AddInvoice(context.Context, *AddInvoiceRequest) (*AddInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInvoice not implemented")
+}
+func (UnimplementedASRPCServer) LookupInvoice(context.Context, *LookupInvoiceRequest) (*LookupInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupInvoice not implemented")
+}
+func (UnimplementedASRPCServer) ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+}
+func (UnimplementedASRPCServer) PayInvoice(context.Context, *PayInvoiceRequest) (*PayInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PayInvoice not implemented")
+}
+func (UnimplementedASRPCServer) ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPayments not implemented")
+}
+func (UnimplementedASRPCServer) DepositToChannel(context.Context, *DepositToChannelRequest) (*DepositToChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DepositToChannel not implemented")
+}
+func (UnimplementedASRPCServer) WithdrawFromChannel(context.Context, *WithdrawFromChannelRequest) (*WithdrawFromChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WithdrawFromChannel not implemented")
+}
+func (UnimplementedASRPCServer) AbortChannelOpen(context.Context, *AbortChannelOpenRequest) (*AbortChannelOpenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortChannelOpen not implemented")
+}
+func (UnimplementedASRPCServer) CleanupClosedChannels(context.Context, *CleanupClosedChannelsRequest) (*CleanupClosedChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanupClosedChannels not implemented")
+}
+func (UnimplementedASRPCServer) ListChannelFees(context.Context, *ListChannelFeesRequest) (*ListChannelFeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChannelFees not implemented")
+}
 func (UnimplementedASRPCServer) mustEmbedUnimplementedASRPCServer() {}
 
 // UnsafeASRPCServer may be embedded to opt out of forward compatibility for this service.
@@ -168,6 +392,42 @@ func RegisterASRPCServer(s grpc.ServiceRegistrar, srv ASRPCServer) {
 	s.RegisterService(&ASRPC_ServiceDesc, srv)
 }
 
+func _ASRPC_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).Unlock(ctx, req.(*UnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ASRPC_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ResetRequest)
 	if err := dec(in); err != nil {
@@ -312,6 +572,258 @@ func _ASRPC_TryToCheat_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ASRPC_AddHTLC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddHTLCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).AddHTLC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/AddHTLC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).AddHTLC(ctx, req.(*AddHTLCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_SettleHTLC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SettleHTLCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).SettleHTLC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/SettleHTLC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).SettleHTLC(ctx, req.(*SettleHTLCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_FailHTLC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FailHTLCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).FailHTLC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/FailHTLC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).FailHTLC(ctx, req.(*FailHTLCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_SendPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).SendPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/SendPayment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).SendPayment(ctx, req.(*SendPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).AddInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/AddInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).AddInvoice(ctx, req.(*AddInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).LookupInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/LookupInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).LookupInvoice(ctx, req.(*LookupInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/ListInvoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).ListInvoices(ctx, req.(*ListInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_PayInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).PayInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/PayInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).PayInvoice(ctx, req.(*PayInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).ListPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/ListPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_DepositToChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DepositToChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).DepositToChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/DepositToChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).DepositToChannel(ctx, req.(*DepositToChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_WithdrawFromChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawFromChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).WithdrawFromChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/WithdrawFromChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).WithdrawFromChannel(ctx, req.(*WithdrawFromChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_AbortChannelOpen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortChannelOpenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).AbortChannelOpen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/AbortChannelOpen",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).AbortChannelOpen(ctx, req.(*AbortChannelOpenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_CleanupClosedChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanupClosedChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).CleanupClosedChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/CleanupClosedChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).CleanupClosedChannels(ctx, req.(*CleanupClosedChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ASRPC_ListChannelFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ASRPCServer).ListChannelFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ASRPC/ListChannelFees",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ASRPCServer).ListChannelFees(ctx, req.(*ListChannelFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ASRPC_ServiceDesc is the grpc.ServiceDesc for ASRPC service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -319,6 +831,14 @@ var ASRPC_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "ASRPC",
 	HandlerType: (*ASRPCServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ASRPC_Create_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _ASRPC_Unlock_Handler,
+		},
 		{
 			MethodName: "Reset",
 			Handler:    _ASRPC_Reset_Handler,
@@ -351,6 +871,62 @@ var ASRPC_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TryToCheat",
 			Handler:    _ASRPC_TryToCheat_Handler,
 		},
+		{
+			MethodName: "AddHTLC",
+			Handler:    _ASRPC_AddHTLC_Handler,
+		},
+		{
+			MethodName: "SettleHTLC",
+			Handler:    _ASRPC_SettleHTLC_Handler,
+		},
+		{
+			MethodName: "FailHTLC",
+			Handler:    _ASRPC_FailHTLC_Handler,
+		},
+		{
+			MethodName: "SendPayment",
+			Handler:    _ASRPC_SendPayment_Handler,
+		},
+		{
+			MethodName: "AddInvoice",
+			Handler:    _ASRPC_AddInvoice_Handler,
+		},
+		{
+			MethodName: "LookupInvoice",
+			Handler:    _ASRPC_LookupInvoice_Handler,
+		},
+		{
+			MethodName: "ListInvoices",
+			Handler:    _ASRPC_ListInvoices_Handler,
+		},
+		{
+			MethodName: "PayInvoice",
+			Handler:    _ASRPC_PayInvoice_Handler,
+		},
+		{
+			MethodName: "ListPayments",
+			Handler:    _ASRPC_ListPayments_Handler,
+		},
+		{
+			MethodName: "DepositToChannel",
+			Handler:    _ASRPC_DepositToChannel_Handler,
+		},
+		{
+			MethodName: "WithdrawFromChannel",
+			Handler:    _ASRPC_WithdrawFromChannel_Handler,
+		},
+		{
+			MethodName: "AbortChannelOpen",
+			Handler:    _ASRPC_AbortChannelOpen_Handler,
+		},
+		{
+			MethodName: "CleanupClosedChannels",
+			Handler:    _ASRPC_CleanupClosedChannels_Handler,
+		},
+		{
+			MethodName: "ListChannelFees",
+			Handler:    _ASRPC_ListChannelFees_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "asrpc.proto",
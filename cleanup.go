@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dancodery/algorand-state-channels/payment"
+)
+
+// cleanupClosedChannels deletes all channel apps I created whose app account was closed out.
+// It returns the deleted apps and the fees the deletions paid, their phases are recorded into recorder, which may be nil.
+func (s *server) cleanupClosedChannels(recorder *payment.PhaseRecorder) (deleted_app_ids []uint64, fee uint64, err error) {
+	// 1. read the apps I created
+	account_info, err := s.algod_client.AccountInformation(s.account().Address().String()).Do(context.Background())
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, app := range account_info.CreatedApps {
+		// 2. skip apps which are no payment channels
+		if !payment.IsPaymentApprovalProgram(app.Params.ApprovalProgram) {
+			continue
+		}
+
+		// 3. skip channels which still hold funds
+		closed, err := payment.IsChannelAppClosed(s.algod_client, app.Id)
+		if err != nil {
+			return deleted_app_ids, fee, err
+		}
+		if !closed {
+			continue
+		}
+
+		// 4. delete the app
+		delete_fee, err := payment.DeleteChannelApp(s.algod_client, payment.WithPhaseRecorder(s.account(), recorder), app.Id)
+		fee += delete_fee
+		if err != nil {
+			return deleted_app_ids, fee, err
+		}
+		deleted_app_ids = append(deleted_app_ids, app.Id)
+
+		// 5. forget the channel, its fees stay recorded
+		partner_address := ""
+		s.mu.Lock()
+		for onchain_partner_address, onchain_state := range s.payment_channels_onchain_states {
+			if onchain_state.app_id == app.Id {
+				partner_address = onchain_partner_address
+				delete(s.payment_channels_onchain_states, partner_address)
+				delete(s.payment_channels_offchain_states_log, partner_address)
+			}
+		}
+		s.mu.Unlock()
+
+		s.recordChannelFee(app.Id, partner_address, delete_fee)
+
+		fmt.Printf("Deleted closed payment channel app with app_id %d\n", app.Id)
+	}
+	return deleted_app_ids, fee, nil
+}
+
+// checkRetiredChannels fails if an app the account created still holds the funds of a channel running a retired
+// contract version, which this node can no longer call. Such channels have to be closed before upgrading.
+func (s *server) checkRetiredChannels(algo_address string) error {
+	account_info, err := s.algod_client.AccountInformation(algo_address).Do(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, app := range account_info.CreatedApps {
+		contract_version, retired := payment.LookupRetiredContractVersion(app.Params.ApprovalProgram, app.Params.ClearStateProgram)
+		if !retired {
+			continue
+		}
+		closed, err := payment.IsChannelAppClosed(s.algod_client, app.Id)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return fmt.Errorf("channel app %d runs the retired contract version %d, close it with the release which opened it before upgrading", app.Id, contract_version.Version)
+		}
+	}
+	return nil
+}
@@ -6,12 +6,19 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"time"
+
+	"github.com/dancodery/algorand-state-channels/payment"
 )
 
 type P2PRequest struct {
 	Command string
 	// Args    []string
 	Args [][]byte
+
+	// set by sendRequestWithTimeout, peers reject requests of other networks or protocol versions
+	GenesisHash     []byte
+	ProtocolVersion uint64
 }
 
 type P2PResponse struct {
@@ -19,14 +26,54 @@ type P2PResponse struct {
 	Data    [][]byte
 }
 
-func sendRequest(recipient_ip string, request P2PRequest) (response P2PResponse, err error) {
+// peerNetwork connects the node with its partner nodes, tests replace it with an in-memory network
+type peerNetwork interface {
+	Dial(host string, timeout time.Duration) (net.Conn, error)
+	Listen(port int) (net.Listener, error)
+}
+
+// tcpNetwork reaches partner nodes on the default peer port of their host
+type tcpNetwork struct{}
+
+func (tcpNetwork) Dial(host string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", host+":"+strconv.Itoa(DEFAULT_PEER_PORT), timeout)
+}
+
+func (tcpNetwork) Listen(port int) (net.Listener, error) {
+	return net.Listen("tcp", fmt.Sprintf(":%d", port))
+}
+
+// peerHost returns the host of a partner node's address, under which it is reached back
+func peerHost(addr net.Addr) string {
+	if tcp_addr, ok := addr.(*net.TCPAddr); ok {
+		return tcp_addr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+func (s *server) sendRequest(recipient_ip string, request P2PRequest) (response P2PResponse, err error) {
+	return s.sendRequestWithTimeout(recipient_ip, request, 0)
+}
+
+// sendRequestWithTimeout fails if the peer server does not answer within timeout, 0 waits forever
+func (s *server) sendRequestWithTimeout(recipient_ip string, request P2PRequest, timeout time.Duration) (response P2PResponse, err error) {
 	// connect to peer server
-	conn, err := net.Dial("tcp", recipient_ip+":"+strconv.Itoa(DEFAULT_PEER_PORT))
+	conn, err := s.peer_network.Dial(recipient_ip, timeout)
 	if err != nil {
 		fmt.Fprintf(os.Stdout, "Did not connect to peer server: %v\n", err)
+		return P2PResponse{}, err
 	}
 	defer conn.Close()
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
 
+	request.GenesisHash = s.genesis_hash
+	request.ProtocolVersion = payment.PROTOCOL_VERSION
 	json_request, err := json.Marshal(request)
 	if err != nil {
 		fmt.Fprintf(os.Stdout, "Error marshalling request: %v\n", err)
@@ -41,15 +88,8 @@ func sendRequest(recipient_ip string, request P2PRequest) (response P2PResponse,
 	}
 
 	// read response from peer server
-	responseData := make([]byte, 1024)
-	n, err := conn.Read(responseData)
-	if err != nil {
-		fmt.Fprintf(os.Stdout, "Error reading response: %v\n", err)
-		return P2PResponse{}, err
-	}
-
 	var server_response P2PResponse
-	err = json.Unmarshal(responseData[:n], &server_response)
+	err = json.NewDecoder(conn).Decode(&server_response)
 	if err != nil {
 		fmt.Fprintf(os.Stdout, "Error unmarshalling server_response: %v\n", err)
 		return P2PResponse{}, err
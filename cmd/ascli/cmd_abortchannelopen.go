@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var abortChannelOpenCommand = cli.Command{
+	Name:  "abortchannelopen",
+	Usage: "refund a channel the partner node never accepted",
+	Description: `
+		Abort opening a payment channel and refund the deposit.
+		Only works as long as the partner node has not accepted the channel on chain.
+		openchannel does this automatically if the partner node rejects or does not answer.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "app_id",
+			Usage: "app id of the payment channel",
+		},
+	},
+	Action: abortChannelOpen,
+}
+
+func abortChannelOpen(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.Uint64("app_id") == 0 {
+		return cli.NewExitError("app id is required", 1)
+	}
+
+	abort_channel_open_request := &asrpc.AbortChannelOpenRequest{
+		AppId: ctx.Uint64("app_id"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	abort_channel_open_response, err := client.AbortChannelOpen(ctxb, abort_channel_open_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(abort_channel_open_response)
+
+	return nil
+}
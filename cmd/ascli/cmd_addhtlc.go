@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var addHTLCCommand = cli.Command{
+	Name:  "addhtlc",
+	Usage: "offer a hash-time-locked payment to the channel partner",
+	Description: `
+		Lock an amount in a conditional payment to the channel partner.
+		The partner receives the amount once it reveals the preimage of the payment hash.
+		After expiry rounds the amount can be refunded.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node",
+		},
+		cli.Uint64Flag{
+			Name:  "amount",
+			Usage: "amount to lock in the htlc",
+		},
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "hex encoded sha256 hash of the preimage",
+		},
+		cli.Uint64Flag{
+			Name:  "expiry",
+			Usage: "number of rounds until the htlc can be refunded",
+		},
+	},
+	Action: addHTLC,
+}
+
+func addHTLC(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("partner_address") == "" {
+		return cli.NewExitError("partner algo address is required", 1)
+	}
+	if ctx.Uint64("amount") == 0 {
+		return cli.NewExitError("amount is required", 1)
+	}
+	if ctx.Uint64("expiry") == 0 {
+		return cli.NewExitError("expiry is required", 1)
+	}
+	payment_hash, err := hex.DecodeString(ctx.String("payment_hash"))
+	if err != nil || len(payment_hash) != 32 {
+		return cli.NewExitError("payment hash must be 32 hex encoded bytes", 1)
+	}
+
+	add_htlc_request := &asrpc.AddHTLCRequest{
+		AlgoAddress: ctx.String("partner_address"),
+		Amount:      ctx.Uint64("amount"),
+		PaymentHash: payment_hash,
+		Expiry:      ctx.Uint64("expiry"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	add_htlc_response, err := client.AddHTLC(ctxb, add_htlc_request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Response from gRPC server: ", add_htlc_response)
+
+	return nil
+}
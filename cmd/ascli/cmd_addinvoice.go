@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var addInvoiceCommand = cli.Command{
+	Name:  "addinvoice",
+	Usage: "create an invoice to request a payment",
+	Description: `
+		Create an invoice for the given amount and memo.
+		The returned payment request is handed to the payer, who pays it with payinvoice.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "amount",
+			Usage: "amount to request in microalgos",
+		},
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "description of the payment, e.g. an order id (optional)",
+		},
+		cli.Uint64Flag{
+			Name:  "expiry",
+			Usage: "seconds until the invoice expires (optional)",
+		},
+	},
+	Action: addInvoice,
+}
+
+func addInvoice(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.Uint64("amount") == 0 {
+		return cli.NewExitError("amount is required", 1)
+	}
+
+	add_invoice_request := &asrpc.AddInvoiceRequest{
+		Amount: ctx.Uint64("amount"),
+		Memo:   ctx.String("memo"),
+		Expiry: ctx.Uint64("expiry"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	add_invoice_response, err := client.AddInvoice(ctxb, add_invoice_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(add_invoice_response)
+
+	return nil
+}
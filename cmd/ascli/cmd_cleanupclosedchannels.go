@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var cleanupClosedChannelsCommand = cli.Command{
+	Name:  "cleanupclosedchannels",
+	Usage: "delete all closed payment channel apps of the node",
+	Description: `
+		Delete all payment channel apps created by the node whose funds were paid out.
+		This frees the minimum balance held for their global state.
+	`,
+	Action: cleanupClosedChannels,
+}
+
+func cleanupClosedChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	cleanup_closed_channels_response, err := client.CleanupClosedChannels(ctxb, &asrpc.CleanupClosedChannelsRequest{})
+	if err != nil {
+		return err
+	}
+
+	printJson(cleanup_closed_channels_response)
+
+	return nil
+}
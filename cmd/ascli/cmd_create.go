@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var createCommand = cli.Command{
+	Name:  "create",
+	Usage: "create the encrypted keystore of the node",
+	Description: `
+		Create the encrypted keystore holding the account of the node and unlock it.
+		A new account is generated unless a mnemonic is given to restore one.
+		Write down the mnemonic of a generated account, it is shown only once.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "password",
+			Usage: "password of the keystore, read from stdin if not given",
+		},
+		cli.StringFlag{
+			Name:  "mnemonic",
+			Usage: "25 word mnemonic of an account to restore (optional)",
+		},
+	},
+	Action: create,
+}
+
+func create(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	password, err := readPassword(ctx)
+	if err != nil {
+		return err
+	}
+
+	create_request := &asrpc.CreateRequest{
+		Password: password,
+		Mnemonic: ctx.String("mnemonic"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	create_response, err := client.Create(ctxb, create_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(create_response)
+
+	return nil
+}
+
+// readPassword returns the password flag or reads the password from stdin
+func readPassword(ctx *cli.Context) (string, error) {
+	if ctx.String("password") != "" {
+		return ctx.String("password"), nil
+	}
+	fmt.Print("Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return "", cli.NewExitError("password is required", 1)
+	}
+	return password, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var depositToChannelCommand = cli.Command{
+	Name:  "deposittochannel",
+	Usage: "add funds to an open payment channel",
+	Description: `
+		Deposit more funds into an open payment channel.
+		The channel partner co-signs a new state crediting the deposit to my balance.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node",
+		},
+		cli.Uint64Flag{
+			Name:  "amount",
+			Usage: "amount to deposit into the channel",
+		},
+	},
+	Action: depositToChannel,
+}
+
+func depositToChannel(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("partner_address") == "" {
+		return cli.NewExitError("partner address is required", 1)
+	}
+	if ctx.Uint64("amount") == 0 {
+		return cli.NewExitError("amount is required", 1)
+	}
+
+	deposit_to_channel_request := &asrpc.DepositToChannelRequest{
+		AlgoAddress: ctx.String("partner_address"),
+		Amount:      ctx.Uint64("amount"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	deposit_to_channel_response, err := client.DepositToChannel(ctxb, deposit_to_channel_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(deposit_to_channel_response)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var failHTLCCommand = cli.Command{
+	Name:  "failhtlc",
+	Usage: "cancel a hash-time-locked payment",
+	Description: `
+		Remove a pending htlc from the channel and refund the amount to its offerer.
+		Incoming htlcs can always be failed, outgoing htlcs only after they expired.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node",
+		},
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "hex encoded payment hash of the htlc",
+		},
+	},
+	Action: failHTLC,
+}
+
+func failHTLC(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("partner_address") == "" {
+		return cli.NewExitError("partner algo address is required", 1)
+	}
+	payment_hash, err := hex.DecodeString(ctx.String("payment_hash"))
+	if err != nil || len(payment_hash) != 32 {
+		return cli.NewExitError("payment hash must be 32 hex encoded bytes", 1)
+	}
+
+	fail_htlc_request := &asrpc.FailHTLCRequest{
+		AlgoAddress: ctx.String("partner_address"),
+		PaymentHash: payment_hash,
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	fail_htlc_response, err := client.FailHTLC(ctxb, fail_htlc_request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Response from gRPC server: ", fail_htlc_response)
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var listChannelFeesCommand = cli.Command{
+	Name:  "listchannelfees",
+	Usage: "list the blockchain fees the node paid per payment channel",
+	Description: `
+		List the cumulative blockchain fees the node paid for the on-chain operations of each payment channel,
+		including closed channels. The list can be restricted to the channel with one partner.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node (optional)",
+		},
+	},
+	Action: listChannelFees,
+}
+
+func listChannelFees(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+
+	list_channel_fees_request := &asrpc.ListChannelFeesRequest{
+		AlgoAddress: ctx.String("partner_address"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	list_channel_fees_response, err := client.ListChannelFees(ctxb, list_channel_fees_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(list_channel_fees_response)
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var listInvoicesCommand = cli.Command{
+	Name:  "listinvoices",
+	Usage: "list all invoices of the node",
+	Description: `
+		List all invoices of the node with their state: open, settled or expired.
+	`,
+	Action: listInvoices,
+}
+
+func listInvoices(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	list_invoices_response, err := client.ListInvoices(ctxb, &asrpc.ListInvoicesRequest{})
+	if err != nil {
+		return err
+	}
+
+	printJson(list_invoices_response)
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var listPaymentsCommand = cli.Command{
+	Name:  "listpayments",
+	Usage: "list the payment history of the node",
+	Description: `
+		List all off-chain payments with their memos.
+		The history can be restricted to the channel with one partner.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node (optional)",
+		},
+	},
+	Action: listPayments,
+}
+
+func listPayments(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+
+	list_payments_request := &asrpc.ListPaymentsRequest{
+		AlgoAddress: ctx.String("partner_address"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	list_payments_response, err := client.ListPayments(ctxb, list_payments_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(list_payments_response)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var lookupInvoiceCommand = cli.Command{
+	Name:  "lookupinvoice",
+	Usage: "show an invoice and whether it was paid",
+	Description: `
+		Look up one of the node's invoices by its payment hash.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "hex encoded payment hash of the invoice",
+		},
+	},
+	Action: lookupInvoice,
+}
+
+func lookupInvoice(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	payment_hash, err := hex.DecodeString(ctx.String("payment_hash"))
+	if err != nil || len(payment_hash) != 32 {
+		return cli.NewExitError("payment hash must be 32 hex encoded bytes", 1)
+	}
+
+	lookup_invoice_request := &asrpc.LookupInvoiceRequest{
+		PaymentHash: payment_hash,
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	lookup_invoice_response, err := client.LookupInvoice(ctxb, lookup_invoice_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(lookup_invoice_response)
+
+	return nil
+}
@@ -15,6 +15,7 @@ var openChannelCommand = cli.Command{
 		Open a new channel to another node.	
 		The partner's Algo address, funding amount, penalty reserve, and dispute window are required for the smart contract.
 		The partner's IP address is required for the off-chain communication.
+		With an asset id, the channel is funded with and denominated in that Algorand Standard Asset instead of algos.
 	`,
 	ArgsUsage: "partner_ip funding_amount penalty_reserve dispute_window",
 	Flags: []cli.Flag{
@@ -38,6 +39,10 @@ var openChannelCommand = cli.Command{
 			Name:  "dispute_window",
 			Usage: "number of blocks to wait for dispute resolution",
 		},
+		cli.Uint64Flag{
+			Name:  "asset_id",
+			Usage: "asset the channel is denominated in, algo if not set (optional)",
+		},
 	},
 	Action: openChannel,
 }
@@ -75,6 +80,7 @@ func openChannel(ctx *cli.Context) error {
 		FundingAmount:  ctx.Uint64("funding_amount"),
 		PenaltyReserve: ctx.Uint64("penalty_reserve"),
 		DisputeWindow:  ctx.Uint64("dispute_window"),
+		AssetId:        ctx.Uint64("asset_id"),
 	}
 
 	openChannelResponse, err := client.OpenChannel(ctxb, openChannelRequest)
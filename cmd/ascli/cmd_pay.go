@@ -14,6 +14,7 @@ var payCommand = cli.Command{
 	Description: `
 		Pay the channel partner off-chain.
 		Only the algo address of the partner and the amount to pay are required.
+		An optional memo, e.g. an order id, is signed and stored with the payment.
 	`,
 	ArgsUsage: "amount",
 	Flags: []cli.Flag{
@@ -25,6 +26,10 @@ var payCommand = cli.Command{
 			Name:  "amount",
 			Usage: "amount to pay the channel partner",
 		},
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "description of the payment (optional)",
+		},
 	},
 	Action: pay,
 }
@@ -43,6 +48,7 @@ func pay(ctx *cli.Context) error {
 	payRequest := &asrpc.PayRequest{
 		AlgoAddress: ctx.String("partner_address"),
 		Amount:      ctx.Uint64("amount"),
+		Memo:        ctx.String("memo"),
 	}
 
 	ctxb := context.Background()
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var payInvoiceCommand = cli.Command{
+	Name:  "payinvoice",
+	Usage: "pay an invoice of another node",
+	Description: `
+		Pay an encoded payment request created with addinvoice.
+		Without hops the invoice is paid over the direct payment channel with the invoice node.
+		Otherwise the hops list the route from the channel partner to the invoice node as ip,algo_address.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_request",
+			Usage: "encoded payment request of the invoice",
+		},
+		cli.StringSliceFlag{
+			Name:  "hop",
+			Usage: "ip,algo_address of a hop, repeated in route order (optional)",
+		},
+	},
+	Action: payInvoice,
+}
+
+func payInvoice(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("payment_request") == "" {
+		return cli.NewExitError("payment request is required", 1)
+	}
+
+	var route []*asrpc.StateChannelNodeAddress
+	for _, hop := range ctx.StringSlice("hop") {
+		host, algo_address, found := strings.Cut(hop, ",")
+		if !found {
+			return cli.NewExitError("hop must be given as ip,algo_address", 1)
+		}
+		route = append(route, &asrpc.StateChannelNodeAddress{
+			Host:        host,
+			AlgoAddress: algo_address,
+		})
+	}
+
+	pay_invoice_request := &asrpc.PayInvoiceRequest{
+		PaymentRequest: ctx.String("payment_request"),
+		Route:          route,
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	pay_invoice_response, err := client.PayInvoice(ctxb, pay_invoice_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(pay_invoice_response)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var sendPaymentCommand = cli.Command{
+	Name:  "sendpayment",
+	Usage: "send a payment over a route of payment channels",
+	Description: `
+		Send a multi-hop payment to a node without a direct payment channel.
+		The route lists every hop from the channel partner to the destination as ip,algo_address.
+		Every intermediate hop forwards the payment for a fee.
+		Without a payment hash, the payment is sent spontaneously.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "destination_address",
+			Usage: "algo address of the destination node",
+		},
+		cli.Uint64Flag{
+			Name:  "amount",
+			Usage: "amount the destination receives",
+		},
+		cli.StringSliceFlag{
+			Name:  "hop",
+			Usage: "ip,algo_address of a hop, repeated in route order",
+		},
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "hex encoded sha256 hash of the preimage (optional)",
+		},
+		cli.Uint64Flag{
+			Name:  "final_expiry",
+			Usage: "number of rounds until the htlc of the destination can be refunded (optional)",
+		},
+	},
+	Action: sendPayment,
+}
+
+func sendPayment(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("destination_address") == "" {
+		return cli.NewExitError("destination algo address is required", 1)
+	}
+	if ctx.Uint64("amount") == 0 {
+		return cli.NewExitError("amount is required", 1)
+	}
+	if len(ctx.StringSlice("hop")) == 0 {
+		return cli.NewExitError("at least one hop is required", 1)
+	}
+
+	var route []*asrpc.StateChannelNodeAddress
+	for _, hop := range ctx.StringSlice("hop") {
+		host, algo_address, found := strings.Cut(hop, ",")
+		if !found {
+			return cli.NewExitError("hop must be given as ip,algo_address", 1)
+		}
+		route = append(route, &asrpc.StateChannelNodeAddress{
+			Host:        host,
+			AlgoAddress: algo_address,
+		})
+	}
+
+	var payment_hash []byte
+	if ctx.String("payment_hash") != "" {
+		var err error
+		payment_hash, err = hex.DecodeString(ctx.String("payment_hash"))
+		if err != nil || len(payment_hash) != 32 {
+			return cli.NewExitError("payment hash must be 32 hex encoded bytes", 1)
+		}
+	}
+
+	send_payment_request := &asrpc.SendPaymentRequest{
+		DestinationAddress: ctx.String("destination_address"),
+		Amount:             ctx.Uint64("amount"),
+		Route:              route,
+		PaymentHash:        payment_hash,
+		FinalExpiry:        ctx.Uint64("final_expiry"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	send_payment_response, err := client.SendPayment(ctxb, send_payment_request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Response from gRPC server: ", send_payment_response)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var settleHTLCCommand = cli.Command{
+	Name:  "settlehtlc",
+	Usage: "claim a hash-time-locked payment of the channel partner",
+	Description: `
+		Reveal the preimage of an incoming htlc to the channel partner.
+		The locked amount is added to the own channel balance.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node",
+		},
+		cli.StringFlag{
+			Name:  "preimage",
+			Usage: "hex encoded preimage of the payment hash",
+		},
+	},
+	Action: settleHTLC,
+}
+
+func settleHTLC(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("partner_address") == "" {
+		return cli.NewExitError("partner algo address is required", 1)
+	}
+	preimage, err := hex.DecodeString(ctx.String("preimage"))
+	if err != nil || len(preimage) == 0 {
+		return cli.NewExitError("hex encoded preimage is required", 1)
+	}
+
+	settle_htlc_request := &asrpc.SettleHTLCRequest{
+		AlgoAddress: ctx.String("partner_address"),
+		Preimage:    preimage,
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	settle_htlc_response, err := client.SettleHTLC(ctxb, settle_htlc_request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Response from gRPC server: ", settle_htlc_response)
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var unlockCommand = cli.Command{
+	Name:  "unlock",
+	Usage: "unlock the encrypted keystore of the node",
+	Description: `
+		Decrypt the keystore holding the account of the node.
+		The node refuses to operate channels until it is unlocked.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "password",
+			Usage: "password of the keystore, read from stdin if not given",
+		},
+	},
+	Action: unlock,
+}
+
+func unlock(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	password, err := readPassword(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	unlock_response, err := client.Unlock(ctxb, &asrpc.UnlockRequest{Password: password})
+	if err != nil {
+		return err
+	}
+
+	printJson(unlock_response)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/urfave/cli"
+)
+
+var withdrawFromChannelCommand = cli.Command{
+	Name:  "withdrawfromchannel",
+	Usage: "take funds out of an open payment channel",
+	Description: `
+		Withdraw funds from an open payment channel without closing it.
+		The channel partner co-signs a withdraw authorization taking the amount from my balance.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "partner_address",
+			Usage: "algo address of the partner node",
+		},
+		cli.Uint64Flag{
+			Name:  "amount",
+			Usage: "amount to withdraw from the channel",
+		},
+	},
+	Action: withdrawFromChannel,
+}
+
+func withdrawFromChannel(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return cli.NewExitError("incorrect number of arguments", 1)
+	}
+	if ctx.String("partner_address") == "" {
+		return cli.NewExitError("partner address is required", 1)
+	}
+	if ctx.Uint64("amount") == 0 {
+		return cli.NewExitError("amount is required", 1)
+	}
+
+	withdraw_from_channel_request := &asrpc.WithdrawFromChannelRequest{
+		AlgoAddress: ctx.String("partner_address"),
+		Amount:      ctx.Uint64("amount"),
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	withdraw_from_channel_response, err := client.WithdrawFromChannel(ctxb, withdraw_from_channel_request)
+	if err != nil {
+		return err
+	}
+
+	printJson(withdraw_from_channel_response)
+
+	return nil
+}
@@ -43,6 +43,8 @@ func main() {
 	app.Name = "ascli"
 	app.Usage = "control plane for the Algorand State Channels node"
 	app.Commands = []cli.Command{
+		createCommand,
+		unlockCommand,
 		resetCommand,
 		getInfoCommand,
 		openChannelCommand,
@@ -50,6 +52,20 @@ func main() {
 		cooperativecloseChannelCommand,
 		initiateChannelClosingCommand,
 		finalizeChannelClosingCommand,
+		addHTLCCommand,
+		settleHTLCCommand,
+		failHTLCCommand,
+		sendPaymentCommand,
+		addInvoiceCommand,
+		lookupInvoiceCommand,
+		listInvoicesCommand,
+		payInvoiceCommand,
+		listPaymentsCommand,
+		depositToChannelCommand,
+		withdrawFromChannelCommand,
+		abortChannelOpenCommand,
+		cleanupClosedChannelsCommand,
+		listChannelFeesCommand,
 		tryToCheatCommand, // only for testing purposes
 	}
 
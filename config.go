@@ -1,18 +1,130 @@
 package main
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
 const (
 	DEFAULT_GRPC_PORT = 50051
 	DEFAULT_PEER_PORT = 28547
+
+	// forwarding fees and timelock delta charged for multi-hop payments
+	DEFAULT_FEE_BASE       = 1000 // microalgos
+	DEFAULT_FEE_PPM        = 100  // parts per million of the forwarded amount
+	DEFAULT_TIMELOCK_DELTA = 40   // rounds
+
+	MAX_MEMO_LENGTH = 1024 // bytes
+
+	OPEN_CHANNEL_TIMEOUT = 60 * time.Second // time the partner node has to accept a new channel
+	WATCHTOWER_INTERVAL  = 1 * time.Second  // time between two checks of the watchtower
+
+	DEFAULT_KEYSTORE_DIR  = ".asc"
+	DEFAULT_KEYSTORE_FILE = "keystore.json"
+
+	SIGNER_BACKEND_KEYSTORE   = "keystore"
+	SIGNER_BACKEND_KMD        = "kmd"
+	SIGNER_BACKEND_AUTHORIZED = "authorized" // rekeyed or multisig funding account
+
+	MULTISIG_VERSION = 1
 )
 
 type config struct {
 	GRPCPort int
 	PeerPort int
+	Host     string // host under which partner nodes reach this node, encoded into invoices
+
+	FeeBase       uint64
+	FeePPM        uint64
+	TimelockDelta uint64
+
+	DevMode      bool   // from DEV_MODE=true, only for testing
+	KeystorePath string // from KEYSTORE_PATH, defaults to ~/.asc/keystore.json
+
+	SignerBackend     string // from SIGNER_BACKEND, keystore or kmd
+	KMDWalletName     string // from KMD_WALLET_NAME
+	KMDWalletPassword string // from KMD_WALLET_PASSWORD
+	KMDAccount        string // from KMD_ACCOUNT, address of the node account in the kmd wallet
+	KMDChannelSeed    string // from KMD_CHANNEL_SEED, mnemonic of the seed deriving the channel signing keys
+
+	FundingAddress    string   // from FUNDING_ADDRESS, rekeyed or multisig account holding the channel funds
+	AuthMnemonics     []string // from AUTH_MNEMONICS, comma separated mnemonics of the authorizing keys, the first derives the channel signing keys
+	MultisigAddresses []string // from MULTISIG_ADDRESSES, comma separated ordered members, empty for a single authorizing key
+	MultisigThreshold uint8    // from MULTISIG_THRESHOLD
 }
 
 func loadConfig() (*config, error) {
+	host := os.Getenv("NODE_HOST")
+	if host == "" {
+		var err error
+		host, err = os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keystore_path := os.Getenv("KEYSTORE_PATH")
+	if keystore_path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		keystore_path = filepath.Join(home, DEFAULT_KEYSTORE_DIR, DEFAULT_KEYSTORE_FILE)
+	}
+
+	signer_backend := os.Getenv("SIGNER_BACKEND")
+	if signer_backend == "" {
+		signer_backend = SIGNER_BACKEND_KEYSTORE
+	}
+	if signer_backend != SIGNER_BACKEND_KEYSTORE && signer_backend != SIGNER_BACKEND_KMD && signer_backend != SIGNER_BACKEND_AUTHORIZED {
+		return nil, fmt.Errorf("unknown signer backend %s", signer_backend)
+	}
+
+	multisig_threshold := 0
+	if threshold := os.Getenv("MULTISIG_THRESHOLD"); threshold != "" {
+		var err error
+		multisig_threshold, err = strconv.Atoi(threshold)
+		if err != nil || multisig_threshold < 1 || multisig_threshold > 255 {
+			return nil, fmt.Errorf("invalid multisig threshold %s", threshold)
+		}
+	}
+
 	return &config{
 		GRPCPort: DEFAULT_GRPC_PORT,
 		PeerPort: DEFAULT_PEER_PORT,
+		Host:     host,
+
+		FeeBase:       DEFAULT_FEE_BASE,
+		FeePPM:        DEFAULT_FEE_PPM,
+		TimelockDelta: DEFAULT_TIMELOCK_DELTA,
+
+		DevMode:      os.Getenv("DEV_MODE") == "true",
+		KeystorePath: keystore_path,
+
+		SignerBackend:     signer_backend,
+		KMDWalletName:     os.Getenv("KMD_WALLET_NAME"),
+		KMDWalletPassword: os.Getenv("KMD_WALLET_PASSWORD"),
+		KMDAccount:        os.Getenv("KMD_ACCOUNT"),
+		KMDChannelSeed:    os.Getenv("KMD_CHANNEL_SEED"),
+
+		FundingAddress:    os.Getenv("FUNDING_ADDRESS"),
+		AuthMnemonics:     splitList(os.Getenv("AUTH_MNEMONICS")),
+		MultisigAddresses: splitList(os.Getenv("MULTISIG_ADDRESSES")),
+		MultisigThreshold: uint8(multisig_threshold),
 	}, nil
 }
+
+// splitList splits a comma separated env variable, an empty variable is an empty list
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
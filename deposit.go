@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dancodery/algorand-state-channels/payment"
+)
+
+// pendingCapacityChange is a co-signed state of a deposit or withdrawal which is not confirmed on chain yet
+type pendingCapacityChange struct {
+	total_deposit   uint64 // total deposit once the change is on chain
+	off_chain_state paymentChannelOffChainState
+}
+
+// capacityChangePending tells whether a deposit or withdrawal of the channel partner awaits its confirmation
+func (s *server) capacityChangePending(counterparty_address string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.pending_capacity_changes[counterparty_address]
+	return ok
+}
+
+// depositToChannel lets the channel partner co-sign a state crediting amount to my balance,
+// deposits the amount on chain and tells the partner about the confirmed deposit. It returns the fee of the deposit.
+// The phases of the deposit are recorded into recorder, which may be nil.
+func (s *server) depositToChannel(counterparty_address string, amount uint64, recorder *payment.PhaseRecorder) (uint64, error) {
+	if amount == 0 {
+		return 0, errors.New("deposit amount must be positive")
+	}
+	unlock_channel := s.lockChannel(counterparty_address)
+	defer unlock_channel()
+	onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		return 0, err
+	}
+
+	// 1. credit the deposit to my balance
+	new_alice_balance := latestOffChainState.alice_balance
+	new_bob_balance := latestOffChainState.bob_balance
+	if me_alice {
+		new_alice_balance += amount
+	} else {
+		new_bob_balance += amount
+	}
+
+	// 2. let the partner co-sign the new state
+	off_chain_state, err := s.cosignStateUpdate(
+		counterparty_address,
+		"deposit_request",
+		new_alice_balance,
+		new_bob_balance,
+		latestOffChainState.htlcs,
+		[][]byte{uint64ToBytes(amount)},
+		recorder)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3. deposit on chain
+	fee, err := payment.DepositToChannel(
+		s.algod_client,
+		payment.WithPhaseRecorder(s.account(), recorder),
+		s.genesis_hash,
+		onchain_state.app_id,
+		off_chain_state.alice_balance,
+		off_chain_state.bob_balance,
+		uint64(off_chain_state.timestamp),
+		off_chain_state.htlcs,
+		onchain_state.asset_id,
+		off_chain_state.alice_signature,
+		off_chain_state.bob_signature,
+		amount)
+	if err == nil {
+		s.recordChannelFee(onchain_state.app_id, counterparty_address, fee)
+
+		// 4. save new state and capacity
+		s.saveOffChainState(counterparty_address, off_chain_state)
+		onchain_state.total_deposit += amount
+		s.mu.Lock()
+		s.payment_channels_onchain_states[counterparty_address] = onchain_state
+		s.mu.Unlock()
+		s.UpdateWatchtowerState()
+	}
+
+	// 5. let the partner check the deposit on chain, it drops the pending state otherwise
+	end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	server_response, confirmation_err := s.sendRequest(onchain_state.partner_ip, P2PRequest{
+		Command: "deposit_confirmation",
+		Args:    [][]byte{[]byte(s.account().Address().String())},
+	})
+	end_round_trip()
+	if err != nil {
+		return fee, err
+	}
+	if confirmation_err != nil {
+		return fee, confirmation_err
+	}
+	if server_response.Message != "approve" {
+		return fee, errors.New("partner node did not confirm the deposit")
+	}
+	return fee, nil
+}
+
+// processDepositRequest co-signs a state crediting a deposit to the channel partner's balance.
+// The state is only saved once the deposit is confirmed on chain.
+func (s *server) processDepositRequest(args [][]byte) (server_response P2PResponse) {
+	server_response.Message = "reject"
+
+	if len(args) < 7 {
+		fmt.Println("Error: deposit_request has too few arguments")
+		return
+	}
+	if !areUint64Args(args, 1, 2, 3, 6) {
+		fmt.Println("Error: deposit_request has malformed balances, timestamp or amount")
+		return
+	}
+	counterparty_address := string(args[0])
+	alice_new_balance := binary.BigEndian.Uint64(args[1])
+	bob_new_balance := binary.BigEndian.Uint64(args[2])
+	new_timestamp := int64(binary.BigEndian.Uint64(args[3]))
+	new_htlcs, err := payment.DecodeHTLCs(args[4])
+	if err != nil {
+		fmt.Printf("Error decoding htlcs: %v\n", err)
+		return
+	}
+	channel_partner_signature := args[5]
+	amount := binary.BigEndian.Uint64(args[6])
+
+	// 1. load on chain and latest off chain state
+	onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if s.capacityChangePending(counterparty_address) {
+		fmt.Println("Error: a deposit or withdrawal is already pending")
+		return
+	}
+
+	// 2. verify that only the partner's balance increased by the deposit
+	ok := amount > 0 &&
+		latestOffChainState.timestamp < new_timestamp &&
+		bytes.Equal(payment.EncodeHTLCs(latestOffChainState.htlcs), payment.EncodeHTLCs(new_htlcs))
+	if me_alice {
+		ok = ok && alice_new_balance == latestOffChainState.alice_balance &&
+			bob_new_balance == latestOffChainState.bob_balance+amount
+	} else {
+		ok = ok && bob_new_balance == latestOffChainState.bob_balance &&
+			alice_new_balance == latestOffChainState.alice_balance+amount
+	}
+	if !ok {
+		fmt.Println("Error: invalid new balances")
+		return
+	}
+
+	// 3. verify channel partner signature
+	channel_partner_signature_correct := payment.VerifyState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		channel_partner_signature,
+		onchain_state.signingKey(counterparty_address),
+		new_timestamp,
+		new_htlcs,
+	)
+	if !channel_partner_signature_correct {
+		fmt.Println("Error: invalid channel partner signature")
+		return
+	}
+
+	// 4. sign the state as well
+	my_signature, err := payment.SignState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		onchain_state.my_signer,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		new_timestamp,
+		new_htlcs,
+	)
+	if err != nil {
+		fmt.Printf("Error signing state: %v\n", err)
+		return
+	}
+
+	// 5. keep the state until the deposit is confirmed
+	off_chain_state := paymentChannelOffChainState{
+		timestamp: new_timestamp,
+
+		alice_balance: alice_new_balance,
+		bob_balance:   bob_new_balance,
+		htlcs:         new_htlcs,
+
+		genesis_hash: s.genesis_hash,
+		app_id:       onchain_state.app_id,
+	}
+	if me_alice {
+		off_chain_state.alice_signature = my_signature
+		off_chain_state.bob_signature = channel_partner_signature
+	} else {
+		off_chain_state.alice_signature = channel_partner_signature
+		off_chain_state.bob_signature = my_signature
+	}
+	s.mu.Lock()
+	s.pending_capacity_changes[counterparty_address] = pendingCapacityChange{
+		total_deposit:   onchain_state.total_deposit + amount,
+		off_chain_state: off_chain_state,
+	}
+	s.mu.Unlock()
+
+	fmt.Printf("Processed deposit_request of %d microalgos with app_id %d\n\n", amount, onchain_state.app_id)
+
+	server_response.Message = "approve"
+	server_response.Data = [][]byte{
+		my_signature,
+	}
+	return server_response
+}
+
+// processCapacityConfirmation saves the pending deposit or withdrawal state if it arrived on chain and drops it otherwise
+func (s *server) processCapacityConfirmation(args [][]byte) (server_response P2PResponse) {
+	server_response.Message = "reject"
+
+	if len(args) < 1 {
+		fmt.Println("Error: capacity confirmation has too few arguments")
+		return
+	}
+	counterparty_address := string(args[0])
+
+	s.mu.Lock()
+	pending_change, pending_ok := s.pending_capacity_changes[counterparty_address]
+	delete(s.pending_capacity_changes, counterparty_address)
+	onchain_state, ok := s.payment_channels_onchain_states[counterparty_address]
+	s.mu.Unlock()
+	if !pending_ok {
+		fmt.Printf("Error: no pending deposit or withdrawal of partner node %v\n", counterparty_address)
+		return
+	}
+	if !ok {
+		fmt.Printf("Error: payment channel with partner node %v does not exist\n", counterparty_address)
+		return
+	}
+
+	// check the new total deposit on chain
+	app_state, err := payment.ReadChannelAppState(s.algod_client, onchain_state.app_id)
+	if err != nil {
+		fmt.Printf("Error reading smart contract from blockchain: %v\n", err)
+		return
+	}
+	if app_state.TotalDeposit != pending_change.total_deposit {
+		fmt.Println("Error: capacity change was not found on chain")
+		return
+	}
+
+	// save new state and capacity
+	s.saveOffChainState(counterparty_address, pending_change.off_chain_state)
+	onchain_state.total_deposit = pending_change.total_deposit
+	s.mu.Lock()
+	s.payment_channels_onchain_states[counterparty_address] = onchain_state
+	s.mu.Unlock()
+	s.UpdateWatchtowerState()
+
+	fmt.Printf("Total deposit of app_id %d changed to %d microalgos\n\n", onchain_state.app_id, onchain_state.total_deposit)
+
+	server_response.Message = "approve"
+	return server_response
+}
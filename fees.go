@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// channelFees are the blockchain fees I paid for the on-chain operations of one channel
+type channelFees struct {
+	partner_address string
+	total_fee       uint64 // microalgos
+}
+
+// recordChannelFee adds the fee of an on-chain operation to the cumulative fees of the channel app_id
+func (s *server) recordChannelFee(app_id uint64, partner_address string, fee uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channel_fees, ok := s.channel_fees[app_id]
+	if !ok {
+		channel_fees = &channelFees{partner_address: partner_address}
+		s.channel_fees[app_id] = channel_fees
+	}
+	channel_fees.total_fee += fee
+	fmt.Printf("Paid %d microalgos blockchain fees for app_id %d, %d in total\n", fee, app_id, channel_fees.total_fee)
+}
+
+func (r *rpcServer) ListChannelFees(ctx context.Context, in *asrpc.ListChannelFeesRequest) (*asrpc.ListChannelFeesResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	channel_fees := make([]*asrpc.ChannelFee, 0)
+	total_blockchain_fee := uint64(0)
+	r.server.mu.Lock()
+	for app_id, fees := range r.server.channel_fees {
+		if in.AlgoAddress != "" && in.AlgoAddress != fees.partner_address {
+			continue
+		}
+		channel_fees = append(channel_fees, &asrpc.ChannelFee{
+			AppId:          app_id,
+			PartnerAddress: fees.partner_address,
+			BlockchainFee:  fees.total_fee,
+		})
+		total_blockchain_fee += fees.total_fee
+	}
+	r.server.mu.Unlock()
+	sort.Slice(channel_fees, func(i, j int) bool {
+		return channel_fees[i].AppId < channel_fees[j].AppId
+	})
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	return &asrpc.ListChannelFeesResponse{
+		ChannelFees:        channel_fees,
+		TotalBlockchainFee: total_blockchain_fee,
+		RuntimeRecording:   runtime_recording,
+	}, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/dancodery/algorand-state-channels/payment"
+)
+
+const (
+	HTLC_UPDATE_RETRIES     = 5
+	HTLC_UPDATE_RETRY_DELAY = 500 * time.Millisecond
+)
+
+// forwardingPolicy defines what a node charges for forwarding multi-hop payments
+type forwardingPolicy struct {
+	base_fee       uint64 // microalgos per forwarded payment
+	fee_ppm        uint64 // parts per million of the forwarded amount
+	timelock_delta uint64 // rounds between the incoming and the outgoing htlc
+}
+
+func (p forwardingPolicy) fee(amount uint64) uint64 {
+	return p.base_fee + amount*p.fee_ppm/1_000_000
+}
+
+// forwardedHTLC links an incoming htlc to the outgoing htlc I offered for it
+type forwardedHTLC struct {
+	incoming_address string
+	outgoing_address string
+}
+
+// pendingPayment is a multi-hop payment I sent, which waits for its outgoing htlc to be resolved
+type pendingPayment struct {
+	first_hop string
+	result    chan []byte // the preimage, or nil if the payment failed
+}
+
+// queryForwardingPolicy asks a node for its forwarding fees and timelock delta
+func (s *server) queryForwardingPolicy(host string) (forwardingPolicy, error) {
+	server_response, err := s.sendRequest(host, P2PRequest{Command: "forwarding_policy_request"})
+	if err != nil {
+		return forwardingPolicy{}, err
+	}
+	if server_response.Message != "approve" || len(server_response.Data) != 3 {
+		return forwardingPolicy{}, fmt.Errorf("node %v did not return its forwarding policy", host)
+	}
+	return forwardingPolicy{
+		base_fee:       binary.BigEndian.Uint64(server_response.Data[0]),
+		fee_ppm:        binary.BigEndian.Uint64(server_response.Data[1]),
+		timelock_delta: binary.BigEndian.Uint64(server_response.Data[2]),
+	}, nil
+}
+
+// htlcForwardingHook returns the action to take after an htlc update of the channel partner was accepted
+func (s *server) htlcForwardingHook(command string, htlc payment.HTLC, args [][]byte) func() {
+	counterparty_address := string(args[0])
+	switch command {
+	case "add_htlc_request":
+		// htlcs without an onion are direct conditional payments
+		if len(args) > 6 {
+			onion := args[6]
+			return func() { s.handleIncomingHTLC(counterparty_address, htlc, onion) }
+		}
+	case "settle_htlc_request":
+		preimage := args[6]
+		return func() { s.resolveOutgoingHTLC(counterparty_address, htlc.Hashlock, preimage) }
+	case "fail_htlc_request":
+		return func() { s.resolveOutgoingHTLC(counterparty_address, htlc.Hashlock, nil) }
+	}
+	return nil
+}
+
+// handleIncomingHTLC settles an htlc addressed to me or forwards it to the next hop of its onion
+func (s *server) handleIncomingHTLC(upstream_address string, htlc payment.HTLC, onion []byte) {
+	payload, err := peelOnion(s.account(), onion)
+	if err != nil {
+		fmt.Printf("Error peeling onion: %v\n", err)
+		s.failUpstream(upstream_address, htlc.Hashlock)
+		return
+	}
+
+	// 1. I am the final hop
+	if payload.next_address == "" {
+		if htlc.Amount < payload.amount || htlc.Timelock < payload.timelock {
+			fmt.Printf("Error: htlc with hashlock %x does not match its onion\n", htlc.Hashlock)
+			s.failUpstream(upstream_address, htlc.Hashlock)
+			return
+		}
+		preimage := payload.preimage
+		var paid_invoice *invoice
+		if s.hasInvoice(htlc.Hashlock) {
+			paid_invoice, err = s.payableInvoice(htlc.Hashlock, htlc.Amount)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				s.failUpstream(upstream_address, htlc.Hashlock)
+				return
+			}
+			preimage = paid_invoice.preimage
+		}
+		if preimage == nil {
+			preimage, _ = s.knownPreimage(htlc.Hashlock)
+		}
+		if preimage == nil || payment.HashPreimage(preimage) != htlc.Hashlock {
+			fmt.Printf("Error: unknown preimage for hashlock %x\n", htlc.Hashlock)
+			s.failUpstream(upstream_address, htlc.Hashlock)
+			return
+		}
+		err = retryHTLCUpdate(func() error {
+			_, err := s.settleIncomingHTLC(upstream_address, preimage, nil)
+			return err
+		})
+		if err != nil {
+			fmt.Printf("Error settling htlc: %v\n", err)
+			return
+		}
+		if paid_invoice != nil {
+			s.settleInvoice(paid_invoice, htlc.Amount)
+		}
+		fmt.Printf("Received multi-hop payment of %v microalgos with hashlock %x\n\n", htlc.Amount, htlc.Hashlock)
+		return
+	}
+
+	// 2. the incoming htlc must pay my fee and leave me enough time to settle it after the outgoing htlc
+	if htlc.Amount < payload.amount+s.forwarding_policy.fee(payload.amount) ||
+		htlc.Timelock < payload.timelock+s.forwarding_policy.timelock_delta {
+		fmt.Printf("Error: htlc with hashlock %x does not meet my forwarding policy\n", htlc.Hashlock)
+		s.failUpstream(upstream_address, htlc.Hashlock)
+		return
+	}
+
+	// 3. offer the outgoing htlc to the next hop
+	s.mu.Lock()
+	s.forwarded_htlcs[htlc.Hashlock] = forwardedHTLC{
+		incoming_address: upstream_address,
+		outgoing_address: payload.next_address,
+	}
+	s.mu.Unlock()
+	_, err = s.addOutgoingHTLC(payload.next_address, payload.amount, htlc.Hashlock, payload.timelock, payload.onion, nil)
+	if err != nil {
+		fmt.Printf("Error forwarding htlc: %v\n", err)
+		s.mu.Lock()
+		delete(s.forwarded_htlcs, htlc.Hashlock)
+		s.mu.Unlock()
+		s.failUpstream(upstream_address, htlc.Hashlock)
+		return
+	}
+	fmt.Printf("Forwarded htlc of %v microalgos with hashlock %x to %v\n\n", payload.amount, htlc.Hashlock, payload.next_address)
+}
+
+// resolveOutgoingHTLC passes the settlement or failure of one of my outgoing htlcs back to where it came from
+func (s *server) resolveOutgoingHTLC(downstream_address string, hashlock [32]byte, preimage []byte) {
+	s.mu.Lock()
+	forwarded, forwarded_ok := s.forwarded_htlcs[hashlock]
+	forwarded_ok = forwarded_ok && forwarded.outgoing_address == downstream_address
+	pending, pending_ok := s.pending_payments[hashlock]
+	pending_ok = !forwarded_ok && pending_ok && pending.first_hop == downstream_address
+	if forwarded_ok {
+		delete(s.forwarded_htlcs, hashlock)
+	}
+	if pending_ok {
+		delete(s.pending_payments, hashlock)
+	}
+	s.mu.Unlock()
+
+	if forwarded_ok {
+		if preimage == nil {
+			s.failUpstream(forwarded.incoming_address, hashlock)
+			return
+		}
+		err := retryHTLCUpdate(func() error {
+			_, err := s.settleIncomingHTLC(forwarded.incoming_address, preimage, nil)
+			return err
+		})
+		if err != nil {
+			fmt.Printf("Error settling htlc upstream: %v\n", err)
+		}
+		return
+	}
+
+	if pending_ok {
+		pending.result <- preimage
+	}
+}
+
+// failUpstream fails the incoming htlc, so that the upstream node gets its amount back
+func (s *server) failUpstream(upstream_address string, hashlock [32]byte) {
+	err := retryHTLCUpdate(func() error {
+		_, err := s.failHTLC(upstream_address, hashlock, nil)
+		return err
+	})
+	if err != nil {
+		fmt.Printf("Error failing htlc upstream: %v\n", err)
+	}
+}
+
+// retryHTLCUpdate retries a state update the channel partner rejected, because it was updating the channel itself at the same time.
+// My own updates of a channel wait for each other, see lockChannel.
+func retryHTLCUpdate(update func() error) (err error) {
+	for i := 0; i < HTLC_UPDATE_RETRIES; i++ {
+		err = update()
+		if err == nil {
+			return nil
+		}
+		time.Sleep(HTLC_UPDATE_RETRY_DELAY)
+	}
+	return err
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/dancodery/algorand-state-channels/payment/simulator"
+	sandbox "github.com/dancodery/algorand-state-channels/payment/testing"
+)
+
+// linkConditions are the faults of the in-memory network, drawn from the seeded random source of the simulation
+type linkConditions struct {
+	latency        time.Duration // delay of every request
+	jitter         time.Duration // random extra delay, which reorders concurrent requests
+	drop_requests  float64       // probability that a request is lost before it reaches the partner node
+	drop_responses float64       // probability that the response is lost after the partner node processed the request
+}
+
+// memoryNetwork connects the nodes of a simulation in memory, every node reaches the others by their host name
+type memoryNetwork struct {
+	mu        sync.Mutex
+	random    *rand.Rand
+	link      linkConditions
+	listeners map[string]*memoryListener // by host
+
+	dropped_requests  int
+	dropped_responses int
+}
+
+func newMemoryNetwork(seed int64, link linkConditions) *memoryNetwork {
+	return &memoryNetwork{
+		random:    rand.New(rand.NewSource(seed)),
+		link:      link,
+		listeners: make(map[string]*memoryListener),
+	}
+}
+
+// setLink changes the faults of all following requests
+func (n *memoryNetwork) setLink(link linkConditions) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.link = link
+}
+
+// endpoint is the peer network of the node with the given host
+func (n *memoryNetwork) endpoint(host string) peerNetwork {
+	return &memoryEndpoint{network: n, host: host}
+}
+
+type memoryEndpoint struct {
+	network *memoryNetwork
+	host    string
+}
+
+func (e *memoryEndpoint) Listen(port int) (net.Listener, error) {
+	e.network.mu.Lock()
+	defer e.network.mu.Unlock()
+	if _, ok := e.network.listeners[e.host]; ok {
+		return nil, fmt.Errorf("listen %s: address already in use", e.host)
+	}
+	listener := &memoryListener{
+		network: e.network,
+		addr:    memoryAddr(e.host),
+		conns:   make(chan net.Conn),
+		closed:  make(chan struct{}),
+	}
+	e.network.listeners[e.host] = listener
+	return listener, nil
+}
+
+// Dial delivers the connection to the listener of the host after the latency of the link, unless the request is dropped
+func (e *memoryEndpoint) Dial(host string, timeout time.Duration) (net.Conn, error) {
+	n := e.network
+	n.mu.Lock()
+	listener := n.listeners[host]
+	delay := n.link.latency
+	if n.link.jitter > 0 {
+		delay += time.Duration(n.random.Int63n(int64(n.link.jitter)))
+	}
+	drop_request := n.random.Float64() < n.link.drop_requests
+	drop_response := n.random.Float64() < n.link.drop_responses
+	if drop_request {
+		n.dropped_requests++
+	} else if drop_response {
+		n.dropped_responses++
+	}
+	n.mu.Unlock()
+
+	if listener == nil {
+		return nil, fmt.Errorf("dial %s: no such host", host)
+	}
+	if timeout > 0 && delay > timeout {
+		time.Sleep(timeout)
+		return nil, fmt.Errorf("dial %s: i/o timeout", host)
+	}
+	time.Sleep(delay)
+	if drop_request {
+		return nil, fmt.Errorf("dial %s: request dropped", host)
+	}
+
+	client_end, server_end := net.Pipe()
+	select {
+	case listener.conns <- &memoryConn{Conn: server_end, local: memoryAddr(host), remote: memoryAddr(e.host)}:
+	case <-listener.closed:
+		return nil, fmt.Errorf("dial %s: connection refused", host)
+	}
+	return &memoryConn{Conn: client_end, local: memoryAddr(e.host), remote: memoryAddr(host), drop_response: drop_response}, nil
+}
+
+type memoryAddr string
+
+func (a memoryAddr) Network() string {
+	return "memory"
+}
+
+func (a memoryAddr) String() string {
+	return string(a)
+}
+
+type memoryListener struct {
+	network    *memoryNetwork
+	addr       memoryAddr
+	conns      chan net.Conn
+	closed     chan struct{}
+	close_once sync.Once
+}
+
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.close_once.Do(func() {
+		close(l.closed)
+		l.network.mu.Lock()
+		delete(l.network.listeners, string(l.addr))
+		l.network.mu.Unlock()
+	})
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return l.addr
+}
+
+// memoryConn is one end of a connection, whose partner node is identified by its host
+type memoryConn struct {
+	net.Conn
+	local, remote memoryAddr
+	drop_response bool // the response is discarded once the partner node sent it
+}
+
+func (c *memoryConn) Read(b []byte) (int, error) {
+	if c.drop_response {
+		io.Copy(io.Discard, c.Conn)
+		return 0, fmt.Errorf("read %s: response dropped", c.remote)
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *memoryConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// chainClock is the time of a simulation. Rounds only pass when a test advances them, and the watchtowers
+// of the nodes check the channels right after, instead of on their own schedule.
+type chainClock struct {
+	ledger *simulator.Ledger
+	nodes  []*server
+}
+
+// After never fires, the loops of the watchtowers only end when their node is stopped
+func (c *chainClock) After(d time.Duration) <-chan time.Time {
+	return nil
+}
+
+// AdvanceRounds moves the chain forward and runs the watchtower of every node once
+func (c *chainClock) AdvanceRounds(rounds uint64) {
+	c.ledger.AdvanceRounds(rounds)
+	for _, node := range c.nodes {
+		node.watchChannels()
+	}
+}
+
+// simulation runs two nodes, alice and bob, in the test process on a simulated chain
+type simulation struct {
+	t       *testing.T
+	seed    int64
+	sandbox *sandbox.MockSandbox
+	network *memoryNetwork
+	clock   *chainClock
+
+	alice *simulationNode
+	bob   *simulationNode
+}
+
+type simulationNode struct {
+	host   string
+	server *server
+	rpc    *rpcServer
+}
+
+func newSimulation(t *testing.T, seed int64, link linkConditions) *simulation {
+	mock_sandbox := sandbox.StartMockSandbox()
+	t.Cleanup(mock_sandbox.Close)
+	t.Setenv("ALGOD_ADDRESS", mock_sandbox.AlgodAddress())
+	t.Setenv("KMD_ADDRESS", mock_sandbox.KmdAddress())
+	t.Setenv("SEED_PHRASE", "")
+
+	sim := &simulation{
+		t:       t,
+		seed:    seed,
+		sandbox: mock_sandbox,
+		network: newMemoryNetwork(seed, link),
+		clock:   &chainClock{ledger: mock_sandbox.Ledger},
+	}
+	sim.alice = sim.startNode("alice")
+	sim.bob = sim.startNode("bob")
+	return sim
+}
+
+// startNode starts a node in dev mode, with a new funded account
+func (sim *simulation) startNode(host string) *simulationNode {
+	s, err := initializeServer(&config{
+		GRPCPort:      DEFAULT_GRPC_PORT,
+		PeerPort:      DEFAULT_PEER_PORT,
+		Host:          host,
+		FeeBase:       DEFAULT_FEE_BASE,
+		FeePPM:        DEFAULT_FEE_PPM,
+		TimelockDelta: DEFAULT_TIMELOCK_DELTA,
+		DevMode:       true,
+		KeystorePath:  filepath.Join(sim.t.TempDir(), DEFAULT_KEYSTORE_FILE),
+		SignerBackend: SIGNER_BACKEND_KEYSTORE,
+	})
+	if err != nil {
+		sim.t.Fatalf("starting node %s: %v", host, err)
+	}
+	s.peer_network = sim.network.endpoint(host)
+	s.clock = sim.clock
+	err = s.listenForPeers()
+	if err != nil {
+		sim.t.Fatalf("listening for peers of node %s: %v", host, err)
+	}
+	sim.t.Cleanup(s.stop)
+	sim.clock.nodes = append(sim.clock.nodes, s)
+	return &simulationNode{host: host, server: s, rpc: s.rpcServer}
+}
+
+func (n *simulationNode) address() string {
+	return n.server.account().Address().String()
+}
+
+// latestState is the latest off-chain state of the channel with the partner node
+func (n *simulationNode) latestState(partner *simulationNode) *paymentChannelOffChainState {
+	_, latest_state, _, err := n.server.loadChannelState(partner.address())
+	if err != nil {
+		return nil
+	}
+	return latest_state
+}
+
+// openChannel opens a channel of alice with bob
+func (sim *simulation) openChannel(funding_amount uint64, penalty_reserve uint64, dispute_window uint64) uint64 {
+	return sim.openChannelBetween(sim.alice, sim.bob, funding_amount, penalty_reserve, dispute_window)
+}
+
+// openChannelBetween opens a channel of the opener, who funds it, with the partner
+func (sim *simulation) openChannelBetween(opener *simulationNode, partner *simulationNode, funding_amount uint64, penalty_reserve uint64, dispute_window uint64) uint64 {
+	response, err := opener.rpc.OpenChannel(context.Background(), &asrpc.OpenChannelRequest{
+		PartnerNode:    &asrpc.StateChannelNodeAddress{Host: partner.host, AlgoAddress: partner.address()},
+		FundingAmount:  funding_amount,
+		PenaltyReserve: penalty_reserve,
+		DisputeWindow:  dispute_window,
+	})
+	if err != nil {
+		sim.t.Fatalf("opening channel (seed %d): %v", sim.seed, err)
+	}
+	return response.AppId
+}
+
+func (sim *simulation) pay(payer *simulationNode, payee *simulationNode, amount uint64) error {
+	_, err := payer.rpc.Pay(context.Background(), &asrpc.PayRequest{AlgoAddress: payee.address(), Amount: amount})
+	return err
+}
+
+// assertConverged fails unless both nodes hold the same latest state of their channel
+func (sim *simulation) assertConverged() {
+	sim.t.Helper()
+	alice_state := sim.alice.latestState(sim.bob)
+	bob_state := sim.bob.latestState(sim.alice)
+	if alice_state == nil || bob_state == nil {
+		sim.t.Fatalf("a node has no channel state (seed %d)", sim.seed)
+	}
+	if alice_state.timestamp != bob_state.timestamp || alice_state.alice_balance != bob_state.alice_balance || alice_state.bob_balance != bob_state.bob_balance {
+		sim.t.Fatalf("states diverged (seed %d): alice holds %d/%d at %d, bob holds %d/%d at %d", sim.seed,
+			alice_state.alice_balance, alice_state.bob_balance, alice_state.timestamp,
+			bob_state.alice_balance, bob_state.bob_balance, bob_state.timestamp)
+	}
+}
+
+// balance is the on-chain balance of the account of the node
+func (sim *simulation) balance(node *simulationNode) uint64 {
+	return sim.sandbox.Ledger.Balance(node.server.account().Address())
+}
+
+var errNoChannel = errors.New("node has no channel")
+
+// channelInfo is the on-chain state of the channel the node keeps
+func (n *simulationNode) channelInfo(partner *simulationNode) (paymentChannelInfo, error) {
+	onchain_state, ok := n.server.getOnChainState(partner.address())
+	if !ok {
+		return paymentChannelInfo{}, errNoChannel
+	}
+	return onchain_state, nil
+}
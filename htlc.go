@@ -0,0 +1,601 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dancodery/algorand-state-channels/payment"
+)
+
+// lockChannel locks the channel with the given partner for an update of mine, from loading the latest state to saving the new one.
+// Updates of the same channel wait for each other instead of building on the same state. It returns the function unlocking the channel.
+func (s *server) lockChannel(counterparty_address string) func() {
+	channel_lock := s.channelLock(counterparty_address)
+	channel_lock.Lock()
+	return channel_lock.Unlock
+}
+
+// tryLockChannel locks the channel with the given partner for an update of the partner, unless I am updating it myself
+func (s *server) tryLockChannel(counterparty_address string) (unlock func(), ok bool) {
+	channel_lock := s.channelLock(counterparty_address)
+	if !channel_lock.TryLock() {
+		return nil, false
+	}
+	return channel_lock.Unlock, true
+}
+
+func (s *server) channelLock(counterparty_address string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channel_lock, ok := s.channel_locks[counterparty_address]
+	if !ok {
+		channel_lock = &sync.Mutex{}
+		s.channel_locks[counterparty_address] = channel_lock
+	}
+	return channel_lock
+}
+
+// loadChannelState returns the on chain state and the latest off chain state of the channel with the given partner
+func (s *server) loadChannelState(counterparty_address string) (onchain_state paymentChannelInfo, latest_offchain_state *paymentChannelOffChainState, me_alice bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	onchain_state, ok := s.payment_channels_onchain_states[counterparty_address]
+	if !ok {
+		return onchain_state, nil, false, fmt.Errorf("payment channel with partner node %v does not exist", counterparty_address)
+	}
+
+	payment_log, ok := s.payment_channels_offchain_states_log[counterparty_address]
+	if !ok {
+		return onchain_state, nil, false, fmt.Errorf("payment channel with partner node %v does not exist", counterparty_address)
+	}
+	latest_offchain_state, err = getLatestOffChainState(payment_log)
+	if err != nil {
+		return onchain_state, nil, false, err
+	}
+
+	me_alice = onchain_state.alice_address == s.account().Address().String()
+	return onchain_state, latest_offchain_state, me_alice, nil
+}
+
+// getOnChainState returns the on chain state of the channel with the given partner
+func (s *server) getOnChainState(counterparty_address string) (paymentChannelInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	onchain_state, ok := s.payment_channels_onchain_states[counterparty_address]
+	return onchain_state, ok
+}
+
+// saveOffChainState appends a new co-signed state to the off chain log of the channel
+func (s *server) saveOffChainState(counterparty_address string, off_chain_state paymentChannelOffChainState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.payment_channels_offchain_states_log[counterparty_address] == nil {
+		s.payment_channels_offchain_states_log[counterparty_address] = make(map[int64]paymentChannelOffChainState)
+	}
+	s.payment_channels_offchain_states_log[counterparty_address][off_chain_state.timestamp] = off_chain_state
+}
+
+// proposeStateUpdate signs a new state, sends it to the channel partner and stores it once the partner co-signed it.
+// The p2p request carries: my address, alice balance, bob balance, timestamp, htlcs, my signature, extra_args...
+func (s *server) proposeStateUpdate(
+	counterparty_address string,
+	command string,
+	new_alice_balance uint64,
+	new_bob_balance uint64,
+	new_htlcs []payment.HTLC,
+	extra_args [][]byte,
+	recorder *payment.PhaseRecorder,
+) error {
+	off_chain_state, err := s.cosignStateUpdate(counterparty_address, command, new_alice_balance, new_bob_balance, new_htlcs, extra_args, recorder)
+	if err != nil {
+		return err
+	}
+	s.saveOffChainState(counterparty_address, off_chain_state)
+
+	return nil
+}
+
+// cosignStateUpdate signs a new state and lets the channel partner co-sign it without storing it.
+// The phases of the exchange are recorded into recorder, which may be nil.
+func (s *server) cosignStateUpdate(
+	counterparty_address string,
+	command string,
+	new_alice_balance uint64,
+	new_bob_balance uint64,
+	new_htlcs []payment.HTLC,
+	extra_args [][]byte,
+	recorder *payment.PhaseRecorder,
+) (off_chain_state paymentChannelOffChainState, err error) {
+	onchain_state, _, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		return off_chain_state, err
+	}
+	timestamp_now := time.Now().UnixNano()
+
+	// 1. sign new state
+	my_signature, err := payment.SignState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		payment.WithPhaseRecorder(onchain_state.my_signer, recorder),
+		new_alice_balance,
+		new_bob_balance,
+		s.genesis_hash,
+		timestamp_now,
+		new_htlcs)
+	if err != nil {
+		return off_chain_state, err
+	}
+
+	// 2. send new state to partner node
+	args := [][]byte{
+		[]byte(s.account().Address().String()), // 1. my address
+		uint64ToBytes(new_alice_balance),       // 2. alice's new balance
+		uint64ToBytes(new_bob_balance),         // 3. bob's new balance
+		uint64ToBytes(uint64(timestamp_now)),   // 4. timestamp
+		payment.EncodeHTLCs(new_htlcs),         // 5. pending htlcs
+		my_signature,                           // 6. my signature
+	}
+	end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	server_response, err := s.sendRequest(onchain_state.partner_ip, P2PRequest{Command: command, Args: append(args, extra_args...)})
+	end_round_trip()
+	if err != nil {
+		return off_chain_state, err
+	}
+
+	// 3. read partner node's response
+	if server_response.Message != "approve" || len(server_response.Data) < 1 {
+		return off_chain_state, fmt.Errorf("partner node rejected %s", command)
+	}
+
+	// 4. verify partner node's signature
+	partner_signature := server_response.Data[0]
+	end_verification := recorder.Start(payment.PHASE_PARTNER_VERIFICATION)
+	partner_verified := payment.VerifyState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		new_alice_balance,
+		new_bob_balance,
+		s.genesis_hash,
+		partner_signature,
+		onchain_state.signingKey(counterparty_address),
+		timestamp_now,
+		new_htlcs)
+	end_verification()
+	if !partner_verified {
+		return off_chain_state, errors.New("partner node's signature is invalid")
+	}
+
+	// 5. assemble the co-signed state
+	off_chain_state = paymentChannelOffChainState{
+		timestamp: timestamp_now,
+
+		alice_balance: new_alice_balance,
+		bob_balance:   new_bob_balance,
+		htlcs:         new_htlcs,
+
+		genesis_hash: s.genesis_hash,
+		app_id:       onchain_state.app_id,
+	}
+	if me_alice {
+		off_chain_state.alice_signature = my_signature
+		off_chain_state.bob_signature = partner_signature
+	} else {
+		off_chain_state.alice_signature = partner_signature
+		off_chain_state.bob_signature = my_signature
+	}
+
+	return off_chain_state, nil
+}
+
+// processHTLCRequest handles add_htlc_request, settle_htlc_request and fail_htlc_request messages of the channel partner.
+// It returns the added or removed htlc if the new state was accepted.
+func (s *server) processHTLCRequest(command string, args [][]byte) (server_response P2PResponse, accepted_htlc *payment.HTLC) {
+	server_response.Message = "reject"
+
+	if len(args) < 6 {
+		fmt.Printf("Error: %s has too few arguments\n", command)
+		return
+	}
+	if !areUint64Args(args, 1, 2, 3) {
+		fmt.Printf("Error: %s has malformed balances or timestamp\n", command)
+		return
+	}
+	counterparty_address := string(args[0])
+	alice_new_balance := binary.BigEndian.Uint64(args[1])
+	bob_new_balance := binary.BigEndian.Uint64(args[2])
+	new_timestamp := int64(binary.BigEndian.Uint64(args[3]))
+	new_htlcs, err := payment.DecodeHTLCs(args[4])
+	if err != nil {
+		fmt.Printf("Error decoding htlcs: %v\n", err)
+		return
+	}
+	channel_partner_signature := args[5]
+
+	// 1. load on chain and latest off chain state
+	onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if s.capacityChangePending(counterparty_address) {
+		fmt.Println("Error: a deposit or withdrawal is pending")
+		return
+	}
+	if latestOffChainState.timestamp >= new_timestamp {
+		fmt.Println("Error: timestamp must be increasing")
+		return
+	}
+	current_round, err := s.getCurrentRound()
+	if err != nil {
+		fmt.Printf("Error getting current round: %v\n", err)
+		return
+	}
+
+	// 2. verify that the new state only differs in the requested htlc
+	var htlc payment.HTLC
+	var ok bool
+	switch command {
+	case "add_htlc_request":
+		htlc, ok = addedHTLC(latestOffChainState.htlcs, new_htlcs)
+		if !ok || htlc.OfferedByAlice == me_alice || htlc.Amount == 0 {
+			fmt.Println("Error: invalid htlc added")
+			return
+		}
+		// the offerer pays the amount into the htlc
+		if htlc.OfferedByAlice {
+			ok = htlc.Amount <= latestOffChainState.alice_balance &&
+				alice_new_balance == latestOffChainState.alice_balance-htlc.Amount &&
+				bob_new_balance == latestOffChainState.bob_balance &&
+				alice_new_balance >= onchain_state.penalty_reserve
+		} else {
+			ok = htlc.Amount <= latestOffChainState.bob_balance &&
+				bob_new_balance == latestOffChainState.bob_balance-htlc.Amount &&
+				alice_new_balance == latestOffChainState.alice_balance &&
+				bob_new_balance >= onchain_state.penalty_reserve
+		}
+		// I must be able to settle the htlc on chain after a closing was initiated
+		ok = ok && htlc.Timelock > current_round+onchain_state.dispute_window
+
+	case "settle_htlc_request":
+		if len(args) < 7 {
+			fmt.Println("Error: settle_htlc_request without preimage")
+			return
+		}
+		preimage := args[6]
+		htlc, ok = removedHTLC(latestOffChainState.htlcs, new_htlcs)
+		if !ok || htlc.OfferedByAlice != me_alice || payment.HashPreimage(preimage) != htlc.Hashlock {
+			fmt.Println("Error: invalid htlc settled")
+			return
+		}
+		// the receiver gets the amount of the htlc
+		if htlc.OfferedByAlice {
+			ok = bob_new_balance == latestOffChainState.bob_balance+htlc.Amount &&
+				alice_new_balance == latestOffChainState.alice_balance
+		} else {
+			ok = alice_new_balance == latestOffChainState.alice_balance+htlc.Amount &&
+				bob_new_balance == latestOffChainState.bob_balance
+		}
+		ok = ok && current_round <= htlc.Timelock
+		if ok {
+			s.rememberPreimage(htlc.Hashlock, preimage)
+		}
+
+	case "fail_htlc_request":
+		htlc, ok = removedHTLC(latestOffChainState.htlcs, new_htlcs)
+		if !ok {
+			fmt.Println("Error: invalid htlc failed")
+			return
+		}
+		// the offerer gets the amount of the htlc back
+		if htlc.OfferedByAlice {
+			ok = alice_new_balance == latestOffChainState.alice_balance+htlc.Amount &&
+				bob_new_balance == latestOffChainState.bob_balance
+		} else {
+			ok = bob_new_balance == latestOffChainState.bob_balance+htlc.Amount &&
+				alice_new_balance == latestOffChainState.alice_balance
+		}
+		// my partner may only take back its own htlc once it expired
+		if htlc.OfferedByAlice != me_alice {
+			ok = ok && current_round > htlc.Timelock
+		}
+	}
+	if !ok {
+		fmt.Println("Error: invalid new balances")
+		return
+	}
+
+	// 3. verify channel partner signature
+	channel_partner_signature_correct := payment.VerifyState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		channel_partner_signature,
+		onchain_state.signingKey(counterparty_address),
+		new_timestamp,
+		new_htlcs,
+	)
+	if !channel_partner_signature_correct {
+		fmt.Println("Error: invalid channel partner signature")
+		return
+	}
+
+	// 4. sign the state as well
+	my_signature, err := payment.SignState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		onchain_state.my_signer,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		new_timestamp,
+		new_htlcs,
+	)
+	if err != nil {
+		fmt.Printf("Error signing state: %v\n", err)
+		return
+	}
+
+	// 5. save new state
+	off_chain_state := paymentChannelOffChainState{
+		timestamp: new_timestamp,
+
+		alice_balance: alice_new_balance,
+		bob_balance:   bob_new_balance,
+		htlcs:         new_htlcs,
+
+		genesis_hash: s.genesis_hash,
+		app_id:       onchain_state.app_id,
+	}
+	if me_alice {
+		off_chain_state.alice_signature = my_signature
+		off_chain_state.bob_signature = channel_partner_signature
+	} else {
+		off_chain_state.alice_signature = channel_partner_signature
+		off_chain_state.bob_signature = my_signature
+	}
+	s.saveOffChainState(counterparty_address, off_chain_state)
+
+	fmt.Printf("Processed %s of %d microalgos with hashlock %x\n\n", command, htlc.Amount, htlc.Hashlock)
+
+	server_response.Message = "approve"
+	server_response.Data = [][]byte{
+		my_signature,
+	}
+	return server_response, &htlc
+}
+
+// addOutgoingHTLC locks amount from my balance into a new htlc offered to the channel partner
+func (s *server) addOutgoingHTLC(counterparty_address string, amount uint64, hashlock [32]byte, timelock uint64, onion []byte, recorder *payment.PhaseRecorder) (*payment.HTLC, error) {
+	unlock_channel := s.lockChannel(counterparty_address)
+	defer unlock_channel()
+	onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		return nil, err
+	}
+	if len(latestOffChainState.htlcs) >= payment.MAX_HTLCS {
+		return nil, fmt.Errorf("channel already has %d pending htlcs", len(latestOffChainState.htlcs))
+	}
+	if findHTLC(latestOffChainState.htlcs, hashlock) != -1 {
+		return nil, fmt.Errorf("htlc with payment hash %x already exists", hashlock)
+	}
+
+	// 1. the partner must be able to settle the htlc on chain after a closing was initiated
+	current_round, err := s.getCurrentRound()
+	if err != nil {
+		return nil, err
+	}
+	if timelock <= current_round+onchain_state.dispute_window {
+		return nil, fmt.Errorf("htlc must not expire within the dispute window of %d rounds", onchain_state.dispute_window)
+	}
+
+	// 2. lock the amount from my balance into the htlc
+	var my_balance uint64
+	if me_alice {
+		my_balance = latestOffChainState.alice_balance
+	} else {
+		my_balance = latestOffChainState.bob_balance
+	}
+	if amount == 0 || my_balance < amount+onchain_state.penalty_reserve {
+		return nil, fmt.Errorf("not enough balance to add htlc of %d microalgos", amount)
+	}
+
+	htlc := payment.HTLC{
+		OfferedByAlice: me_alice,
+		Amount:         amount,
+		Hashlock:       hashlock,
+		Timelock:       timelock,
+	}
+	new_alice_balance := latestOffChainState.alice_balance
+	new_bob_balance := latestOffChainState.bob_balance
+	if me_alice {
+		new_alice_balance -= amount
+	} else {
+		new_bob_balance -= amount
+	}
+	new_htlcs := append(append([]payment.HTLC{}, latestOffChainState.htlcs...), htlc)
+
+	// 3. let the partner co-sign the new state, the onion tells the partner where to forward the htlc
+	var extra_args [][]byte
+	if onion != nil {
+		extra_args = [][]byte{onion}
+	}
+	err = s.proposeStateUpdate(counterparty_address, "add_htlc_request", new_alice_balance, new_bob_balance, new_htlcs, extra_args, recorder)
+	if err != nil {
+		return nil, err
+	}
+	return &htlc, nil
+}
+
+// settleIncomingHTLC reveals the preimage of an htlc the channel partner offered to me and claims its amount
+func (s *server) settleIncomingHTLC(counterparty_address string, preimage []byte, recorder *payment.PhaseRecorder) (*payment.HTLC, error) {
+	unlock_channel := s.lockChannel(counterparty_address)
+	defer unlock_channel()
+	_, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. find the htlc my partner offered to me
+	hashlock := payment.HashPreimage(preimage)
+	index := findHTLC(latestOffChainState.htlcs, hashlock)
+	if index == -1 || latestOffChainState.htlcs[index].OfferedByAlice == me_alice {
+		return nil, fmt.Errorf("no incoming htlc with payment hash %x", hashlock)
+	}
+	htlc := latestOffChainState.htlcs[index]
+
+	// 2. the amount of the htlc goes to me
+	new_alice_balance := latestOffChainState.alice_balance
+	new_bob_balance := latestOffChainState.bob_balance
+	if me_alice {
+		new_alice_balance += htlc.Amount
+	} else {
+		new_bob_balance += htlc.Amount
+	}
+	new_htlcs := withoutHTLC(latestOffChainState.htlcs, index)
+	s.rememberPreimage(hashlock, preimage)
+
+	// 3. reveal the preimage and let the partner co-sign the new state
+	err = s.proposeStateUpdate(counterparty_address, "settle_htlc_request", new_alice_balance, new_bob_balance, new_htlcs, [][]byte{preimage}, recorder)
+	if err != nil {
+		return nil, err
+	}
+	return &htlc, nil
+}
+
+// failHTLC removes an htlc and refunds its amount to the offerer.
+// Incoming htlcs can always be failed, my own htlcs only once they expired.
+func (s *server) failHTLC(counterparty_address string, hashlock [32]byte, recorder *payment.PhaseRecorder) (*payment.HTLC, error) {
+	unlock_channel := s.lockChannel(counterparty_address)
+	defer unlock_channel()
+	_, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. find the htlc
+	index := findHTLC(latestOffChainState.htlcs, hashlock)
+	if index == -1 {
+		return nil, fmt.Errorf("no htlc with payment hash %x", hashlock)
+	}
+	htlc := latestOffChainState.htlcs[index]
+
+	// 2. my own htlcs can only be refunded to me once they expired
+	if htlc.OfferedByAlice == me_alice {
+		current_round, err := s.getCurrentRound()
+		if err != nil {
+			return nil, err
+		}
+		if current_round <= htlc.Timelock {
+			return nil, fmt.Errorf("htlc does not expire before round %d", htlc.Timelock)
+		}
+	}
+
+	// 3. the amount of the htlc goes back to its offerer
+	new_alice_balance := latestOffChainState.alice_balance
+	new_bob_balance := latestOffChainState.bob_balance
+	if htlc.OfferedByAlice {
+		new_alice_balance += htlc.Amount
+	} else {
+		new_bob_balance += htlc.Amount
+	}
+	new_htlcs := withoutHTLC(latestOffChainState.htlcs, index)
+
+	err = s.proposeStateUpdate(counterparty_address, "fail_htlc_request", new_alice_balance, new_bob_balance, new_htlcs, nil, recorder)
+	if err != nil {
+		return nil, err
+	}
+	return &htlc, nil
+}
+
+// getOnChainHTLCs returns the pending htlcs of the state that was committed on chain during closing
+func (s *server) getOnChainHTLCs(counterparty_address string, onchain_timestamp int64) []payment.HTLC {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offchain_state, ok := s.payment_channels_offchain_states_log[counterparty_address][onchain_timestamp]
+	if !ok {
+		return nil
+	}
+	return offchain_state.htlcs
+}
+
+// rememberPreimage keeps a preimage I learned, to settle the htlc on chain if the channel is closed
+func (s *server) rememberPreimage(hashlock [32]byte, preimage []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.htlc_preimages[hashlock] = preimage
+}
+
+// knownPreimage returns the preimage of the hashlock if I learned it
+func (s *server) knownPreimage(hashlock [32]byte) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	preimage, ok := s.htlc_preimages[hashlock]
+	return preimage, ok
+}
+
+// findHTLC returns the index of the htlc with the given hashlock or -1
+func findHTLC(htlcs []payment.HTLC, hashlock [32]byte) int {
+	for i, htlc := range htlcs {
+		if htlc.Hashlock == hashlock {
+			return i
+		}
+	}
+	return -1
+}
+
+// withoutHTLC returns a copy of htlcs without the htlc at the given index
+func withoutHTLC(htlcs []payment.HTLC, index int) []payment.HTLC {
+	new_htlcs := make([]payment.HTLC, 0, len(htlcs)-1)
+	new_htlcs = append(new_htlcs, htlcs[:index]...)
+	return append(new_htlcs, htlcs[index+1:]...)
+}
+
+// addedHTLC checks that new_htlcs are old_htlcs with exactly one htlc appended and returns it
+func addedHTLC(old_htlcs []payment.HTLC, new_htlcs []payment.HTLC) (payment.HTLC, bool) {
+	if len(new_htlcs) != len(old_htlcs)+1 {
+		return payment.HTLC{}, false
+	}
+	if !bytes.Equal(payment.EncodeHTLCs(old_htlcs), payment.EncodeHTLCs(new_htlcs[:len(old_htlcs)])) {
+		return payment.HTLC{}, false
+	}
+	htlc := new_htlcs[len(old_htlcs)]
+	if findHTLC(old_htlcs, htlc.Hashlock) != -1 {
+		return payment.HTLC{}, false
+	}
+	return htlc, true
+}
+
+// removedHTLC checks that new_htlcs are old_htlcs with exactly one htlc removed and returns it
+func removedHTLC(old_htlcs []payment.HTLC, new_htlcs []payment.HTLC) (payment.HTLC, bool) {
+	if len(new_htlcs)+1 != len(old_htlcs) {
+		return payment.HTLC{}, false
+	}
+	for i := range old_htlcs {
+		if bytes.Equal(payment.EncodeHTLCs(withoutHTLC(old_htlcs, i)), payment.EncodeHTLCs(new_htlcs)) {
+			return old_htlcs[i], true
+		}
+	}
+	return payment.HTLC{}, false
+}
+
+// areUint64Args tells whether the arguments at the given indexes are 8 byte big endian integers
+func areUint64Args(args [][]byte, indexes ...int) bool {
+	for _, i := range indexes {
+		if i >= len(args) || len(args[i]) != 8 {
+			return false
+		}
+	}
+	return true
+}
+
+func uint64ToBytes(val uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, val)
+	return b
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/dancodery/algorand-state-channels/payment"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	PAYMENT_REQUEST_PREFIX = "asinv1"
+	DEFAULT_INVOICE_EXPIRY = 3600 // seconds
+)
+
+var payment_request_encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// invoice is a request for payment created by this node
+type invoice struct {
+	preimage     []byte
+	payment_hash [32]byte
+	amount       uint64
+	memo         string
+
+	creation_date int64  // unix timestamp in seconds
+	expiry        uint64 // seconds after the creation date
+	settle_date   int64  // unix timestamp in seconds, 0 while unsettled
+	amount_paid   uint64
+
+	payment_request string
+}
+
+func (i *invoice) state() asrpc.Invoice_InvoiceState {
+	if i.settle_date != 0 {
+		return asrpc.Invoice_SETTLED
+	}
+	if time.Now().Unix() > i.creation_date+int64(i.expiry) {
+		return asrpc.Invoice_EXPIRED
+	}
+	return asrpc.Invoice_OPEN
+}
+
+func (i *invoice) toRPC() *asrpc.Invoice {
+	rpc_invoice := &asrpc.Invoice{
+		PaymentHash:    i.payment_hash[:],
+		Preimage:       i.preimage,
+		Amount:         i.amount,
+		Memo:           i.memo,
+		State:          i.state(),
+		CreationDate:   timestamppb.New(time.Unix(i.creation_date, 0)),
+		Expiry:         i.expiry,
+		AmountPaid:     i.amount_paid,
+		PaymentRequest: i.payment_request,
+	}
+	if i.settle_date != 0 {
+		rpc_invoice.SettleDate = timestamppb.New(time.Unix(i.settle_date, 0))
+	}
+	return rpc_invoice
+}
+
+// paymentRequest is the content of an encoded invoice, which is handed to the payer
+type paymentRequest struct {
+	node_address string
+	node_host    string
+	amount       uint64
+	memo         string
+
+	creation_date int64
+	expiry        uint64
+	payment_hash  [32]byte
+}
+
+func (p *paymentRequest) expired() bool {
+	return time.Now().Unix() > p.creation_date+int64(p.expiry)
+}
+
+// payload serializes the payment request:
+// address (32 bytes) | host length (1 byte) | host | amount (8 bytes) | creation date (8 bytes) | expiry (8 bytes) | payment hash (32 bytes) | memo length (2 bytes) | memo
+func (p *paymentRequest) payload() ([]byte, error) {
+	if len(p.node_host) > 255 || len(p.memo) > 65535 {
+		return nil, errors.New("payment request field too long")
+	}
+	node_address, err := types.DecodeAddress(p.node_address)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append([]byte{}, node_address[:]...)
+	data = append(data, byte(len(p.node_host)))
+	data = append(data, []byte(p.node_host)...)
+	data = append(data, uint64ToBytes(p.amount)...)
+	data = append(data, uint64ToBytes(uint64(p.creation_date))...)
+	data = append(data, uint64ToBytes(p.expiry)...)
+	data = append(data, p.payment_hash[:]...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(p.memo)))
+	data = append(data, []byte(p.memo)...)
+	return data, nil
+}
+
+// encodePaymentRequest signs the payment request with the node's key and encodes it as text
+func encodePaymentRequest(account payment.Signer, p *paymentRequest) (string, error) {
+	data, err := p.payload()
+	if err != nil {
+		return "", err
+	}
+	signature, err := account.SignBytes(append([]byte("PAYMENT_REQUEST"), data...))
+	if err != nil {
+		return "", err
+	}
+	return PAYMENT_REQUEST_PREFIX + strings.ToLower(payment_request_encoding.EncodeToString(append(data, signature...))), nil
+}
+
+// decodePaymentRequest decodes a payment request and verifies that it was signed by the node it pays to
+func decodePaymentRequest(encoded string) (*paymentRequest, error) {
+	if !strings.HasPrefix(encoded, PAYMENT_REQUEST_PREFIX) {
+		return nil, errors.New("not a payment request")
+	}
+	data, err := payment_request_encoding.DecodeString(strings.ToUpper(strings.TrimPrefix(encoded, PAYMENT_REQUEST_PREFIX)))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 32+1+ed25519.SignatureSize {
+		return nil, errors.New("payment request too short")
+	}
+	signature := data[len(data)-ed25519.SignatureSize:]
+	data = data[:len(data)-ed25519.SignatureSize]
+
+	p := &paymentRequest{}
+	var node_address types.Address
+	copy(node_address[:], data[:32])
+	p.node_address = node_address.String()
+	if !ed25519.Verify(node_address[:], append([]byte("PAYMENT_REQUEST"), data...), signature) {
+		return nil, errors.New("invalid payment request signature")
+	}
+
+	host_length := int(data[32])
+	rest := data[33:]
+	if len(rest) < host_length+8+8+8+32+2 {
+		return nil, errors.New("payment request too short")
+	}
+	p.node_host = string(rest[:host_length])
+	rest = rest[host_length:]
+	p.amount = binary.BigEndian.Uint64(rest[:8])
+	p.creation_date = int64(binary.BigEndian.Uint64(rest[8:16]))
+	p.expiry = binary.BigEndian.Uint64(rest[16:24])
+	copy(p.payment_hash[:], rest[24:56])
+	memo_length := int(binary.BigEndian.Uint16(rest[56:58]))
+	rest = rest[58:]
+	if len(rest) != memo_length {
+		return nil, errors.New("invalid memo length")
+	}
+	p.memo = string(rest)
+	return p, nil
+}
+
+// addInvoice creates a new invoice with a random preimage
+func (s *server) addInvoice(amount uint64, memo string, expiry uint64) (*invoice, error) {
+	if expiry == 0 {
+		expiry = DEFAULT_INVOICE_EXPIRY
+	}
+	preimage := make([]byte, 32)
+	_, err := rand.Read(preimage)
+	if err != nil {
+		return nil, err
+	}
+
+	new_invoice := &invoice{
+		preimage:      preimage,
+		payment_hash:  payment.HashPreimage(preimage),
+		amount:        amount,
+		memo:          memo,
+		creation_date: time.Now().Unix(),
+		expiry:        expiry,
+	}
+	new_invoice.payment_request, err = encodePaymentRequest(s.account(), &paymentRequest{
+		node_address:  s.account().Address().String(),
+		node_host:     s.node_host,
+		amount:        amount,
+		memo:          memo,
+		creation_date: new_invoice.creation_date,
+		expiry:        expiry,
+		payment_hash:  new_invoice.payment_hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.invoices[new_invoice.payment_hash] = new_invoice
+	s.mu.Unlock()
+	return new_invoice, nil
+}
+
+// hasInvoice tells whether I issued an invoice with the given payment hash
+func (s *server) hasInvoice(payment_hash [32]byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.invoices[payment_hash]
+	return ok
+}
+
+// payableInvoice returns the open invoice with the given payment hash, if amount is enough to pay it
+func (s *server) payableInvoice(payment_hash [32]byte, amount uint64) (*invoice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing_invoice, ok := s.invoices[payment_hash]
+	if !ok {
+		return nil, fmt.Errorf("no invoice with payment hash %x", payment_hash)
+	}
+	if existing_invoice.state() != asrpc.Invoice_OPEN {
+		return nil, fmt.Errorf("invoice with payment hash %x is %v", payment_hash, existing_invoice.state())
+	}
+	if amount < existing_invoice.amount {
+		return nil, fmt.Errorf("invoice with payment hash %x requires %d microalgos", payment_hash, existing_invoice.amount)
+	}
+	return existing_invoice, nil
+}
+
+func (s *server) settleInvoice(settled_invoice *invoice, amount_paid uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settled_invoice.settle_date = time.Now().Unix()
+	settled_invoice.amount_paid = amount_paid
+	fmt.Printf("Settled invoice with payment hash %x, memo: %q\n", settled_invoice.payment_hash, settled_invoice.memo)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KEYSTORE_VERSION = 1
+
+	// scrypt parameters for deriving the keystore key from the password
+	KEYSTORE_SCRYPT_N = 1 << 17
+	KEYSTORE_SCRYPT_R = 8
+	KEYSTORE_SCRYPT_P = 1
+
+	MIN_PASSWORD_LENGTH = 8
+)
+
+// keystoreFile is the on-disk format of the encrypted node account
+type keystoreFile struct {
+	Version int    `json:"version"`
+	Address string `json:"address"` // authenticated as additional data
+
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	ScryptN int    `json:"scrypt_n"`
+	ScryptR int    `json:"scrypt_r"`
+	ScryptP int    `json:"scrypt_p"`
+
+	Cipher     string `json:"cipher"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"` // encrypted ed25519 seed
+}
+
+// createKeystore encrypts the account with the password and writes it to path, it never overwrites an existing keystore
+func createKeystore(path string, password string, account crypto.Account) error {
+	if len(password) < MIN_PASSWORD_LENGTH {
+		return fmt.Errorf("password must have at least %d characters", MIN_PASSWORD_LENGTH)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("keystore %s already exists", path)
+	}
+
+	// 1. derive the encryption key
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := scrypt.Key([]byte(password), salt, KEYSTORE_SCRYPT_N, KEYSTORE_SCRYPT_R, KEYSTORE_SCRYPT_P, chacha20poly1305.KeySize)
+	if err != nil {
+		return err
+	}
+
+	// 2. encrypt the seed of the private key
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	address := account.Address.String()
+	ciphertext := aead.Seal(nil, nonce, account.PrivateKey.Seed(), []byte(address))
+
+	keystore_file := keystoreFile{
+		Version: KEYSTORE_VERSION,
+		Address: address,
+
+		KDF:     "scrypt",
+		Salt:    salt,
+		ScryptN: KEYSTORE_SCRYPT_N,
+		ScryptR: KEYSTORE_SCRYPT_R,
+		ScryptP: KEYSTORE_SCRYPT_P,
+
+		Cipher:     "xchacha20-poly1305",
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}
+	keystore_json, err := json.MarshalIndent(keystore_file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// 3. write the keystore, readable only by me
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, keystore_json, 0600)
+}
+
+// loadKeystore decrypts the account stored at path with the password
+func loadKeystore(path string, password string) (crypto.Account, error) {
+	keystore_json, err := os.ReadFile(path)
+	if err != nil {
+		return crypto.Account{}, err
+	}
+	var keystore_file keystoreFile
+	if err := json.Unmarshal(keystore_json, &keystore_file); err != nil {
+		return crypto.Account{}, err
+	}
+	if keystore_file.Version != KEYSTORE_VERSION || keystore_file.KDF != "scrypt" || keystore_file.Cipher != "xchacha20-poly1305" {
+		return crypto.Account{}, errors.New("unsupported keystore format")
+	}
+
+	// 1. derive the encryption key
+	key, err := scrypt.Key([]byte(password), keystore_file.Salt, keystore_file.ScryptN, keystore_file.ScryptR, keystore_file.ScryptP, chacha20poly1305.KeySize)
+	if err != nil {
+		return crypto.Account{}, err
+	}
+
+	// 2. decrypt the seed, fails for a wrong password or a modified keystore
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return crypto.Account{}, err
+	}
+	seed, err := aead.Open(nil, keystore_file.Nonce, keystore_file.Ciphertext, []byte(keystore_file.Address))
+	if err != nil {
+		return crypto.Account{}, errors.New("invalid password")
+	}
+	if len(seed) != ed25519.SeedSize {
+		return crypto.Account{}, errors.New("invalid keystore seed")
+	}
+
+	account, err := crypto.AccountFromPrivateKey(ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		return crypto.Account{}, err
+	}
+	if account.Address.String() != keystore_file.Address {
+		return crypto.Account{}, errors.New("keystore address does not match its key")
+	}
+	return account, nil
+}
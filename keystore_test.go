@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+)
+
+const testKeystorePassword = "correct horse battery"
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asc", DEFAULT_KEYSTORE_FILE)
+	account := crypto.GenerateAccount()
+	err := createKeystore(path, testKeystorePassword, account)
+	if err != nil {
+		t.Fatalf("creating keystore: %v", err)
+	}
+
+	loaded_account, err := loadKeystore(path, testKeystorePassword)
+	if err != nil {
+		t.Fatalf("loading keystore: %v", err)
+	}
+	if loaded_account.Address != account.Address || !loaded_account.PrivateKey.Equal(account.PrivateKey) {
+		t.Errorf("loaded account %s, created %s", loaded_account.Address, account.Address)
+	}
+
+	// an existing keystore is never overwritten
+	err = createKeystore(path, testKeystorePassword, crypto.GenerateAccount())
+	if err == nil {
+		t.Errorf("overwrote the keystore")
+	}
+	if loaded_account, err = loadKeystore(path, testKeystorePassword); err != nil || loaded_account.Address != account.Address {
+		t.Errorf("keystore holds %s after the second create, expected %s", loaded_account.Address, account.Address)
+	}
+}
+
+func TestKeystoreWrongPassword(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DEFAULT_KEYSTORE_FILE)
+	err := createKeystore(path, "short", crypto.GenerateAccount())
+	if err == nil {
+		t.Errorf("created a keystore with a password of 5 characters")
+	}
+	err = createKeystore(path, testKeystorePassword, crypto.GenerateAccount())
+	if err != nil {
+		t.Fatalf("creating keystore: %v", err)
+	}
+
+	_, err = loadKeystore(path, testKeystorePassword+"!")
+	if err == nil {
+		t.Errorf("loaded the keystore with a wrong password")
+	}
+
+	// the address is authenticated, a keystore claiming another address does not decrypt
+	keystore_json, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading keystore: %v", err)
+	}
+	var keystore_file keystoreFile
+	if err := json.Unmarshal(keystore_json, &keystore_file); err != nil {
+		t.Fatalf("decoding keystore: %v", err)
+	}
+	keystore_file.Address = crypto.GenerateAccount().Address.String()
+	keystore_json, err = json.Marshal(keystore_file)
+	if err != nil {
+		t.Fatalf("encoding keystore: %v", err)
+	}
+	if err := os.WriteFile(path, keystore_json, 0600); err != nil {
+		t.Fatalf("writing keystore: %v", err)
+	}
+	_, err = loadKeystore(path, testKeystorePassword)
+	if err == nil {
+		t.Errorf("loaded a keystore with a modified address")
+	}
+}
+
+func TestKeystoreFilePermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "asc")
+	path := filepath.Join(dir, DEFAULT_KEYSTORE_FILE)
+	err := createKeystore(path, testKeystorePassword, crypto.GenerateAccount())
+	if err != nil {
+		t.Fatalf("creating keystore: %v", err)
+	}
+
+	// only the node user may read the keystore
+	file_info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("reading keystore: %v", err)
+	}
+	if mode := file_info.Mode().Perm(); mode != 0600 {
+		t.Errorf("keystore has mode %o, expected 600", mode)
+	}
+	dir_info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("reading keystore dir: %v", err)
+	}
+	if mode := dir_info.Mode().Perm(); mode != 0700 {
+		t.Errorf("keystore dir has mode %o, expected 700", mode)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/dancodery/algorand-state-channels/payment"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// hopPayload is the per-hop instruction of a multi-hop payment. Every hop can only
+// decrypt its own payload, which contains the encrypted onion for the next hop.
+type hopPayload struct {
+	next_address string // empty for the final hop
+
+	amount   uint64 // amount of the outgoing htlc, or the amount the final hop receives
+	timelock uint64 // timelock of the outgoing htlc, or the timelock expected by the final hop
+
+	preimage []byte // only set for spontaneous payments to the final hop
+	onion    []byte // encrypted payload for the next hop
+}
+
+// encode serializes the payload:
+// address (32 bytes) | amount (8 bytes) | timelock (8 bytes) | preimage length (1 byte) | preimage | onion
+func (p *hopPayload) encode() ([]byte, error) {
+	if len(p.preimage) > 255 {
+		return nil, errors.New("preimage too long")
+	}
+	var next_address types.Address
+	if p.next_address != "" {
+		var err error
+		next_address, err = types.DecodeAddress(p.next_address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := append([]byte{}, next_address[:]...)
+	data = append(data, uint64ToBytes(p.amount)...)
+	data = append(data, uint64ToBytes(p.timelock)...)
+	data = append(data, byte(len(p.preimage)))
+	data = append(data, p.preimage...)
+	data = append(data, p.onion...)
+	return data, nil
+}
+
+func decodeHopPayload(data []byte) (*hopPayload, error) {
+	p := &hopPayload{}
+	if len(data) < 32+8+8+1 {
+		return nil, errors.New("hop payload too short")
+	}
+
+	var next_address types.Address
+	copy(next_address[:], data[:32])
+	if !next_address.IsZero() {
+		p.next_address = next_address.String()
+	}
+	data = data[32:]
+
+	p.amount = binary.BigEndian.Uint64(data[:8])
+	p.timelock = binary.BigEndian.Uint64(data[8:16])
+	data = data[16:]
+
+	preimage_length := int(data[0])
+	data = data[1:]
+	if len(data) < preimage_length {
+		return nil, errors.New("hop payload too short")
+	}
+	if preimage_length > 0 {
+		p.preimage = data[:preimage_length]
+	}
+	if len(data) > preimage_length {
+		p.onion = data[preimage_length:]
+	}
+	return p, nil
+}
+
+// buildOnion wraps the payloads into each other, so that payloads[i] can only be read by hop_addresses[i]
+func buildOnion(hop_addresses []string, payloads []*hopPayload) ([]byte, error) {
+	if len(hop_addresses) != len(payloads) || len(payloads) == 0 {
+		return nil, errors.New("every hop needs exactly one payload")
+	}
+
+	var onion []byte
+	for i := len(payloads) - 1; i >= 0; i-- {
+		payloads[i].onion = onion
+
+		data, err := payloads[i].encode()
+		if err != nil {
+			return nil, err
+		}
+		hop_key, err := addressToX25519(hop_addresses[i])
+		if err != nil {
+			return nil, err
+		}
+		onion, err = box.SealAnonymous(nil, data, hop_key, rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return onion, nil
+}
+
+// peelOnion decrypts the outermost layer of the onion with my own key
+func peelOnion(account payment.Signer, onion []byte) (*hopPayload, error) {
+	private_key, err := privateKeyToX25519(account)
+	if err != nil {
+		return nil, err
+	}
+	public_key, err := curve25519.X25519(private_key[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	var public_key_array [32]byte
+	copy(public_key_array[:], public_key)
+
+	data, ok := box.OpenAnonymous(nil, onion, &public_key_array, &private_key)
+	if !ok {
+		return nil, errors.New("onion could not be decrypted")
+	}
+	return decodeHopPayload(data)
+}
+
+// field prime of curve25519: 2^255 - 19
+var curve25519_p = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// addressToX25519 converts the ed25519 public key of an algo address into its x25519 public key,
+// using the birational map u = (1 + y) / (1 - y) between edwards25519 and curve25519
+func addressToX25519(algo_address string) (*[32]byte, error) {
+	address, err := types.DecodeAddress(algo_address)
+	if err != nil {
+		return nil, err
+	}
+
+	// y is encoded little endian, the highest bit is the sign of x
+	y_bytes := make([]byte, 32)
+	for i := 0; i < 32; i++ {
+		y_bytes[i] = address[31-i]
+	}
+	y_bytes[0] &= 0x7f
+	y := new(big.Int).SetBytes(y_bytes)
+
+	numerator := new(big.Int).Add(big.NewInt(1), y)
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, curve25519_p)
+	if denominator.Sign() == 0 {
+		return nil, errors.New("invalid public key")
+	}
+	denominator.ModInverse(denominator, curve25519_p)
+	u := numerator.Mul(numerator, denominator)
+	u.Mod(u, curve25519_p)
+
+	var u_bytes [32]byte
+	u_big_endian := u.FillBytes(make([]byte, 32))
+	for i := 0; i < 32; i++ {
+		u_bytes[i] = u_big_endian[31-i]
+	}
+	return &u_bytes, nil
+}
+
+// privateKeyToX25519 derives the x25519 private key belonging to the ed25519 key of the account,
+// which requires a signer that can export its key
+func privateKeyToX25519(account payment.Signer) ([32]byte, error) {
+	var scalar [32]byte
+	key_exporter, ok := account.(payment.KeyExporter)
+	if !ok {
+		return scalar, errors.New("signer cannot export the key to decrypt onions")
+	}
+	private_key, err := key_exporter.ExportPrivateKey()
+	if err != nil {
+		return scalar, err
+	}
+	hash := sha512.Sum512(private_key.Seed())
+
+	copy(scalar[:], hash[:32])
+	scalar[0] &= 248
+	scalar[31] &= 127
+	scalar[31] |= 64
+	return scalar, nil
+}
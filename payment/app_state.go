@@ -0,0 +1,162 @@
+package payment
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// value types of models.TealValue
+const (
+	tealBytesType = 1
+	tealUintType  = 2
+)
+
+// ChannelAppState is the decoded global state of a payment channel app.
+// Keys the app has not written yet keep their zero value, e.g. bob_signing_key before bob accepted.
+type ChannelAppState struct {
+	AppID uint64
+
+	AliceAddress types.Address
+	BobAddress   types.Address
+
+	AliceSigningKey types.Address
+	BobSigningKey   types.Address
+	BobAccepted     bool
+
+	GenesisHash []byte
+	AssetID     uint64 // 0 for algo channels
+
+	PenaltyReserve uint64
+	DisputeWindow  uint64
+	TotalDeposit   uint64
+
+	Timeout          uint64 // round after which the channel can be finalized, 0 while the channel is open
+	ClosingInitiator string // "alice" or "bob", empty while the channel is open
+
+	LatestAliceBalance uint64
+	LatestBobBalance   uint64
+	LatestTimestamp    uint64
+	LatestHTLCsHash    []byte
+	SettledHTLCs       uint64 // bitmask of the htlcs of the latest state settled on chain
+}
+
+// channelAppStateKeys maps every global state key of the contract to its value type
+var channelAppStateKeys = map[string]uint64{
+	"alice_address":        tealBytesType,
+	"bob_address":          tealBytesType,
+	"alice_signing_key":    tealBytesType,
+	"bob_signing_key":      tealBytesType,
+	"bob_accepted":         tealUintType,
+	"genesis_hash":         tealBytesType,
+	"asset_id":             tealUintType,
+	"penalty_reserve":      tealUintType,
+	"dispute_window":       tealUintType,
+	"total_deposit":        tealUintType,
+	"timeout":              tealUintType,
+	"closing_initiator":    tealBytesType,
+	"latest_alice_balance": tealUintType,
+	"latest_bob_balance":   tealUintType,
+	"latest_timestamp":     tealUintType,
+	"latest_htlcs_hash":    tealBytesType,
+	"settled_htlcs":        tealUintType,
+}
+
+// ReadChannelAppState reads and decodes the global state of the channel app from the blockchain
+func ReadChannelAppState(algodClient *algod.Client, appID uint64) (ChannelAppState, error) {
+	app_info, err := algodClient.GetApplicationByID(appID).Do(context.Background())
+	if err != nil {
+		return ChannelAppState{}, err
+	}
+	return DecodeChannelAppState(app_info)
+}
+
+// DecodeChannelAppState decodes the global state of the channel app, it fails if the app
+// does not have the schema of the payment contract or holds keys the contract does not write
+func DecodeChannelAppState(app models.Application) (ChannelAppState, error) {
+	schema := app.Params.GlobalStateSchema
+	if schema.NumUint != NUM_UINTS || schema.NumByteSlice != NUM_BYTE_SLICES {
+		return ChannelAppState{}, fmt.Errorf("app %d has global schema %d uints and %d byte slices, expected %d and %d",
+			app.Id, schema.NumUint, schema.NumByteSlice, NUM_UINTS, NUM_BYTE_SLICES)
+	}
+
+	state := ChannelAppState{AppID: app.Id}
+	for _, teal_key_value := range app.Params.GlobalState {
+		decoded_key, err := base64.StdEncoding.DecodeString(teal_key_value.Key)
+		if err != nil {
+			return ChannelAppState{}, err
+		}
+		key := string(decoded_key)
+		expected_type, ok := channelAppStateKeys[key]
+		if !ok {
+			return ChannelAppState{}, fmt.Errorf("unknown key %q in the global state of app %d", key, app.Id)
+		}
+		if teal_key_value.Value.Type != expected_type {
+			return ChannelAppState{}, fmt.Errorf("key %q of app %d has type %d, expected %d", key, app.Id, teal_key_value.Value.Type, expected_type)
+		}
+
+		uint_value := teal_key_value.Value.Uint
+		bytes_value, err := base64.StdEncoding.DecodeString(teal_key_value.Value.Bytes)
+		if err != nil {
+			return ChannelAppState{}, err
+		}
+
+		switch key {
+		case "alice_address":
+			err = decodeAddressValue(bytes_value, &state.AliceAddress)
+		case "bob_address":
+			err = decodeAddressValue(bytes_value, &state.BobAddress)
+		case "alice_signing_key":
+			err = decodeAddressValue(bytes_value, &state.AliceSigningKey)
+		case "bob_signing_key":
+			err = decodeAddressValue(bytes_value, &state.BobSigningKey)
+		case "bob_accepted":
+			state.BobAccepted = uint_value == 1
+		case "genesis_hash":
+			state.GenesisHash = bytes_value
+		case "asset_id":
+			state.AssetID = uint_value
+		case "penalty_reserve":
+			state.PenaltyReserve = uint_value
+		case "dispute_window":
+			state.DisputeWindow = uint_value
+		case "total_deposit":
+			state.TotalDeposit = uint_value
+		case "timeout":
+			state.Timeout = uint_value
+		case "closing_initiator":
+			state.ClosingInitiator = string(bytes_value)
+		case "latest_alice_balance":
+			state.LatestAliceBalance = uint_value
+		case "latest_bob_balance":
+			state.LatestBobBalance = uint_value
+		case "latest_timestamp":
+			state.LatestTimestamp = uint_value
+		case "latest_htlcs_hash":
+			state.LatestHTLCsHash = bytes_value
+		case "settled_htlcs":
+			state.SettledHTLCs = uint_value
+		}
+		if err != nil {
+			return ChannelAppState{}, fmt.Errorf("key %q of app %d: %v", key, app.Id, err)
+		}
+	}
+	return state, nil
+}
+
+// IsClosing reports whether one of the parties initiated closing the channel
+func (c ChannelAppState) IsClosing() bool {
+	return c.Timeout > 0
+}
+
+func decodeAddressValue(value []byte, address *types.Address) error {
+	if len(value) != len(address) {
+		return fmt.Errorf("expected a %d byte address, got %d bytes", len(address), len(value))
+	}
+	copy(address[:], value)
+	return nil
+}
@@ -0,0 +1,45 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// minimum balance of an account, and the increase for every asset it is opted into
+const MIN_BALANCE = 100_000
+
+// the app account of an asa channel holds algos for its minimum balance and the fees of
+// the inner transactions: asset opt-in, two asset transfers and the closing payment
+func assetChannelAlgoReserve(min_fee uint64) uint64 {
+	return 2*MIN_BALANCE + 4*min_fee
+}
+
+func foreignAssets(asset_id uint64) []uint64 {
+	if asset_id == 0 {
+		return nil
+	}
+	return []uint64{asset_id}
+}
+
+// OptInAsset opts the account into an asset, so that it can receive its channel payout, and returns the fee paid
+func OptInAsset(algodClient *algod.Client, account Signer, assetID uint64) (uint64, error) {
+	sp, err := algodClient.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	optInTxn, err := transaction.MakeAssetAcceptanceTxn(account.Address().String(), nil, sp, assetID)
+	if err != nil {
+		return 0, err
+	}
+	_, fee, err := SignAndSendGroup(algodClient, account, []types.Transaction{optInTxn})
+	return fee, err
+}
+
+// IsOptedInAsset checks whether the account can receive the asset
+func IsOptedInAsset(algodClient *algod.Client, address string, assetID uint64) bool {
+	_, err := algodClient.AccountAssetInformation(address, assetID).Do(context.Background())
+	return err == nil
+}
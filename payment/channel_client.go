@@ -0,0 +1,597 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ABI_RETURN_PREFIX starts the log holding the return value of an ARC-4 method call
+var ABI_RETURN_PREFIX = []byte{0x15, 0x1f, 0x7c, 0x75}
+
+// ChannelClient calls the methods of a payment channel app through the ARC-4 interface of the latest contract version.
+// Every call is composed with the atomic transaction composer, padded with increaseBudget calls if simulation
+// demands it, signed by the sender and submitted. It returns the decoded return value and the fee paid by the group.
+type ChannelClient struct {
+	algod_client *algod.Client
+	contract     *abi.Contract
+	app_id       uint64
+	sender       Signer
+}
+
+// NewChannelClient returns a client calling the app appID as sender, a new channel is created with an appID of 0
+func NewChannelClient(algodClient *algod.Client, appID uint64, sender Signer) *ChannelClient {
+	return &ChannelClient{
+		algod_client: algodClient,
+		contract:     LatestContractVersion().Interface,
+		app_id:       appID,
+		sender:       sender,
+	}
+}
+
+// AppID is the app the client calls, set by Create and CreateAndFund
+func (c *ChannelClient) AppID() uint64 {
+	return c.app_id
+}
+
+// Create deploys a new channel app between the sender as alice and partnerAddress as bob and returns its id
+func (c *ChannelClient) Create(
+	partnerAddress string,
+	penaltyReserve uint64,
+	disputeWindow uint64,
+	assetID uint64,
+	aliceSigningKey types.Address) (appID uint64, fee uint64, err error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	create_call, err := c.createCall(sp, partnerAddress, penaltyReserve, disputeWindow, assetID, aliceSigningKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	group := channelGroup{}
+	group.addMethodCall(create_call)
+
+	confirmed_txns, fee, err := c.execute(group, sp, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	c.app_id = confirmed_txns[0].ApplicationIndex
+	return c.app_id, fee, nil
+}
+
+// CreateAndFund deploys and funds a new channel app in one group, the funding transactions pay to the address of predictedAppID.
+// Algo channels are funded by a payment before the create call, asset channels by an asset transfer after it,
+// which the app can only receive once it opted into the asset during its creation.
+func (c *ChannelClient) CreateAndFund(
+	predictedAppID uint64,
+	partnerAddress string,
+	penaltyReserve uint64,
+	disputeWindow uint64,
+	assetID uint64,
+	aliceSigningKey types.Address,
+	fundingAmount uint64) (appID uint64, fee uint64, err error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+	app_address := crypto.GetApplicationAddress(predictedAppID)
+
+	// 1. algos for the channel, or for the minimum balance and inner transaction fees of an asset channel
+	payment_amount := fundingAmount
+	if assetID != 0 {
+		payment_amount = assetChannelAlgoReserve(uint64(sp.MinFee))
+	}
+	payment_txn, err := transaction.MakePaymentTxn(c.sender.Address().String(), app_address.String(), payment_amount, nil, "", sp)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 2. create the app, which opts into the asset and checks the funding
+	create_call, err := c.createCall(sp, partnerAddress, penaltyReserve, disputeWindow, assetID, aliceSigningKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	group := channelGroup{}
+	group.addTransaction(payment_txn)
+	group.addMethodCall(create_call)
+
+	// 3. fund the asset channel
+	if assetID != 0 {
+		fund_txn, err := transaction.MakeAssetTransferTxn(c.sender.Address().String(), app_address.String(), fundingAmount, nil, sp, "", assetID)
+		if err != nil {
+			return 0, 0, err
+		}
+		group.addTransaction(fund_txn)
+	}
+
+	confirmed_txns, fee, err := c.execute(group, sp, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	c.app_id = confirmed_txns[1].ApplicationIndex
+	return c.app_id, fee, nil
+}
+
+// Fund funds a created channel with fundingAmount of assetID and registers aliceSigningKey, it returns the total deposit.
+// The app account of an asset channel receives algos for its minimum balance and opts into the asset first.
+func (c *ChannelClient) Fund(assetID uint64, fundingAmount uint64, aliceSigningKey types.Address) (totalDeposit uint64, fee uint64, err error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+	app_address := crypto.GetApplicationAddress(c.app_id)
+	group := channelGroup{}
+
+	var fund_txn types.Transaction
+	if assetID == 0 {
+		fund_txn, err = transaction.MakePaymentTxn(c.sender.Address().String(), app_address.String(), fundingAmount, nil, "", sp)
+		if err != nil {
+			return 0, 0, err
+		}
+	} else {
+		// 1. algos for the minimum balance and inner transaction fees
+		reserve_txn, err := transaction.MakePaymentTxn(c.sender.Address().String(), app_address.String(), assetChannelAlgoReserve(uint64(sp.MinFee)), nil, "", sp)
+		if err != nil {
+			return 0, 0, err
+		}
+		group.addTransaction(reserve_txn)
+
+		// 2. opt the app account into the asset
+		opt_in_call, err := c.methodCall(sp, "optInAsset")
+		if err != nil {
+			return 0, 0, err
+		}
+		opt_in_call.ForeignAssets = foreignAssets(assetID)
+		group.addMethodCall(opt_in_call)
+
+		// 3. fund the channel with the asset
+		fund_txn, err = transaction.MakeAssetTransferTxn(c.sender.Address().String(), app_address.String(), fundingAmount, nil, sp, "", assetID)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	fund_call, err := c.methodCall(sp, "fund", fund_txn, aliceSigningKey)
+	if err != nil {
+		return 0, 0, err
+	}
+	group.addMethodCall(fund_call)
+
+	confirmed_txns, fee, err := c.execute(group, sp, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	totalDeposit, err = c.uint64Return("fund", confirmed_txns[len(confirmed_txns)-1])
+	return totalDeposit, fee, err
+}
+
+// AcceptChannel records that bob accepted the channel, along with the key signing bob's off-chain states
+func (c *ChannelClient) AcceptChannel(bobSigningKey types.Address) (fee uint64, err error) {
+	return c.callMethod(false, nil, nil, "acceptChannel", bobSigningKey)
+}
+
+// AbortChannelOpen refunds alice's deposit of a channel bob has not accepted yet
+func (c *ChannelClient) AbortChannelOpen(assetID uint64) (fee uint64, err error) {
+	return c.callMethod(false, nil, foreignAssets(assetID), "abortChannelOpen")
+}
+
+// Deposit adds amount to the channel, the state signed by alice and bob must credit it to the sender.
+// It returns the new total deposit of the channel.
+func (c *ChannelClient) Deposit(state ChannelState, aliceSignature []byte, bobSignature []byte, amount uint64) (totalDeposit uint64, fee uint64, err error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+	app_address := crypto.GetApplicationAddress(c.app_id)
+
+	// 1. transfer the deposit to the app account
+	var deposit_txn types.Transaction
+	if state.AssetID == 0 {
+		deposit_txn, err = transaction.MakePaymentTxn(c.sender.Address().String(), app_address.String(), amount, nil, "", sp)
+	} else {
+		deposit_txn, err = transaction.MakeAssetTransferTxn(c.sender.Address().String(), app_address.String(), amount, nil, sp, "", state.AssetID)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 2. let the contract verify the new state
+	deposit_call, err := c.methodCall(sp, "deposit", append([]interface{}{deposit_txn}, stateArgs(state, aliceSignature, bobSignature)...)...)
+	if err != nil {
+		return 0, 0, err
+	}
+	deposit_call.ForeignAssets = foreignAssets(state.AssetID)
+	group := channelGroup{}
+	group.addMethodCall(deposit_call)
+
+	confirmed_txns, fee, err := c.execute(group, sp, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	totalDeposit, err = c.uint64Return("deposit", confirmed_txns[1])
+	return totalDeposit, fee, err
+}
+
+// Withdraw pays amount out of the channel to the sender, authorized by alice and bob signing the remaining state.
+// It returns the new total deposit of the channel.
+func (c *ChannelClient) Withdraw(state ChannelState, aliceSignature []byte, bobSignature []byte, amount uint64) (totalDeposit uint64, fee uint64, err error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, 0, err
+	}
+	// the app call pays the fee of the inner payment
+	withdraw_sp := sp
+	withdraw_sp.FlatFee = true
+	withdraw_sp.Fee = types.MicroAlgos(2 * sp.MinFee)
+
+	withdraw_call, err := c.methodCall(withdraw_sp, "withdraw", append(stateArgs(state, aliceSignature, bobSignature), amount)...)
+	if err != nil {
+		return 0, 0, err
+	}
+	withdraw_call.ForeignAssets = foreignAssets(state.AssetID)
+	group := channelGroup{}
+	group.addMethodCall(withdraw_call)
+
+	confirmed_txns, fee, err := c.execute(group, sp, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	totalDeposit, err = c.uint64Return("withdraw", confirmed_txns[0])
+	return totalDeposit, fee, err
+}
+
+// InitiateChannelClosing starts closing the channel with the state signed by alice and bob.
+// It returns the last round of the dispute window.
+func (c *ChannelClient) InitiateChannelClosing(state ChannelState, aliceSignature []byte, bobSignature []byte) (timeout uint64, fee uint64, err error) {
+	confirmed_txns, fee, err := c.sendMethod(true, nil, nil, "initiateChannelClosing", stateArgs(state, aliceSignature, bobSignature)...)
+	if err != nil {
+		return 0, 0, err
+	}
+	timeout, err = c.uint64Return("initiateChannelClosing", confirmed_txns[0])
+	return timeout, fee, err
+}
+
+// RaiseDispute replaces the closing state with a newer state signed by alice and bob, which penalizes the closing initiator
+func (c *ChannelClient) RaiseDispute(state ChannelState, aliceSignature []byte, bobSignature []byte) (fee uint64, err error) {
+	return c.callMethod(true, nil, nil, "raiseDispute", stateArgs(state, aliceSignature, bobSignature)...)
+}
+
+// SettleHTLC reveals the preimage of the pending htlc at htlcIndex of the closing state
+func (c *ChannelClient) SettleHTLC(htlcs []HTLC, htlcIndex uint64, preimage []byte) (fee uint64, err error) {
+	return c.callMethod(false, nil, nil, "settleHTLC", EncodeHTLCs(htlcs), htlcIndex, preimage)
+}
+
+// FinalizeChannelClosing pays out the channel once the dispute window passed, the fees of the payouts are paid from the app account
+func (c *ChannelClient) FinalizeChannelClosing(counterpartyAddress string, assetID uint64, htlcs []HTLC) (fee uint64, err error) {
+	accounts := []string{c.sender.Address().String(), counterpartyAddress}
+	return c.callMethod(false, accounts, foreignAssets(assetID), "finalizeChannelClosing", EncodeHTLCs(htlcs))
+}
+
+// CooperativeClose pays out the final balances of a CLOSE_CHANNEL_DOMAIN state signed by alice and bob immediately
+func (c *ChannelClient) CooperativeClose(counterpartyAddress string, state ChannelState, aliceSignature []byte, bobSignature []byte) (fee uint64, err error) {
+	accounts := []string{c.sender.Address().String(), counterpartyAddress}
+	return c.callMethod(true, accounts, foreignAssets(state.AssetID), "cooperativeClose", stateArgs(state, aliceSignature, bobSignature)...)
+}
+
+// Delete deletes the closed channel app, which is a bare call outside of the abi
+func (c *ChannelClient) Delete() (fee uint64, err error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	delete_txn, err := transaction.MakeApplicationDeleteTx(c.app_id, nil, nil, nil, nil, sp, c.sender.Address(), nil, types.Digest{}, [32]byte{}, types.ZeroAddress)
+	if err != nil {
+		return 0, err
+	}
+	group := channelGroup{}
+	group.addTransaction(delete_txn)
+
+	_, fee, err = c.execute(group, sp, false)
+	return fee, err
+}
+
+// stateArgs are the abi arguments of a signed state followed by the signatures of alice and bob,
+// the app id and asset of the state are those of the called app
+func stateArgs(state ChannelState, aliceSignature []byte, bobSignature []byte) []interface{} {
+	args := []interface{}{state.GenesisHash, state.AliceBalance, state.BobBalance, uint64(state.Timestamp)}
+	if state.Domain != CLOSE_CHANNEL_DOMAIN {
+		args = append(args, EncodeHTLCs(state.HTLCs))
+	}
+	return append(args, aliceSignature, bobSignature)
+}
+
+// createCall is the abi create call of a new channel app
+func (c *ChannelClient) createCall(
+	sp types.SuggestedParams,
+	partnerAddress string,
+	penaltyReserve uint64,
+	disputeWindow uint64,
+	assetID uint64,
+	aliceSigningKey types.Address) (transaction.AddMethodCallParams, error) {
+	contract := LatestContractVersion()
+	partner_address, err := types.DecodeAddress(partnerAddress)
+	if err != nil {
+		return transaction.AddMethodCallParams{}, err
+	}
+
+	create_client := *c
+	create_client.app_id = 0
+	create_call, err := create_client.methodCall(sp, "create",
+		partner_address,
+		penaltyReserve,
+		disputeWindow,
+		assetID, // 0 for algo channels
+		aliceSigningKey,
+		sp.GenesisHash, // network the channel states are signed for
+	)
+	if err != nil {
+		return transaction.AddMethodCallParams{}, err
+	}
+	create_call.ApprovalProgram = contract.ApprovalProgram
+	create_call.ClearProgram = contract.ClearProgram
+	create_call.GlobalSchema = types.StateSchema{NumUint: NUM_UINTS, NumByteSlice: NUM_BYTE_SLICES}
+	create_call.ExtraPages = contract.extraPages()
+	create_call.ForeignAssets = foreignAssets(assetID) // to opt into the asset when funding on creation
+	return create_call, nil
+}
+
+// methodCall returns the parameters of a call of method with args, transaction arguments are passed as types.Transaction
+func (c *ChannelClient) methodCall(sp types.SuggestedParams, method string, args ...interface{}) (transaction.AddMethodCallParams, error) {
+	abi_method, err := c.contract.GetMethodByName(method)
+	if err != nil {
+		return transaction.AddMethodCallParams{}, err
+	}
+	return transaction.AddMethodCallParams{
+		AppID:           c.app_id,
+		Method:          abi_method,
+		MethodArgs:      args,
+		Sender:          c.sender.Address(),
+		SuggestedParams: sp,
+		OnComplete:      types.NoOpOC,
+	}, nil
+}
+
+// callMethod sends a group with a single call of method and returns the fee paid
+func (c *ChannelClient) callMethod(padBudget bool, accounts []string, assets []uint64, method string, args ...interface{}) (uint64, error) {
+	_, fee, err := c.sendMethod(padBudget, accounts, assets, method, args...)
+	return fee, err
+}
+
+// sendMethod sends a group with a single call of method, followed by the increaseBudget calls it needs if padBudget is set
+func (c *ChannelClient) sendMethod(padBudget bool, accounts []string, assets []uint64, method string, args ...interface{}) ([]models.PendingTransactionInfoResponse, uint64, error) {
+	sp, err := c.algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return nil, 0, err
+	}
+	call, err := c.methodCall(sp, method, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	call.ForeignAccounts = accounts
+	call.ForeignAssets = assets
+	group := channelGroup{}
+	group.addMethodCall(call)
+	return c.execute(group, sp, padBudget)
+}
+
+// execute signs and submits the group and waits for its confirmation. Groups verifying signatures are padded
+// with the increaseBudget calls they need for their opcode budget, groups failing in simulation are not
+// submitted and return a *SimulationError. It returns the confirmed transactions in group order and the fee paid.
+func (c *ChannelClient) execute(group channelGroup, sp types.SuggestedParams, padBudget bool) ([]models.PendingTransactionInfoResponse, uint64, error) {
+	if padBudget {
+		padded_group, estimate, err := c.estimate(group, sp)
+		if err != nil {
+			return nil, 0, err
+		}
+		fmt.Printf("Submitting group of %d transactions using %d opcodes with %d increaseBudget calls, expected fee: %d microalgos\n",
+			len(padded_group.entries), estimate.BudgetConsumed, estimate.BudgetPaddingCalls, estimate.Fee)
+		group = padded_group
+	}
+
+	recorder := PhaseRecorderOf(c.sender)
+	atc, err := group.compose(NewTransactionSigner(c.sender))
+	if err != nil {
+		return nil, 0, err
+	}
+	// signing is recorded by the signer, so the signatures are gathered before the submission starts
+	_, err = atc.GatherSignatures()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	end_submission := recorder.Start(PHASE_SUBMISSION)
+	txids, err := atc.Submit(c.algod_client, context.Background())
+	end_submission()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer recorder.Start(PHASE_CONFIRMATION)()
+	confirmed_txns, err := waitForGroup(c.algod_client, txids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return confirmed_txns, GroupFee(confirmed_txns), nil
+}
+
+// estimate appends as many increaseBudget calls to the group as it needs according to simulation,
+// and returns the padded group with its expected consumption and fee
+func (c *ChannelClient) estimate(group channelGroup, sp types.SuggestedParams) (channelGroup, GroupEstimate, error) {
+	padding_calls := 0
+	for {
+		padded_group, err := c.withBudgetPadding(group, padding_calls, sp)
+		if err != nil {
+			return channelGroup{}, GroupEstimate{}, err
+		}
+		atc, err := padded_group.compose(transaction.EmptyTransactionSigner{})
+		if err != nil {
+			return channelGroup{}, GroupEstimate{}, err
+		}
+
+		// the padding calls run the approval program as well, so consumption is measured with the padding in place
+		end_simulation := PhaseRecorderOf(c.sender).Start(PHASE_SIMULATION)
+		simulation, err := atc.Simulate(context.Background(), c.algod_client, models.SimulateRequest{
+			AllowEmptySignatures: true,
+			ExtraOpcodeBudget:    APP_CALL_BUDGET * MAX_GROUP_SIZE,
+		})
+		end_simulation()
+		if err != nil {
+			return channelGroup{}, GroupEstimate{}, err
+		}
+		if len(simulation.SimulateResponse.TxnGroups) != 1 {
+			return channelGroup{}, GroupEstimate{}, fmt.Errorf("expected 1 simulated group, got %d", len(simulation.SimulateResponse.TxnGroups))
+		}
+		result := simulation.SimulateResponse.TxnGroups[0]
+		if result.FailureMessage != "" {
+			return channelGroup{}, GroupEstimate{}, &SimulationError{FailedAt: result.FailedAt, Message: result.FailureMessage}
+		}
+
+		txns_with_signers, err := atc.BuildGroup()
+		if err != nil {
+			return channelGroup{}, GroupEstimate{}, err
+		}
+		txns := make([]types.Transaction, 0, len(txns_with_signers))
+		for _, txn_with_signer := range txns_with_signers {
+			txns = append(txns, txn_with_signer.Txn)
+		}
+		available_budget := APP_CALL_BUDGET * uint64(countAppCalls(txns))
+		if result.AppBudgetConsumed <= available_budget {
+			estimate := GroupEstimate{
+				BudgetConsumed:     result.AppBudgetConsumed,
+				BudgetPaddingCalls: padding_calls,
+			}
+			for _, txn := range txns {
+				estimate.Fee += uint64(txn.Fee)
+			}
+			return padded_group, estimate, nil
+		}
+
+		missing_budget := result.AppBudgetConsumed - available_budget
+		additional_calls := int((missing_budget + APP_CALL_BUDGET - 1) / APP_CALL_BUDGET)
+		if len(txns)+additional_calls > MAX_GROUP_SIZE {
+			return channelGroup{}, GroupEstimate{}, fmt.Errorf("group needs %d opcodes, more than a group of %d transactions provides",
+				result.AppBudgetConsumed, MAX_GROUP_SIZE)
+		}
+		padding_calls += additional_calls
+	}
+}
+
+// withBudgetPadding returns the group followed by amount increaseBudget calls, their index keeps the transaction ids apart
+func (c *ChannelClient) withBudgetPadding(group channelGroup, amount int, sp types.SuggestedParams) (channelGroup, error) {
+	padded_group := channelGroup{entries: append([]groupEntry{}, group.entries...)}
+	for i := 0; i < amount; i++ {
+		increase_budget_call, err := c.methodCall(sp, "increaseBudget", uint64(i))
+		if err != nil {
+			return channelGroup{}, err
+		}
+		padded_group.addMethodCall(increase_budget_call)
+	}
+	return padded_group, nil
+}
+
+// uint64Return decodes the return value a call of method logged
+func (c *ChannelClient) uint64Return(method string, confirmedTxn models.PendingTransactionInfoResponse) (uint64, error) {
+	abi_method, err := c.contract.GetMethodByName(method)
+	if err != nil {
+		return 0, err
+	}
+	if len(confirmedTxn.Logs) == 0 || !bytes.HasPrefix(confirmedTxn.Logs[len(confirmedTxn.Logs)-1], ABI_RETURN_PREFIX) {
+		return 0, fmt.Errorf("call of %s did not log a return value", method)
+	}
+	return_type, err := abi_method.Returns.GetTypeObject()
+	if err != nil {
+		return 0, err
+	}
+	return_value, err := return_type.Decode(confirmedTxn.Logs[len(confirmedTxn.Logs)-1][len(ABI_RETURN_PREFIX):])
+	if err != nil {
+		return 0, err
+	}
+	value, ok := return_value.(uint64)
+	if !ok {
+		return 0, errors.New(method + " does not return an uint64")
+	}
+	return value, nil
+}
+
+// channelGroup collects the plain transactions and method calls of one atomic group.
+// It is composed twice, without signatures for simulation and with the signer of the client for submission.
+type channelGroup struct {
+	entries []groupEntry
+}
+
+type groupEntry struct {
+	txn  types.Transaction                // a plain transaction
+	call *transaction.AddMethodCallParams // or a method call, whose signer is set when composing
+}
+
+func (g *channelGroup) addTransaction(txn types.Transaction) {
+	g.entries = append(g.entries, groupEntry{txn: txn})
+}
+
+func (g *channelGroup) addMethodCall(call transaction.AddMethodCallParams) {
+	g.entries = append(g.entries, groupEntry{call: &call})
+}
+
+// compose adds the group to a new composer, every transaction is signed by signer
+func (g channelGroup) compose(signer transaction.TransactionSigner) (*transaction.AtomicTransactionComposer, error) {
+	atc := &transaction.AtomicTransactionComposer{}
+	for _, entry := range g.entries {
+		if entry.call == nil {
+			err := atc.AddTransaction(transaction.TransactionWithSigner{Txn: entry.txn, Signer: signer})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		call := *entry.call
+		call.Signer = signer
+		call.MethodArgs = make([]interface{}, 0, len(entry.call.MethodArgs))
+		for _, arg := range entry.call.MethodArgs {
+			if txn, is_txn := arg.(types.Transaction); is_txn {
+				arg = transaction.TransactionWithSigner{Txn: txn, Signer: signer}
+			}
+			call.MethodArgs = append(call.MethodArgs, arg)
+		}
+		err := atc.AddMethodCall(call)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return atc, nil
+}
+
+// NewTransactionSigner lets the atomic transaction composer sign with signer
+func NewTransactionSigner(signer Signer) transaction.TransactionSigner {
+	return transactionSigner{signer: signer}
+}
+
+type transactionSigner struct {
+	signer Signer
+}
+
+func (t transactionSigner) SignTransactions(txGroup []types.Transaction, indexesToSign []int) ([][]byte, error) {
+	signed_txns := make([][]byte, 0, len(indexesToSign))
+	for _, index := range indexesToSign {
+		_, signed_txn, err := t.signer.SignTransaction(txGroup[index])
+		if err != nil {
+			return nil, err
+		}
+		signed_txns = append(signed_txns, signed_txn)
+	}
+	return signed_txns, nil
+}
+
+func (t transactionSigner) Equals(other transaction.TransactionSigner) bool {
+	other_signer, ok := other.(transactionSigner)
+	return ok && other_signer.signer == t.signer
+}
@@ -0,0 +1,688 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/dancodery/algorand-state-channels/payment/simulator"
+)
+
+// the channels of the scenarios run on the simulator, which stands in for algod
+const (
+	testAccountFunding = 100_000_000
+	testChannelFunding = 10_000_000
+	testPenaltyReserve = 1_000_000
+	testDisputeWindow  = 10
+	testMinFee         = 1_000
+)
+
+// testChannel is a channel between alice and bob opened on a simulated network
+type testChannel struct {
+	t            *testing.T
+	ledger       *simulator.Ledger
+	algod_client *algod.Client
+	genesis_hash []byte
+
+	alice, bob                 Signer
+	alice_signing, bob_signing Signer
+	app_id, asset_id           uint64
+}
+
+// newSimulatedNetwork serves a fresh simulator ledger to an algod client
+func newSimulatedNetwork(t *testing.T) (*simulator.Ledger, *algod.Client) {
+	ledger := simulator.NewLedger()
+	server := httptest.NewServer(ledger)
+	t.Cleanup(server.Close)
+	algod_client, err := algod.MakeClient(server.URL, "")
+	if err != nil {
+		t.Fatalf("making algod client: %v", err)
+	}
+	return ledger, algod_client
+}
+
+// frontRunningAlgod serves the ledger like algod, but lands a payment of its own before every group sent to it
+// while front_run is set, so that the app id predicted for a new channel is always taken.
+// The first drop_submissions groups are accepted but never committed, while the client waits for them other payments land.
+type frontRunningAlgod struct {
+	ledger           *simulator.Ledger
+	account          crypto.Account
+	sp               types.SuggestedParams
+	front_run        bool
+	drop_submissions int
+	submissions      int
+	payments         int
+}
+
+func newFrontRunningNetwork(t *testing.T) (*simulator.Ledger, *algod.Client, *frontRunningAlgod) {
+	ledger, algod_client := newSimulatedNetwork(t)
+	sp, err := algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		t.Fatalf("getting suggested params: %v", err)
+	}
+	front_runner := &frontRunningAlgod{ledger: ledger, account: ledger.NewAccount(testAccountFunding), sp: sp, front_run: true}
+	server := httptest.NewServer(front_runner)
+	t.Cleanup(server.Close)
+	algod_client, err = algod.MakeClient(server.URL, "")
+	if err != nil {
+		t.Fatalf("making algod client: %v", err)
+	}
+	return ledger, algod_client, front_runner
+}
+
+func (f *frontRunningAlgod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/v2/transactions" {
+		f.submissions++
+		if f.submissions <= f.drop_submissions {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"txId":"DROPPED"}`))
+			return
+		}
+		if f.front_run {
+			err := f.submitPayment()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v2/status/wait-for-block-after/") && f.submissions <= f.drop_submissions {
+		round, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/v2/status/wait-for-block-after/"), 10, 64)
+		for err == nil && f.ledger.Round() <= round {
+			err = f.submitPayment()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	f.ledger.ServeHTTP(w, r)
+}
+
+func (f *frontRunningAlgod) submitPayment() error {
+	f.payments++
+	address := f.account.Address.String()
+	txn, err := transaction.MakePaymentTxn(address, address, 0, []byte(strconv.Itoa(f.payments)), "", f.sp)
+	if err != nil {
+		return err
+	}
+	_, signed_txn_bytes, err := crypto.SignTransaction(f.account.PrivateKey, txn)
+	if err != nil {
+		return err
+	}
+	var signed_txn types.SignedTxn
+	err = msgpack.Decode(signed_txn_bytes, &signed_txn)
+	if err != nil {
+		return err
+	}
+	_, err = f.ledger.SubmitGroup([]types.SignedTxn{signed_txn})
+	return err
+}
+
+// newTestChannel creates and funds a channel of alice with the funding amount of the asset, 0 for algos.
+// Bob has not accepted it yet.
+func newTestChannel(t *testing.T, ledger *simulator.Ledger, algod_client *algod.Client, asset_id uint64, alice Signer, bob Signer) *testChannel {
+	app_id, _, err := CreateAndFundPaymentApp(algod_client, alice, bob.Address().String(), testPenaltyReserve, testDisputeWindow, asset_id, testChannelFunding)
+	if err != nil {
+		t.Fatalf("creating channel: %v", err)
+	}
+	channel := &testChannel{
+		t:            t,
+		ledger:       ledger,
+		algod_client: algod_client,
+		genesis_hash: ledger.GenesisHash(),
+		alice:        alice,
+		bob:          bob,
+		app_id:       app_id,
+		asset_id:     asset_id,
+	}
+	channel.alice_signing, err = DeriveChannelSigner(alice, app_id)
+	if err != nil {
+		t.Fatalf("deriving alice's signing key: %v", err)
+	}
+	channel.bob_signing, err = DeriveChannelSigner(bob, app_id)
+	if err != nil {
+		t.Fatalf("deriving bob's signing key: %v", err)
+	}
+	return channel
+}
+
+// openTestChannel opens an algo channel between two new accounts, which bob accepted
+func openTestChannel(t *testing.T) *testChannel {
+	ledger, algod_client := newSimulatedNetwork(t)
+	alice := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	bob := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	channel := newTestChannel(t, ledger, algod_client, 0, alice, bob)
+	channel.accept()
+	return channel
+}
+
+func (c *testChannel) client(sender Signer) *ChannelClient {
+	return NewChannelClient(c.algod_client, c.app_id, sender)
+}
+
+func (c *testChannel) accept() {
+	_, err := c.client(c.bob).AcceptChannel(c.bob_signing.Address())
+	if err != nil {
+		c.t.Fatalf("accepting channel: %v", err)
+	}
+}
+
+// signedState returns a state of the channel signed by the channel keys of alice and bob
+func (c *testChannel) signedState(domain StateDomain, alice_balance uint64, bob_balance uint64, timestamp int64, htlcs []HTLC) (ChannelState, []byte, []byte) {
+	state := ChannelState{
+		Domain:       domain,
+		GenesisHash:  c.genesis_hash,
+		AppID:        c.app_id,
+		AssetID:      c.asset_id,
+		AliceBalance: alice_balance,
+		BobBalance:   bob_balance,
+		Timestamp:    timestamp,
+		HTLCs:        htlcs,
+	}
+	alice_signature, err := state.Sign(c.alice_signing)
+	if err != nil {
+		c.t.Fatalf("signing state as alice: %v", err)
+	}
+	bob_signature, err := state.Sign(c.bob_signing)
+	if err != nil {
+		c.t.Fatalf("signing state as bob: %v", err)
+	}
+	return state, alice_signature, bob_signature
+}
+
+func (c *testChannel) appState() ChannelAppState {
+	app_state, err := ReadChannelAppState(c.algod_client, c.app_id)
+	if err != nil {
+		c.t.Fatalf("reading app state: %v", err)
+	}
+	return app_state
+}
+
+func (c *testChannel) isClosed() bool {
+	closed, err := IsChannelAppClosed(c.algod_client, c.app_id)
+	if err != nil {
+		c.t.Fatalf("reading app account: %v", err)
+	}
+	return closed
+}
+
+func TestCreateAndFundChannel(t *testing.T) {
+	channel := openTestChannel(t)
+
+	app_state := channel.appState()
+	if app_state.AliceAddress != channel.alice.Address() || app_state.BobAddress != channel.bob.Address() {
+		t.Errorf("parties are %s and %s", app_state.AliceAddress, app_state.BobAddress)
+	}
+	if app_state.AliceSigningKey != channel.alice_signing.Address() || app_state.BobSigningKey != channel.bob_signing.Address() {
+		t.Errorf("signing keys are %s and %s", app_state.AliceSigningKey, app_state.BobSigningKey)
+	}
+	if !app_state.BobAccepted {
+		t.Errorf("bob did not accept the channel")
+	}
+	if app_state.TotalDeposit != testChannelFunding || app_state.LatestAliceBalance != testChannelFunding {
+		t.Errorf("total deposit %d, alice's balance %d, expected %d", app_state.TotalDeposit, app_state.LatestAliceBalance, testChannelFunding)
+	}
+	if app_state.PenaltyReserve != testPenaltyReserve || app_state.DisputeWindow != testDisputeWindow {
+		t.Errorf("penalty reserve %d, dispute window %d", app_state.PenaltyReserve, app_state.DisputeWindow)
+	}
+	if app_state.IsClosing() {
+		t.Errorf("new channel is closing")
+	}
+	if balance := channel.ledger.Balance(crypto.GetApplicationAddress(channel.app_id)); balance != testChannelFunding {
+		t.Errorf("app account holds %d, expected %d", balance, testChannelFunding)
+	}
+}
+
+func TestCreateThenFundAndAbortChannel(t *testing.T) {
+	ledger, algod_client := newSimulatedNetwork(t)
+	alice := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	bob := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+
+	app_id, _, err := CreatePaymentApp(algod_client, alice, bob.Address().String(), testPenaltyReserve, testDisputeWindow, 0, alice.Address())
+	if err != nil {
+		t.Fatalf("creating channel: %v", err)
+	}
+	total_deposit, _, err := NewChannelClient(algod_client, app_id, alice).Fund(0, testChannelFunding, alice.Address())
+	if err != nil {
+		t.Fatalf("funding channel: %v", err)
+	}
+	if total_deposit != testChannelFunding {
+		t.Errorf("fund returned a total deposit of %d, expected %d", total_deposit, testChannelFunding)
+	}
+	_, _, err = NewChannelClient(algod_client, app_id, alice).Fund(0, testChannelFunding, bob.Address())
+	if err == nil {
+		t.Errorf("funded the channel a second time")
+	}
+
+	balance_before := ledger.Balance(alice.Address())
+	_, err = AbortChannelOpen(algod_client, alice, app_id, 0)
+	if err != nil {
+		t.Fatalf("aborting channel: %v", err)
+	}
+	// the abort call pays its own fee and the fee of the refund
+	if refund := ledger.Balance(alice.Address()) - balance_before; refund != testChannelFunding-2*testMinFee {
+		t.Errorf("alice was refunded %d, expected %d", refund, testChannelFunding-2*testMinFee)
+	}
+	_, err = DeleteChannelApp(algod_client, alice, app_id)
+	if err != nil {
+		t.Fatalf("deleting aborted channel: %v", err)
+	}
+}
+
+func TestCreateAndFundFallsBackToTwoStepsWhenPredictionKeepsMissing(t *testing.T) {
+	ledger, algod_client, front_runner := newFrontRunningNetwork(t)
+	alice := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	bob := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+
+	app_id, _, err := CreateAndFundPaymentApp(algod_client, alice, bob.Address().String(), testPenaltyReserve, testDisputeWindow, 0, testChannelFunding)
+	if err != nil {
+		t.Fatalf("creating channel: %v", err)
+	}
+	// every attempt of the atomic group, then the create call and the funding
+	if front_runner.submissions != CREATE_AND_FUND_ATTEMPTS+2 {
+		t.Errorf("%d groups were submitted, expected %d", front_runner.submissions, CREATE_AND_FUND_ATTEMPTS+2)
+	}
+
+	// the funding registered the signing key derived from the actual app id
+	channel_signer, err := DeriveChannelSigner(alice, app_id)
+	if err != nil {
+		t.Fatalf("deriving signing key: %v", err)
+	}
+	app_info, err := algod_client.GetApplicationByID(app_id).Do(context.Background())
+	if err != nil {
+		t.Fatalf("reading app: %v", err)
+	}
+	app_state, err := DecodeChannelAppState(app_info)
+	if err != nil {
+		t.Fatalf("decoding app state: %v", err)
+	}
+	if app_state.AliceSigningKey != channel_signer.Address() {
+		t.Errorf("alice's signing key is %s, expected %s", app_state.AliceSigningKey, channel_signer.Address())
+	}
+	if app_state.TotalDeposit != testChannelFunding {
+		t.Errorf("total deposit is %d, expected %d", app_state.TotalDeposit, testChannelFunding)
+	}
+}
+
+func TestCreateAndFundFallsBackToTwoStepsWhenGroupsAreDropped(t *testing.T) {
+	ledger, algod_client, front_runner := newFrontRunningNetwork(t)
+	front_runner.front_run = false
+	front_runner.drop_submissions = CREATE_AND_FUND_ATTEMPTS
+	alice := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	bob := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+
+	app_id, fee, err := CreateAndFundPaymentApp(algod_client, alice, bob.Address().String(), testPenaltyReserve, testDisputeWindow, 0, testChannelFunding)
+	if err != nil {
+		t.Fatalf("creating channel: %v", err)
+	}
+	// every dropped group timed out, then the app was created and funded in two steps
+	if front_runner.submissions != CREATE_AND_FUND_ATTEMPTS+2 {
+		t.Errorf("%d groups were submitted, expected %d", front_runner.submissions, CREATE_AND_FUND_ATTEMPTS+2)
+	}
+	app_info, err := algod_client.GetApplicationByID(app_id).Do(context.Background())
+	if err != nil {
+		t.Fatalf("reading app: %v", err)
+	}
+	app_state, err := DecodeChannelAppState(app_info)
+	if err != nil {
+		t.Fatalf("decoding app state: %v", err)
+	}
+	if app_state.TotalDeposit != testChannelFunding {
+		t.Errorf("total deposit is %d, expected %d", app_state.TotalDeposit, testChannelFunding)
+	}
+	// the dropped groups did not pay anything
+	if balance := ledger.Balance(alice.Address()); balance != testAccountFunding-testChannelFunding-fee {
+		t.Errorf("alice holds %d, expected %d", balance, testAccountFunding-testChannelFunding-fee)
+	}
+}
+
+func TestCreateAndFundIsNotRetriedWhenTheFundingIsTooLow(t *testing.T) {
+	ledger, algod_client, front_runner := newFrontRunningNetwork(t)
+	front_runner.front_run = false
+	alice := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	bob := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+
+	_, _, err := CreateAndFundPaymentApp(algod_client, alice, bob.Address().String(), testPenaltyReserve, testDisputeWindow, 0, testPenaltyReserve)
+	if err == nil {
+		t.Fatalf("created a channel funded with no more than the penalty reserve")
+	}
+	if front_runner.submissions != 1 {
+		t.Errorf("%d groups were submitted, expected 1", front_runner.submissions)
+	}
+}
+
+func TestAcceptChannelTwiceFails(t *testing.T) {
+	channel := openTestChannel(t)
+
+	// replacing bob's signing key would invalidate the states alice holds
+	_, err := channel.client(channel.bob).AcceptChannel(channel.bob.Address())
+	if err == nil {
+		t.Fatalf("bob accepted the channel a second time")
+	}
+	if app_state := channel.appState(); app_state.BobSigningKey != channel.bob_signing.Address() {
+		t.Errorf("bob's signing key changed to %s", app_state.BobSigningKey)
+	}
+}
+
+func TestAbortAcceptedChannelFails(t *testing.T) {
+	channel := openTestChannel(t)
+
+	_, err := channel.client(channel.alice).AbortChannelOpen(0)
+	if err == nil {
+		t.Fatalf("alice aborted a channel bob accepted")
+	}
+	if channel.isClosed() {
+		t.Errorf("failed abort closed the channel")
+	}
+}
+
+func TestCooperativeClose(t *testing.T) {
+	channel := openTestChannel(t)
+	state, alice_signature, bob_signature := channel.signedState(CLOSE_CHANNEL_DOMAIN, 6_000_000, 4_000_000, 5, nil)
+
+	bob_before := channel.ledger.Balance(channel.bob.Address())
+	_, err := channel.client(channel.alice).CooperativeClose(channel.bob.Address().String(), state, alice_signature, bob_signature)
+	if err != nil {
+		t.Fatalf("closing channel: %v", err)
+	}
+
+	// the payouts pay their fees from the balances
+	if payout := channel.ledger.Balance(channel.bob.Address()) - bob_before; payout != 4_000_000-testMinFee {
+		t.Errorf("bob was paid %d, expected %d", payout, 4_000_000-testMinFee)
+	}
+	if !channel.isClosed() {
+		t.Errorf("app account was not closed")
+	}
+	_, err = DeleteChannelApp(channel.algod_client, channel.alice, channel.app_id)
+	if err != nil {
+		t.Fatalf("deleting closed channel: %v", err)
+	}
+	if _, err := ReadChannelAppState(channel.algod_client, channel.app_id); err == nil {
+		t.Errorf("deleted app still has a state")
+	}
+}
+
+func TestCooperativeCloseWithForgedSignatureKeepsChannelOpen(t *testing.T) {
+	channel := openTestChannel(t)
+	state, alice_signature, _ := channel.signedState(CLOSE_CHANNEL_DOMAIN, 1_000_000, 9_000_000, 5, nil)
+	forged_signature, err := state.Sign(channel.bob)
+	if err != nil {
+		t.Fatalf("signing state: %v", err)
+	}
+
+	// like the contract the simulator approves the call, but does not pay out
+	_, err = channel.client(channel.bob).CooperativeClose(channel.alice.Address().String(), state, alice_signature, forged_signature)
+	if err != nil {
+		t.Fatalf("closing channel: %v", err)
+	}
+	if channel.isClosed() {
+		t.Errorf("channel was closed with a forged signature")
+	}
+}
+
+func TestDisputePenalizesOutdatedState(t *testing.T) {
+	channel := openTestChannel(t)
+	outdated_state, outdated_alice_signature, outdated_bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, 8_000_000, 2_000_000, 1, nil)
+	latest_state, latest_alice_signature, latest_bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, 5_000_000, 5_000_000, 2, nil)
+
+	// alice closes with the state in her favor
+	timeout, _, err := channel.client(channel.alice).InitiateChannelClosing(outdated_state, outdated_alice_signature, outdated_bob_signature)
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+	if timeout != channel.ledger.Round()+testDisputeWindow {
+		t.Errorf("dispute window ends in round %d, expected %d", timeout, channel.ledger.Round()+testDisputeWindow)
+	}
+	app_state := channel.appState()
+	if !app_state.IsClosing() || app_state.ClosingInitiator != "alice" || app_state.LatestAliceBalance != 8_000_000 {
+		t.Fatalf("closing state: initiator %q, timeout %d, alice's balance %d", app_state.ClosingInitiator, app_state.Timeout, app_state.LatestAliceBalance)
+	}
+
+	// bob disputes with the latest state, alice loses the penalty reserve to him
+	_, err = channel.client(channel.bob).RaiseDispute(latest_state, latest_alice_signature, latest_bob_signature)
+	if err != nil {
+		t.Fatalf("raising dispute: %v", err)
+	}
+	app_state = channel.appState()
+	if app_state.LatestAliceBalance != 5_000_000-testPenaltyReserve || app_state.LatestBobBalance != 5_000_000+testPenaltyReserve {
+		t.Errorf("balances after the dispute are %d and %d", app_state.LatestAliceBalance, app_state.LatestBobBalance)
+	}
+	if app_state.LatestTimestamp != 2 {
+		t.Errorf("latest timestamp is %d, expected 2", app_state.LatestTimestamp)
+	}
+
+	// the outdated state cannot replace the latest one again
+	_, err = channel.client(channel.alice).RaiseDispute(outdated_state, outdated_alice_signature, outdated_bob_signature)
+	if err != nil {
+		t.Fatalf("raising dispute: %v", err)
+	}
+	if channel.appState().LatestTimestamp != 2 {
+		t.Errorf("outdated state replaced the latest state")
+	}
+
+	// finalizing pays out nothing before the dispute window passed
+	_, err = FinalizeCloseChannel(channel.algod_client, channel.bob, channel.alice.Address().String(), channel.app_id, 0, nil)
+	if err != nil {
+		t.Fatalf("finalizing early: %v", err)
+	}
+	if channel.isClosed() {
+		t.Fatalf("channel was paid out within the dispute window")
+	}
+
+	channel.ledger.AdvanceRounds(testDisputeWindow)
+	bob_before := channel.ledger.Balance(channel.bob.Address())
+	_, err = FinalizeCloseChannel(channel.algod_client, channel.alice, channel.bob.Address().String(), channel.app_id, 0, nil)
+	if err != nil {
+		t.Fatalf("finalizing: %v", err)
+	}
+	if !channel.isClosed() {
+		t.Fatalf("channel was not paid out after the dispute window")
+	}
+	if payout := channel.ledger.Balance(channel.bob.Address()) - bob_before; payout != 5_000_000+testPenaltyReserve-testMinFee {
+		t.Errorf("bob was paid %d, expected %d", payout, 5_000_000+testPenaltyReserve-testMinFee)
+	}
+}
+
+func TestSettleAndRefundHTLCsOnClose(t *testing.T) {
+	channel := openTestChannel(t)
+	preimage := []byte("preimage of the htlc offered by alice")
+	round := channel.ledger.Round()
+	htlcs := []HTLC{
+		{OfferedByAlice: true, Amount: 1_000_000, Hashlock: HashPreimage(preimage), Timelock: round + 100},
+		{OfferedByAlice: false, Amount: 500_000, Hashlock: HashPreimage([]byte("never revealed")), Timelock: round + 5},
+	}
+	state, alice_signature, bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, 4_000_000, 4_500_000, 1, htlcs)
+
+	_, _, err := channel.client(channel.bob).InitiateChannelClosing(state, alice_signature, bob_signature)
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+
+	// a wrong preimage is rejected in simulation, before anything is submitted
+	_, err = SettleHTLC(channel.algod_client, channel.bob, channel.app_id, htlcs, 0, []byte("wrong preimage"))
+	if err == nil {
+		t.Fatalf("settled an htlc with a wrong preimage")
+	}
+	_, err = SettleHTLC(channel.algod_client, channel.bob, channel.app_id, htlcs, 0, preimage)
+	if err != nil {
+		t.Fatalf("settling htlc: %v", err)
+	}
+	if app_state := channel.appState(); app_state.SettledHTLCs != 1 || app_state.LatestBobBalance != 5_500_000 {
+		t.Errorf("settled htlcs %b, bob's balance %d", app_state.SettledHTLCs, app_state.LatestBobBalance)
+	}
+
+	// the unsettled htlc of bob returns to him once it expired
+	channel.ledger.AdvanceRounds(testDisputeWindow)
+	bob_before := channel.ledger.Balance(channel.bob.Address())
+	_, err = FinalizeCloseChannel(channel.algod_client, channel.alice, channel.bob.Address().String(), channel.app_id, 0, htlcs)
+	if err != nil {
+		t.Fatalf("finalizing: %v", err)
+	}
+	if payout := channel.ledger.Balance(channel.bob.Address()) - bob_before; payout != 6_000_000-testMinFee {
+		t.Errorf("bob was paid %d, expected %d", payout, 6_000_000-testMinFee)
+	}
+}
+
+func TestInitiateClosingAgainAfterSettleFails(t *testing.T) {
+	channel := openTestChannel(t)
+	preimage := []byte("preimage of the htlc offered by alice")
+	htlcs := []HTLC{{OfferedByAlice: true, Amount: 1_000_000, Hashlock: HashPreimage(preimage), Timelock: channel.ledger.Round() + 100}}
+	state, alice_signature, bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, 4_000_000, 5_000_000, 1, htlcs)
+
+	timeout, _, err := channel.client(channel.alice).InitiateChannelClosing(state, alice_signature, bob_signature)
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+	_, err = SettleHTLC(channel.algod_client, channel.bob, channel.app_id, htlcs, 0, preimage)
+	if err != nil {
+		t.Fatalf("settling htlc: %v", err)
+	}
+
+	// initiating again would reset the settled htlc and extend the dispute window
+	channel.ledger.AdvanceRounds(testDisputeWindow / 2)
+	_, _, err = channel.client(channel.alice).InitiateChannelClosing(state, alice_signature, bob_signature)
+	if err == nil {
+		t.Fatalf("initiated the closing of a closing channel")
+	}
+	app_state := channel.appState()
+	if app_state.SettledHTLCs != 1 || app_state.LatestBobBalance != 6_000_000 || app_state.Timeout != timeout {
+		t.Errorf("settled htlcs %b, bob's balance %d, timeout %d, expected timeout %d", app_state.SettledHTLCs, app_state.LatestBobBalance, app_state.Timeout, timeout)
+	}
+}
+
+func TestDepositAndWithdraw(t *testing.T) {
+	channel := openTestChannel(t)
+
+	// bob deposits, the new state credits the deposit to him
+	state, alice_signature, bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, testChannelFunding, 3_000_000, 1, nil)
+	total_deposit, _, err := channel.client(channel.bob).Deposit(state, alice_signature, bob_signature, 3_000_000)
+	if err != nil {
+		t.Fatalf("depositing: %v", err)
+	}
+	if total_deposit != testChannelFunding+3_000_000 {
+		t.Errorf("total deposit after the deposit is %d", total_deposit)
+	}
+
+	// a state which does not account for the deposit is rejected in simulation
+	stale_state, stale_alice_signature, stale_bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, testChannelFunding, 0, 2, nil)
+	_, _, err = channel.client(channel.alice).Deposit(stale_state, stale_alice_signature, stale_bob_signature, 1_000_000)
+	var simulation_error *SimulationError
+	if !errors.As(err, &simulation_error) {
+		t.Fatalf("deposit of a stale state returned %v, expected a simulation error", err)
+	}
+
+	// alice withdraws, authorized by both parties
+	withdraw_amount := uint64(2_000_000)
+	remaining := ChannelState{AliceBalance: testChannelFunding - withdraw_amount, BobBalance: 3_000_000, Timestamp: 3}
+	withdraw_signatures := make([][]byte, 0, 2)
+	for _, signing_key := range []Signer{channel.alice_signing, channel.bob_signing} {
+		signature, err := SignWithdraw(channel.app_id, 0, signing_key, remaining.AliceBalance, remaining.BobBalance,
+			channel.genesis_hash, remaining.Timestamp, nil, withdraw_amount, channel.alice.Address().String())
+		if err != nil {
+			t.Fatalf("signing withdrawal: %v", err)
+		}
+		withdraw_signatures = append(withdraw_signatures, signature)
+	}
+	alice_before := channel.ledger.Balance(channel.alice.Address())
+	fee, err := WithdrawFromChannel(channel.algod_client, channel.alice, channel.genesis_hash, channel.app_id, remaining.AliceBalance,
+		remaining.BobBalance, uint64(remaining.Timestamp), nil, withdraw_amount, 0, withdraw_signatures[0], withdraw_signatures[1])
+	if err != nil {
+		t.Fatalf("withdrawing: %v", err)
+	}
+	// the withdrawing party pays the fees of the group, the inner payment and the budget padding included
+	if fee < 2*testMinFee {
+		t.Errorf("withdrawal cost a fee of %d, expected at least %d", fee, 2*testMinFee)
+	}
+	if received := channel.ledger.Balance(channel.alice.Address()) - alice_before; received != withdraw_amount-fee {
+		t.Errorf("alice received %d, expected %d", received, withdraw_amount-fee)
+	}
+	if app_state := channel.appState(); app_state.TotalDeposit != testChannelFunding+3_000_000-withdraw_amount {
+		t.Errorf("total deposit after the withdrawal is %d", app_state.TotalDeposit)
+	}
+}
+
+func TestInitiateClosingWithStateOfDeposit(t *testing.T) {
+	channel := openTestChannel(t)
+	state, alice_signature, bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, testChannelFunding, 3_000_000, 2, nil)
+	_, _, err := channel.client(channel.bob).Deposit(state, alice_signature, bob_signature, 3_000_000)
+	if err != nil {
+		t.Fatalf("depositing: %v", err)
+	}
+
+	// a state signed before the deposit is older than the one the deposit recorded
+	older_state, older_alice_signature, older_bob_signature := channel.signedState(STATE_UPDATE_DOMAIN, testChannelFunding, 3_000_000, 1, nil)
+	_, _, err = channel.client(channel.alice).InitiateChannelClosing(older_state, older_alice_signature, older_bob_signature)
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+	if app_state := channel.appState(); app_state.IsClosing() {
+		t.Fatalf("channel is closing with a state of timestamp %d, the latest is %d", older_state.Timestamp, app_state.LatestTimestamp)
+	}
+
+	// the state the deposit recorded is the only one adding up to the new total deposit, it closes the channel
+	_, _, err = channel.client(channel.alice).InitiateChannelClosing(state, alice_signature, bob_signature)
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+	app_state := channel.appState()
+	if !app_state.IsClosing() || app_state.LatestBobBalance != 3_000_000 || app_state.LatestTimestamp != 2 {
+		t.Errorf("closing state: closing %v, bob's balance %d, timestamp %d", app_state.IsClosing(), app_state.LatestBobBalance, app_state.LatestTimestamp)
+	}
+}
+
+func TestAssetChannelCooperativeClose(t *testing.T) {
+	ledger, algod_client := newSimulatedNetwork(t)
+	alice := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+	bob := NewAccountSigner(ledger.NewAccount(testAccountFunding))
+
+	sp, err := algod_client.SuggestedParams().Do(t.Context())
+	if err != nil {
+		t.Fatalf("reading suggested params: %v", err)
+	}
+	create_asset_txn, err := transaction.MakeAssetCreateTxn(alice.Address().String(), nil, sp, 1_000_000_000, 0, false,
+		"", "", "", "", "USDC", "usd coin", "", "")
+	if err != nil {
+		t.Fatalf("making asset: %v", err)
+	}
+	confirmed_txns, _, err := SignAndSendGroup(algod_client, alice, []types.Transaction{create_asset_txn})
+	if err != nil {
+		t.Fatalf("creating asset: %v", err)
+	}
+	asset_id := confirmed_txns[0].AssetIndex
+	_, err = OptInAsset(algod_client, bob, asset_id)
+	if err != nil {
+		t.Fatalf("opting bob into the asset: %v", err)
+	}
+
+	channel := newTestChannel(t, ledger, algod_client, asset_id, alice, bob)
+	channel.accept()
+	app_address := crypto.GetApplicationAddress(channel.app_id)
+	if held, _ := ledger.AssetBalance(app_address, asset_id); held != testChannelFunding {
+		t.Fatalf("app account holds %d of the asset, expected %d", held, testChannelFunding)
+	}
+
+	state, alice_signature, bob_signature := channel.signedState(CLOSE_CHANNEL_DOMAIN, 7_000_000, 3_000_000, 1, nil)
+	_, err = channel.client(bob).CooperativeClose(alice.Address().String(), state, alice_signature, bob_signature)
+	if err != nil {
+		t.Fatalf("closing channel: %v", err)
+	}
+	if held, _ := ledger.AssetBalance(bob.Address(), asset_id); held != 3_000_000 {
+		t.Errorf("bob holds %d of the asset, expected 3000000", held)
+	}
+	if _, opted_in := ledger.AssetBalance(app_address, asset_id); opted_in {
+		t.Errorf("app account is still opted into the asset")
+	}
+	if !channel.isClosed() {
+		t.Errorf("app account was not closed")
+	}
+}
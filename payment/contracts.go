@@ -0,0 +1,123 @@
+package payment
+
+import (
+	"bytes"
+	"crypto/sha512"
+	_ "embed"
+	"encoding/base32"
+	"encoding/json"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+)
+
+// compiled programs of every released contract version, built from build_contracts/ with
+// `goal clerk compile payment_approval.teal -o contracts/payment_approval_v<N>.bin`.
+// Released files must never change, partners running older versions check channels against them.
+// Since version 2 the contract implements the ARC-4 abi described in contracts/payment_abi_v<N>.json.
+var (
+	//go:embed contracts/payment_approval_v1.bin
+	paymentApprovalV1 []byte
+	//go:embed contracts/payment_clear_state_v1.bin
+	paymentClearStateV1 []byte
+
+	//go:embed contracts/payment_approval_v2.bin
+	paymentApprovalV2 []byte
+	//go:embed contracts/payment_clear_state_v2.bin
+	paymentClearStateV2 []byte
+
+	//go:embed contracts/payment_approval_v3.bin
+	paymentApprovalV3 []byte
+	//go:embed contracts/payment_clear_state_v3.bin
+	paymentClearStateV3 []byte
+	//go:embed contracts/payment_abi_v3.json
+	paymentABIV3 []byte
+)
+
+// ContractVersion is a released version of the payment channel contract
+type ContractVersion struct {
+	Version         uint64
+	ApprovalProgram []byte
+	ClearProgram    []byte
+	Interface       *abi.Contract // nil for retired versions
+}
+
+// contractVersions is the whitelist of contracts accepted for channels, ordered from oldest to latest
+var contractVersions = []ContractVersion{
+	{Version: 3, ApprovalProgram: paymentApprovalV3, ClearProgram: paymentClearStateV3, Interface: mustParseInterface(paymentABIV3)},
+}
+
+// retiredContractVersions can no longer be called by this node, their channels have to be closed with the release which opened them.
+// Version 1 predates the abi, version 2 accepts a second closing or acceptance and closings with states older than a deposit.
+var retiredContractVersions = []ContractVersion{
+	{Version: 1, ApprovalProgram: paymentApprovalV1, ClearProgram: paymentClearStateV1},
+	{Version: 2, ApprovalProgram: paymentApprovalV2, ClearProgram: paymentClearStateV2},
+}
+
+// LatestContractVersion returns the contract new channels are deployed with
+func LatestContractVersion() ContractVersion {
+	return contractVersions[len(contractVersions)-1]
+}
+
+// ContractVersions returns all whitelisted contract versions
+func ContractVersions() []ContractVersion {
+	return contractVersions
+}
+
+// ProgramHash identifies a contract version by the hash of its approval and clear programs
+func (c ContractVersion) ProgramHash() string {
+	return programHash(c.ApprovalProgram, c.ClearProgram)
+}
+
+// RetiredContractVersions returns the contract versions this node can no longer call
+func RetiredContractVersions() []ContractVersion {
+	return retiredContractVersions
+}
+
+// LookupContractVersion finds the whitelisted contract version of a deployed app
+func LookupContractVersion(approvalProgram []byte, clearProgram []byte) (ContractVersion, bool) {
+	return lookupProgramHash(contractVersions, programHash(approvalProgram, clearProgram))
+}
+
+// LookupRetiredContractVersion finds the retired contract version of a deployed app
+func LookupRetiredContractVersion(approvalProgram []byte, clearProgram []byte) (ContractVersion, bool) {
+	return lookupProgramHash(retiredContractVersions, programHash(approvalProgram, clearProgram))
+}
+
+func lookupProgramHash(versions []ContractVersion, hash string) (ContractVersion, bool) {
+	for _, contract_version := range versions {
+		if contract_version.ProgramHash() == hash {
+			return contract_version, true
+		}
+	}
+	return ContractVersion{}, false
+}
+
+// IsPaymentApprovalProgram reports whether the approval program belongs to any whitelisted contract version
+func IsPaymentApprovalProgram(approvalProgram []byte) bool {
+	for _, contract_version := range contractVersions {
+		if bytes.Equal(contract_version.ApprovalProgram, approvalProgram) {
+			return true
+		}
+	}
+	return false
+}
+
+// extraPages returns the additional program pages the contract needs on creation, a page holds 2048 bytes
+func (c ContractVersion) extraPages() uint32 {
+	program_length := len(c.ApprovalProgram) + len(c.ClearProgram)
+	return uint32((program_length - 1) / 2048)
+}
+
+func programHash(approvalProgram []byte, clearProgram []byte) string {
+	hash := sha512.Sum512_256(append(append(uint64ToBytes(uint64(len(approvalProgram))), approvalProgram...), clearProgram...))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(hash[:])
+}
+
+// mustParseInterface parses an embedded ARC-4 contract description
+func mustParseInterface(spec []byte) *abi.Contract {
+	contract := &abi.Contract{}
+	if err := json.Unmarshal(spec, contract); err != nil {
+		panic("invalid contract interface: " + err.Error())
+	}
+	return contract
+}
@@ -0,0 +1,32 @@
+package payment
+
+import "testing"
+
+// program hashes of the released contract versions, a released program must never change
+var releasedProgramHashes = map[uint64]string{
+	1: "5IPKBBOB3RU42YMWKY33MDD4PLZ5AJ62P2BMD2NG35T6VUMTWXLQ",
+	2: "AVHDWTOPSJGN3ELEBHEYWOYO4JJ5KVYRKOPR35EES2W4UWNINREA",
+	3: "MLO2KAFSZYOSPFTYXIUV3XIIQ7GNHYUEZA355IVX4I3RZAHN2ZMA",
+}
+
+func TestReleasedContractVersionsKeepTheirHashes(t *testing.T) {
+	versions := append(append([]ContractVersion{}, RetiredContractVersions()...), ContractVersions()...)
+	if len(versions) != len(releasedProgramHashes) {
+		t.Errorf("%d contract versions are embedded, %d were released", len(versions), len(releasedProgramHashes))
+	}
+	for _, contract_version := range versions {
+		if hash := contract_version.ProgramHash(); hash != releasedProgramHashes[contract_version.Version] {
+			t.Errorf("contract version %d has the program hash %s, released as %s", contract_version.Version, hash, releasedProgramHashes[contract_version.Version])
+		}
+	}
+
+	// fixes are released as a new version, the whitelisted and retired versions never share a program
+	for _, contract_version := range ContractVersions() {
+		if _, ok := LookupRetiredContractVersion(contract_version.ApprovalProgram, contract_version.ClearProgram); ok {
+			t.Errorf("contract version %d is whitelisted and retired", contract_version.Version)
+		}
+	}
+	if latest := LatestContractVersion(); latest.Version != 3 || latest.Interface == nil {
+		t.Errorf("new channels are deployed with contract version %d", latest.Version)
+	}
+}
@@ -0,0 +1,120 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// attempts to create and fund an app in one group before falling back to creating and funding it in two steps,
+// the predicted app id is off if other transactions land first
+const CREATE_AND_FUND_ATTEMPTS = 5
+
+// predictAppID predicts the id of an app created by the transaction at groupIndex of a group committed in the next block
+func predictAppID(algodClient *algod.Client, groupIndex uint64) (uint64, error) {
+	status, err := algodClient.Status().Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	block, err := algodClient.Block(status.LastRound).Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	// TxnCounter is the number of the next transaction, an app gets the counter of its creation transaction plus one
+	return block.TxnCounter + groupIndex + 1, nil
+}
+
+// CreateAndFundPaymentApp creates and funds a payment channel app in a single atomic group.
+// The funding transactions pay to the predicted app address, the app rejects the group if the prediction is wrong.
+// The group is retried if other transactions took the predicted app id or it was dropped, if that keeps happening
+// the app is created and funded in two steps. It returns the app id and the fee paid.
+func CreateAndFundPaymentApp(
+	algodClient *algod.Client,
+	senderAccount Signer,
+	partnerAlgoAddress string,
+	penaltyReserve uint64,
+	disputeWindow uint64,
+	assetID uint64,
+	fundingAmount uint64) (uint64, uint64, error) {
+	for attempt := 0; attempt < CREATE_AND_FUND_ATTEMPTS; attempt++ {
+		appID, fee, missed, err := createAndFundPaymentApp(algodClient, senderAccount, partnerAlgoAddress, penaltyReserve, disputeWindow, assetID, fundingAmount)
+		if err == nil {
+			return appID, fee, nil
+		}
+		if !missed {
+			return 0, 0, err
+		}
+		fmt.Printf("Predicted app id was taken by other transactions or the group was dropped, attempt %d: %v\n", attempt+1, err)
+	}
+	return createThenFundPaymentApp(algodClient, senderAccount, partnerAlgoAddress, penaltyReserve, disputeWindow, assetID, fundingAmount)
+}
+
+// createAndFundPaymentApp submits the group creating and funding the app, missed tells whether the group can no longer
+// create the app because other transactions took the predicted app id, or because it was dropped or expired
+func createAndFundPaymentApp(
+	algodClient *algod.Client,
+	senderAccount Signer,
+	partnerAlgoAddress string,
+	penaltyReserve uint64,
+	disputeWindow uint64,
+	assetID uint64,
+	fundingAmount uint64) (appID uint64, fee uint64, missed bool, err error) {
+	// the create call follows the first payment
+	predictedAppID, err := predictAppID(algodClient, 1)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	// the signing key is derived from the predicted app id, the node derives it again from the actual one
+	channelSigner, err := DeriveChannelSigner(senderAccount, predictedAppID)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	appID, fee, err = NewChannelClient(algodClient, 0, senderAccount).CreateAndFund(
+		predictedAppID, partnerAlgoAddress, penaltyReserve, disputeWindow, assetID, channelSigner.Address(), fundingAmount)
+	if err == nil {
+		return appID, fee, false, nil
+	}
+
+	// the group may have been committed although its confirmation was not seen in time
+	app, app_err := algodClient.GetApplicationByID(predictedAppID).Do(context.Background())
+	if app_err == nil && app.Params.Creator == senderAccount.Address().String() {
+		fmt.Printf("App %d was created although its confirmation timed out, its fee is not recorded: %v\n", predictedAppID, err)
+		return predictedAppID, 0, false, nil
+	}
+
+	// once the chain moved past the predicted app id without creating my app, the group can never be committed
+	nextPredictedAppID, predict_err := predictAppID(algodClient, 1)
+	if predict_err != nil {
+		return 0, 0, false, err
+	}
+	return 0, 0, nextPredictedAppID > predictedAppID, err
+}
+
+// createThenFundPaymentApp creates the app before funding it, so that the funding registers
+// the signing key derived from the actual app id. An app whose funding failed stays empty and can be cleaned up.
+func createThenFundPaymentApp(
+	algodClient *algod.Client,
+	senderAccount Signer,
+	partnerAlgoAddress string,
+	penaltyReserve uint64,
+	disputeWindow uint64,
+	assetID uint64,
+	fundingAmount uint64) (uint64, uint64, error) {
+	// the signing key is registered by the funding
+	appID, create_fee, err := CreatePaymentApp(algodClient, senderAccount, partnerAlgoAddress, penaltyReserve, disputeWindow, assetID, types.ZeroAddress)
+	if err != nil {
+		return 0, 0, err
+	}
+	channelSigner, err := DeriveChannelSigner(senderAccount, appID)
+	if err != nil {
+		return 0, create_fee, err
+	}
+	fund_fee, err := SetupPaymentApp(algodClient, appID, senderAccount, fundingAmount, assetID, channelSigner.Address())
+	if err != nil {
+		return 0, create_fee + fund_fee, err
+	}
+	return appID, create_fee + fund_fee, nil
+}
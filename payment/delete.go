@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+)
+
+// IsChannelAppClosed checks whether the app account of a channel was closed out, i.e. all funds were paid out
+func IsChannelAppClosed(algod_client *algod.Client, app_id uint64) (bool, error) {
+	app_account_info, err := algod_client.AccountInformation(crypto.GetApplicationAddress(app_id).String()).Do(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return app_account_info.Amount == 0, nil
+}
+
+// DeleteChannelApp deletes a closed channel app, which frees the minimum balance of its global state for the creator.
+// It returns the fee paid.
+func DeleteChannelApp(algod_client *algod.Client, creator_account Signer, app_id uint64) (uint64, error) {
+	return NewChannelClient(algod_client, app_id, creator_account).Delete()
+}
@@ -0,0 +1,43 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+)
+
+// DepositToChannel adds funds to an open channel. The co-signed state must credit the deposit to the sender's balance.
+// It returns the fee paid by the group.
+func DepositToChannel(
+	algod_client *algod.Client,
+	sender_account Signer,
+	// for signed hash
+	genesis_hash []byte,
+	app_id uint64,
+	alice_balance uint64,
+	bob_balance uint64,
+	timestamp uint64,
+	htlcs []HTLC,
+	// END for signed hash
+	asset_id uint64,
+	alice_signature []byte,
+	bob_signature []byte,
+	deposit_amount uint64,
+) (uint64, error) {
+	state := ChannelState{
+		Domain:       STATE_UPDATE_DOMAIN,
+		GenesisHash:  genesis_hash,
+		AppID:        app_id,
+		AssetID:      asset_id,
+		AliceBalance: alice_balance,
+		BobBalance:   bob_balance,
+		Timestamp:    int64(timestamp),
+		HTLCs:        htlcs,
+	}
+	totalDeposit, fee, err := NewChannelClient(algod_client, app_id, sender_account).Deposit(state, alice_signature, bob_signature, deposit_amount)
+	if err != nil {
+		return fee, err
+	}
+	fmt.Printf("Deposited %d into channel %d, total deposit: %d\n", deposit_amount, app_id, totalDeposit)
+	return fee, nil
+}
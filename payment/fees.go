@@ -0,0 +1,96 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// SignAndSendGroup signs the transactions as one atomic group, submits it and waits for its confirmation.
+// It returns the confirmed transactions in group order and the total fee the group paid in microalgos.
+// Submission and confirmation are recorded as phases if sender was created by WithPhaseRecorder.
+func SignAndSendGroup(client *algod.Client, sender Signer, unsignedTransactions []types.Transaction) ([]models.PendingTransactionInfoResponse, uint64, error) {
+	recorder := PhaseRecorderOf(sender)
+	group := append([]types.Transaction{}, unsignedTransactions...)
+
+	// compute group id
+	if len(group) > 1 {
+		group_id, err := crypto.ComputeGroupID(group)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := range group {
+			group[i].Group = group_id
+		}
+	}
+
+	// sign transactions
+	var txids []string
+	var signedGroupTxns []byte
+	for _, txn := range group {
+		txid, signedTxn, err := sender.SignTransaction(txn)
+		if err != nil {
+			return nil, 0, err
+		}
+		txids = append(txids, txid)
+		signedGroupTxns = append(signedGroupTxns, signedTxn...)
+	}
+
+	// submit group transaction
+	end_submission := recorder.Start(PHASE_SUBMISSION)
+	_, err := client.SendRawTransaction(signedGroupTxns).Do(context.Background())
+	end_submission()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// wait for confirmation
+	defer recorder.Start(PHASE_CONFIRMATION)()
+	confirmed_txns, err := waitForGroup(client, txids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return confirmed_txns, GroupFee(confirmed_txns), nil
+}
+
+// waitForGroup waits for the confirmation of a submitted group and returns its transactions in group order.
+// The whole group is confirmed in the same round.
+func waitForGroup(client *algod.Client, txids []string) ([]models.PendingTransactionInfoResponse, error) {
+	first_confirmed, err := transaction.WaitForConfirmation(client, txids[0], 4, context.Background())
+	if err != nil {
+		return nil, err
+	}
+	confirmed_txns := []models.PendingTransactionInfoResponse{first_confirmed}
+	for _, txid := range txids[1:] {
+		confirmed_txn, _, err := client.PendingTransactionInformation(txid).Do(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		confirmed_txns = append(confirmed_txns, confirmed_txn)
+	}
+	return confirmed_txns, nil
+}
+
+// GroupFee sums the fees paid by confirmed transactions. Inner transactions with a fee of their own,
+// like the payouts of a closing channel, pay it from the app account and are included.
+func GroupFee(confirmedTxns []models.PendingTransactionInfoResponse) uint64 {
+	fee := uint64(0)
+	for _, confirmed_txn := range confirmedTxns {
+		fee += uint64(confirmed_txn.Transaction.Txn.Fee)
+		fee += innerTxnFees(confirmed_txn.InnerTxns)
+	}
+	return fee
+}
+
+func innerTxnFees(innerTxns []models.PendingTransactionResponse) uint64 {
+	fee := uint64(0)
+	for _, inner_txn := range innerTxns {
+		fee += uint64(inner_txn.Transaction.Txn.Fee)
+		fee += innerTxnFees(inner_txn.InnerTxns)
+	}
+	return fee
+}
@@ -0,0 +1,97 @@
+package payment
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+)
+
+// HTLC_SIZE is the number of bytes of one encoded htlc:
+// offerer (1 byte) | amount (8 bytes) | hashlock (32 bytes) | timelock (8 bytes)
+const HTLC_SIZE = 49
+
+// MAX_HTLCS limits the pending htlcs per state, so that a state still fits into
+// the application arguments and the settled bitmask of the smart contract
+const MAX_HTLCS = 8
+
+// HTLC is a hash-time-locked conditional payment inside a channel state.
+// The amount is locked until either the receiver reveals the preimage of the hashlock
+// or the timelock round has passed and the amount is refunded to the offerer.
+type HTLC struct {
+	OfferedByAlice bool
+	Amount         uint64
+	Hashlock       [32]byte // sha256 hash of the preimage
+	Timelock       uint64   // last round in which the htlc can be settled
+}
+
+// EncodeHTLCs serializes the htlcs the same way they are signed and passed to the smart contract
+func EncodeHTLCs(htlcs []HTLC) []byte {
+	data := make([]byte, 0, len(htlcs)*HTLC_SIZE)
+	for _, htlc := range htlcs {
+		if htlc.OfferedByAlice {
+			data = append(data, 0)
+		} else {
+			data = append(data, 1)
+		}
+		data = append(data, uint64ToBytes(htlc.Amount)...)
+		data = append(data, htlc.Hashlock[:]...)
+		data = append(data, uint64ToBytes(htlc.Timelock)...)
+	}
+	return data
+}
+
+// DecodeHTLCs is the inverse of EncodeHTLCs
+func DecodeHTLCs(data []byte) ([]HTLC, error) {
+	if len(data)%HTLC_SIZE != 0 {
+		return nil, errors.New("invalid htlcs length")
+	}
+	if len(data)/HTLC_SIZE > MAX_HTLCS {
+		return nil, errors.New("too many htlcs")
+	}
+
+	htlcs := make([]HTLC, 0, len(data)/HTLC_SIZE)
+	for offset := 0; offset < len(data); offset += HTLC_SIZE {
+		var htlc HTLC
+		switch data[offset] {
+		case 0:
+			htlc.OfferedByAlice = true
+		case 1:
+			htlc.OfferedByAlice = false
+		default:
+			return nil, errors.New("invalid htlc offerer")
+		}
+		htlc.Amount = binary.BigEndian.Uint64(data[offset+1 : offset+9])
+		copy(htlc.Hashlock[:], data[offset+9:offset+41])
+		htlc.Timelock = binary.BigEndian.Uint64(data[offset+41 : offset+49])
+		htlcs = append(htlcs, htlc)
+	}
+	return htlcs, nil
+}
+
+// SumHTLCAmounts returns the total amount locked in the htlcs
+func SumHTLCAmounts(htlcs []HTLC) uint64 {
+	total := uint64(0)
+	for _, htlc := range htlcs {
+		total += htlc.Amount
+	}
+	return total
+}
+
+// HashPreimage returns the hashlock belonging to a preimage
+func HashPreimage(preimage []byte) [32]byte {
+	return sha256.Sum256(preimage)
+}
+
+// SettleHTLC reveals the preimage of a pending htlc of the closing state on chain
+func SettleHTLC(
+	algod_client *algod.Client,
+	sender_account Signer,
+	app_id uint64,
+	htlcs []HTLC,
+	htlc_index uint64,
+	preimage []byte,
+) (uint64, error) {
+	return NewChannelClient(algod_client, app_id, sender_account).SettleHTLC(htlcs, htlc_index, preimage)
+}
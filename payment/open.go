@@ -0,0 +1,17 @@
+package payment
+
+import (
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// AcceptChannel records on chain that bob accepted the channel and the key signing bob's off-chain states.
+// Afterwards alice can no longer abort it.
+func AcceptChannel(algod_client *algod.Client, bob_account Signer, app_id uint64, bob_signing_key types.Address) (uint64, error) {
+	return NewChannelClient(algod_client, app_id, bob_account).AcceptChannel(bob_signing_key)
+}
+
+// AbortChannelOpen refunds alice's deposit of a channel bob has not accepted yet
+func AbortChannelOpen(algod_client *algod.Client, alice_account Signer, app_id uint64, asset_id uint64) (uint64, error) {
+	return NewChannelClient(algod_client, app_id, alice_account).AbortChannelOpen(asset_id)
+}
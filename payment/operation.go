@@ -4,22 +4,37 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"io/ioutil"
-	"math"
-	"strconv"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
-	"github.com/algorand/go-algorand-sdk/v2/crypto"
-	"github.com/algorand/go-algorand-sdk/v2/transaction"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 	"golang.org/x/crypto/ed25519"
 	"golang.org/x/crypto/sha3"
 )
 
-const NUM_UINTS = 7
-const NUM_BYTE_SLICES = 3
+const NUM_UINTS = 10
+const NUM_BYTE_SLICES = 7
+
+// PROTOCOL_VERSION is signed as part of every state, close and withdraw message, the app only accepts its own version
+const PROTOCOL_VERSION = 1
+
+// GetGenesisHash reads the genesis hash of the network algod is connected to, signed messages are bound to it
+func GetGenesisHash(algodClient *algod.Client) ([]byte, error) {
+	sp, err := algodClient.SuggestedParams().Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if len(sp.GenesisHash) != 32 {
+		return nil, fmt.Errorf("invalid genesis hash of length %d", len(sp.GenesisHash))
+	}
+	return sp.GenesisHash, nil
+}
+
+// domainSeparator prefixes signed messages with the protocol version and the network
+func domainSeparator(genesisHash []byte) []byte {
+	return append(uint64ToBytes(PROTOCOL_VERSION), genesisHash...)
+}
 
 // CompileTeal compiles a teal file into binary
 func CompileTeal(algodClient *algod.Client, path string) []byte {
@@ -43,280 +58,149 @@ func CompileTeal(algodClient *algod.Client, path string) []byte {
 	return bin
 }
 
-func CompilePaymentPrograms(algodClient *algod.Client) (approvalProgram []byte, clearProgram []byte) {
-	// compile approval program
-	approvalProgram = CompileTeal(algodClient, "smart_contracts/payment_approval.teal")
-	if approvalProgram == nil {
-		fmt.Printf("Error compiling approval program\n")
-	}
-
-	// compile clear program
-	clearProgram = CompileTeal(algodClient, "smart_contracts/payment_clear_state.teal")
-	if clearProgram == nil {
-		fmt.Printf("Error compiling clear program\n")
-	}
-	return
-}
-
-// CreatePaymentApp creates a new payment channel smart contract
+// CreatePaymentApp creates a new payment channel smart contract, aliceSigningKey is the key signing alice's off-chain states
 func CreatePaymentApp(
 	algodClient *algod.Client,
-	senderAccount crypto.Account,
+	senderAccount Signer,
 	partnerAlgoAddress string,
 	penaltyReserve uint64,
-	disputeWindow uint64) uint64 {
-	approvalBinary, clearBinary := CompilePaymentPrograms(algodClient)
-
-	// create application deployment transaction
-	sp, err := algodClient.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	penaltyReserveBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(penaltyReserveBytes, penaltyReserve)
-
-	disputeWindowBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(disputeWindowBytes, disputeWindow)
-
-	pk, err := types.DecodeAddress(partnerAlgoAddress)
-	if err != nil {
-		fmt.Printf("Error decoding address: %v\n", err)
-	}
-	app_args := [][]byte{
-		pk[:],
-		penaltyReserveBytes,
-		disputeWindowBytes,
-	}
-	paymentAppTxn, err := transaction.MakeApplicationCreateTx(
-		false,                       // opt-in
-		approvalBinary, clearBinary, // approval and clear programs
-		types.StateSchema{NumUint: NUM_UINTS, NumByteSlice: NUM_BYTE_SLICES}, // global state schema
-		types.StateSchema{NumUint: 0, NumByteSlice: 0},                       // local state schema
-		app_args,              // app arguments
-		nil,                   // accounts
-		nil,                   // foreign apps
-		nil,                   // foreign assets
-		sp,                    // suggested params
-		senderAccount.Address, // sender
-		nil,                   // note
-		types.Digest{},        // group
-		[32]byte{},            // lease
-		types.ZeroAddress,     // rekey to
-	)
-	if err != nil {
-		fmt.Printf("Error creating application create transaction: %v\n", err)
-	}
-
-	// sign transaction
-	txid, signed_txn, err := crypto.SignTransaction(senderAccount.PrivateKey, paymentAppTxn)
-	if err != nil {
-		fmt.Printf("Error signing transaction: %v\n", err)
-	}
-
-	// submit transaction
-	_, err = algodClient.SendRawTransaction(signed_txn).Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error submitting transaction: %v\n", err)
-	}
-
-	// wait for confirmation
-	confirmedTxn, err := transaction.WaitForConfirmation(algodClient, txid, 4, context.Background())
-	if err != nil {
-		fmt.Printf("Error waiting for confirmation: %v\n", err)
-	}
-	return confirmedTxn.ApplicationIndex
+	disputeWindow uint64,
+	assetID uint64,
+	aliceSigningKey types.Address) (appID uint64, fee uint64, err error) {
+	return NewChannelClient(algodClient, 0, senderAccount).Create(partnerAlgoAddress, penaltyReserve, disputeWindow, assetID, aliceSigningKey)
 }
 
-// SetupPaymentApp funds the already created payment app
+// SetupPaymentApp funds the already created payment app, registers aliceSigningKey and returns the fee paid
 func SetupPaymentApp(
 	algodClient *algod.Client,
 	appID uint64,
-	senderAccount crypto.Account,
-	fundingAmount uint64) {
-	appAddr := crypto.GetApplicationAddress(appID)
-
-	// create transaction
-	sp, err := algodClient.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	fundAppTxn, err := transaction.MakePaymentTxn(
-		senderAccount.Address.String(),
-		appAddr.String(),
-		fundingAmount,
-		nil,
-		"",
-		sp,
-	)
-	if err != nil {
-		fmt.Printf("Error creating payment transaction: %v\n", err)
-	}
-	callAppFundTxn, err := transaction.MakeApplicationNoOpTx(
-		appID,
-		[][]byte{[]byte("fund")},
-		nil,
-		nil,
-		nil,
-		sp,
-		senderAccount.Address,
-		nil,
-		types.Digest{},
-		[32]byte{},
-		types.ZeroAddress,
-	)
-	if err != nil {
-		fmt.Printf("Error creating application call 'fund' transaction: %v\n", err)
-	}
-
-	// compute group id
-	group_id, err := crypto.ComputeGroupID([]types.Transaction{fundAppTxn, callAppFundTxn})
-	if err != nil {
-		fmt.Printf("Error computing group id: %v\n", err)
-	}
-	fundAppTxn.Group = group_id
-	callAppFundTxn.Group = group_id
-
-	// sign transactions
-	_, signedFundAppTxn, err := crypto.SignTransaction(senderAccount.PrivateKey, fundAppTxn)
+	senderAccount Signer,
+	fundingAmount uint64,
+	assetID uint64,
+	aliceSigningKey types.Address) (uint64, error) {
+	totalDeposit, fee, err := NewChannelClient(algodClient, appID, senderAccount).Fund(assetID, fundingAmount, aliceSigningKey)
 	if err != nil {
-		fmt.Printf("Error signing transaction: %v\n", err)
-	}
-	_, signedCallAppFundTxn, err := crypto.SignTransaction(senderAccount.PrivateKey, callAppFundTxn)
-	if err != nil {
-		fmt.Printf("Error signing transaction: %v\n", err)
-	}
-
-	var signedGroup []byte
-	signedGroup = append(signedGroup, signedFundAppTxn...)
-	signedGroup = append(signedGroup, signedCallAppFundTxn...)
-
-	// submit transactions
-	pending_txn_id, err := algodClient.SendRawTransaction(signedGroup).Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error submitting transaction: %v\n", err)
-	}
-
-	// wait for confirmation
-	_, err = transaction.WaitForConfirmation(algodClient, pending_txn_id, 4, context.Background())
-	if err != nil {
-		fmt.Printf("Error waiting for confirmation: %v\n", err)
+		return fee, err
 	}
+	fmt.Printf("Funded channel %d with a total deposit of %d\n", appID, totalDeposit)
+	return fee, nil
 }
 
+// SignState signs a state update which can be disputed on chain
 func SignState(
 	appID uint64,
-	account crypto.Account,
+	assetID uint64,
+	account Signer,
 	aliceBalance uint64,
 	bobBalance uint64,
-	algorandPort uint64,
+	genesisHash []byte,
 	timestamp int64,
+	htlcs []HTLC,
 ) ([]byte, error) {
-	data_raw := make([]byte, 0)
-	data_raw = append(data_raw, []byte("STATE_UPDATE")...)
-	data_raw = append(data_raw, uint64ToBytes(algorandPort)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(appID)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(aliceBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(bobBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(uint64(timestamp))...)
-	data_raw = append(data_raw, []byte("END_STATE_UPDATE")...)
-	data_hashed := sha3.Sum256(data_raw)
-
-	signed_bytes := ed25519.Sign(account.PrivateKey, data_hashed[:])
-	if signed_bytes == nil {
-		return nil, errors.New("error signing bytes")
-	}
-	return signed_bytes, nil
+	state := ChannelState{
+		Domain:       STATE_UPDATE_DOMAIN,
+		GenesisHash:  genesisHash,
+		AppID:        appID,
+		AssetID:      assetID,
+		AliceBalance: aliceBalance,
+		BobBalance:   bobBalance,
+		Timestamp:    timestamp,
+		HTLCs:        htlcs,
+	}
+	return state.Sign(account)
 }
 
+// SignClose signs the final balances of a cooperative close
 func SignClose(
 	appID uint64,
-	account crypto.Account,
+	assetID uint64,
+	account Signer,
 	aliceBalance uint64,
 	bobBalance uint64,
-	algorandPort uint64,
+	genesisHash []byte,
 	timestamp int64,
 ) ([]byte, error) {
-	data_raw := make([]byte, 0)
-	data_raw = append(data_raw, []byte("CLOSE_CHANNEL")...)
-	data_raw = append(data_raw, uint64ToBytes(algorandPort)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(appID)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(aliceBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(bobBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(uint64(timestamp))...)
-	data_raw = append(data_raw, []byte("END_CLOSE_CHANNEL")...)
-	data_hashed := sha3.Sum256(data_raw)
-
-	signed_bytes := ed25519.Sign(account.PrivateKey, data_hashed[:])
-	if signed_bytes == nil {
-		return nil, errors.New("error signing bytes")
-	}
-	return signed_bytes, nil
+	state := ChannelState{
+		Domain:       CLOSE_CHANNEL_DOMAIN,
+		GenesisHash:  genesisHash,
+		AppID:        appID,
+		AssetID:      assetID,
+		AliceBalance: aliceBalance,
+		BobBalance:   bobBalance,
+		Timestamp:    timestamp,
+	}
+	return state.Sign(account)
 }
 
 func VerifyClose(
 	appID uint64,
+	assetID uint64,
 	aliceBalance uint64,
 	bobBalance uint64,
-	algorandPort uint64,
+	genesisHash []byte,
 	signature []byte,
 	algo_address string,
 	timestamp int64,
 ) bool {
-	data_raw := make([]byte, 0)
-	data_raw = append(data_raw, []byte("CLOSE_CHANNEL")...)
-	data_raw = append(data_raw, uint64ToBytes(algorandPort)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(appID)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(aliceBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(bobBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(uint64(timestamp))...)
-	data_raw = append(data_raw, []byte("END_CLOSE_CHANNEL")...)
-	data_hashed := sha3.Sum256(data_raw)
-
-	decoded_address, err := types.DecodeAddress(algo_address)
-	if err != nil {
-		fmt.Printf("Error decoding address: %v\n", err)
-	}
-
-	pub_key := ed25519.PublicKey(decoded_address[:])
-	return ed25519.Verify(pub_key, data_hashed[:], signature)
+	state := ChannelState{
+		Domain:       CLOSE_CHANNEL_DOMAIN,
+		GenesisHash:  genesisHash,
+		AppID:        appID,
+		AssetID:      assetID,
+		AliceBalance: aliceBalance,
+		BobBalance:   bobBalance,
+		Timestamp:    timestamp,
+	}
+	return state.Verify(signature, algo_address)
 }
 
 func VerifyState(
 	appID uint64,
+	assetID uint64,
 	aliceBalance uint64,
 	bobBalance uint64,
-	algorandPort uint64,
+	genesisHash []byte,
 	signature []byte,
 	algo_address string,
 	timestamp int64,
+	htlcs []HTLC,
 ) bool {
-	data_raw := make([]byte, 0)
-	data_raw = append(data_raw, []byte("STATE_UPDATE")...)
-	data_raw = append(data_raw, uint64ToBytes(algorandPort)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(appID)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(aliceBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(bobBalance)...)
-	data_raw = append(data_raw, []byte(",")...)
-	data_raw = append(data_raw, uint64ToBytes(uint64(timestamp))...)
-	data_raw = append(data_raw, []byte("END_STATE_UPDATE")...)
-	data_hashed := sha3.Sum256(data_raw)
+	state := ChannelState{
+		Domain:       STATE_UPDATE_DOMAIN,
+		GenesisHash:  genesisHash,
+		AppID:        appID,
+		AssetID:      assetID,
+		AliceBalance: aliceBalance,
+		BobBalance:   bobBalance,
+		Timestamp:    timestamp,
+		HTLCs:        htlcs,
+	}
+	return state.Verify(signature, algo_address)
+}
+
+// SignMemo authenticates the memo the payer attaches to the state update with the given timestamp of the channel app on the network of genesisHash.
+// The memo is not part of the state verified by the smart contract.
+func SignMemo(
+	appID uint64,
+	account Signer,
+	genesisHash []byte,
+	timestamp int64,
+	memo []byte,
+) ([]byte, error) {
+	data_hashed := memoHash(appID, genesisHash, timestamp, memo)
+
+	return account.SignBytes(data_hashed[:])
+}
+
+func VerifyMemo(
+	appID uint64,
+	signature []byte,
+	algo_address string,
+	genesisHash []byte,
+	timestamp int64,
+	memo []byte,
+) bool {
+	data_hashed := memoHash(appID, genesisHash, timestamp, memo)
 
 	decoded_address, err := types.DecodeAddress(algo_address)
 	if err != nil {
@@ -327,262 +211,114 @@ func VerifyState(
 	return ed25519.Verify(pub_key, data_hashed[:], signature)
 }
 
+func memoHash(appID uint64, genesisHash []byte, timestamp int64, memo []byte) [32]byte {
+	data_raw := make([]byte, 0)
+	data_raw = append(data_raw, []byte("PAYMENT_MEMO")...)
+	data_raw = append(data_raw, uint64ToBytes(appID)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, genesisHash...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(uint64(timestamp))...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, memo...)
+	data_raw = append(data_raw, []byte("END_PAYMENT_MEMO")...)
+	return sha3.Sum256(data_raw)
+}
+
 func InitiateCloseChannel(
 	algod_client *algod.Client,
-	sender_account crypto.Account,
+	sender_account Signer,
 	// for signed hash
-	algorand_port uint64,
+	genesis_hash []byte,
 	app_id uint64,
 	alice_balance uint64,
 	bob_balance uint64,
 	timestamp uint64,
+	htlcs []HTLC,
 	// END for signed hash
 	alice_signature []byte,
 	bob_signature []byte,
-) {
-	sp, err := algod_client.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	algorandPortBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(algorandPortBytes, algorand_port)
-
-	aliceBalanceBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(aliceBalanceBytes, alice_balance)
-
-	bobBalanceBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(bobBalanceBytes, bob_balance)
-
-	timestampBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(timestampBytes, timestamp)
-
-	app_args := [][]byte{
-		[]byte("initiateChannelClosing"),
-		// BEGIN SIGNED VALUES
-		algorandPortBytes, // algorand_port
-		aliceBalanceBytes, // alice_balance
-		bobBalanceBytes,   // bob_balance
-		timestampBytes,    // timestamp
-		// END SIGNED VALUES
-		alice_signature,
-		bob_signature,
-	}
-	callInitiateChannelClosingTxn, err := transaction.MakeApplicationNoOpTx(
-		app_id,                 // app_id
-		app_args,               // app_args
-		nil,                    // accounts
-		nil,                    // foreign_apps
-		nil,                    // foreign_assets
-		sp,                     // sp
-		sender_account.Address, // sender
-		nil,                    // note
-		types.Digest{},         // group
-		[32]byte{},             // lease
-		types.ZeroAddress,      // rekey_to
-	)
-	if err != nil {
-		fmt.Printf("Error creating application call 'initiateChannelClosing' transaction: %v\n", err)
-	}
-
-	// increase budget and send transaction
-	IncreaseBudgetSignAndSendTransaction(
-		algod_client,
-		app_id,
-		sender_account,
-		callInitiateChannelClosingTxn,
-		3930) // 1x Sha3_256 a 130 + 2x Ed25519Verify a 1900
+) (uint64, error) {
+	state := ChannelState{
+		Domain:       STATE_UPDATE_DOMAIN,
+		GenesisHash:  genesis_hash,
+		AppID:        app_id,
+		AliceBalance: alice_balance,
+		BobBalance:   bob_balance,
+		Timestamp:    int64(timestamp),
+		HTLCs:        htlcs,
+	}
+	timeout, fee, err := NewChannelClient(algod_client, app_id, sender_account).InitiateChannelClosing(state, alice_signature, bob_signature)
+	if err != nil {
+		return fee, err
+	}
+	fmt.Printf("Dispute window of channel %d ends in round %d\n", app_id, timeout)
+	return fee, nil
 }
 
 func RaiseDispute(
 	algod_client *algod.Client,
-	sender_account crypto.Account,
+	sender_account Signer,
 	// for signed hash
-	algorand_port uint64,
+	genesis_hash []byte,
 	app_id uint64,
 	alice_balance uint64,
 	bob_balance uint64,
 	timestamp uint64,
+	htlcs []HTLC,
 	// END for signed hash
 	alice_signature []byte,
 	bob_signature []byte,
-) {
-	sp, err := algod_client.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	algorandPortBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(algorandPortBytes, algorand_port)
-
-	aliceBalanceBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(aliceBalanceBytes, alice_balance)
-
-	bobBalanceBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(bobBalanceBytes, bob_balance)
-
-	timestampBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(timestampBytes, timestamp)
-
-	app_args := [][]byte{
-		[]byte("raiseDispute"),
-		// BEGIN SIGNED VALUES
-		algorandPortBytes, // algorand_port
-		aliceBalanceBytes, // alice_balance
-		bobBalanceBytes,   // bob_balance
-		timestampBytes,    // timestamp
-		// END SIGNED VALUES
-		alice_signature,
-		bob_signature,
-	}
-
-	callRaiseDisputeTxn, err := transaction.MakeApplicationNoOpTx(
-		app_id,                 // app_id
-		app_args,               // app_args
-		nil,                    // accounts
-		nil,                    // foreign_apps
-		nil,                    // foreign_assets
-		sp,                     // sp
-		sender_account.Address, // sender
-		nil,                    // note
-		types.Digest{},         // group
-		[32]byte{},             // lease
-		types.ZeroAddress,      // rekey_to
-	)
-	if err != nil {
-		fmt.Printf("Error creating application call 'raiseDispute' transaction: %v\n", err)
-	}
-
-	IncreaseBudgetSignAndSendTransaction(
-		algod_client,
-		app_id,
-		sender_account,
-		callRaiseDisputeTxn,
-		3930) // 1x Sha3_256 a 130 + 2x Ed25519Verify a 1900
+) (uint64, error) {
+	state := ChannelState{
+		Domain:       STATE_UPDATE_DOMAIN,
+		GenesisHash:  genesis_hash,
+		AppID:        app_id,
+		AliceBalance: alice_balance,
+		BobBalance:   bob_balance,
+		Timestamp:    int64(timestamp),
+		HTLCs:        htlcs,
+	}
+	return NewChannelClient(algod_client, app_id, sender_account).RaiseDispute(state, alice_signature, bob_signature)
 }
 
 func FinalizeCloseChannel(
 	algod_client *algod.Client,
-	sender_account crypto.Account,
+	sender_account Signer,
 	counterparty_address string,
 	app_id uint64,
-) {
-	sp, err := algod_client.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	app_args := [][]byte{
-		[]byte("finalizeChannelClosing"),
-	}
-	callFinalizeChannelClosingTxn, err := transaction.MakeApplicationNoOpTx(
-		app_id,   // app_id
-		app_args, // app_args
-		[]string{
-			sender_account.Address.String(),
-			counterparty_address,
-		}, // accounts
-		nil,                    // foreign_apps
-		nil,                    // foreign_assets
-		sp,                     // sp
-		sender_account.Address, // sender
-		nil,                    // note
-		types.Digest{},         // group
-		[32]byte{},             // lease
-		types.ZeroAddress,      // rekey_to
-	)
-	if err != nil {
-		fmt.Printf("Error creating application call 'finalizeChannelClosing' transaction: %v\n", err)
-	}
-
-	// sign transaction
-	_, signedCallFinalizeChannelClosingTxn, err := crypto.SignTransaction(sender_account.PrivateKey, callFinalizeChannelClosingTxn)
-	if err != nil {
-		fmt.Printf("Failed to sign transaction: %v\n", err)
-	}
-
-	// send transaction
-	pending_txn_id, err := algod_client.SendRawTransaction(signedCallFinalizeChannelClosingTxn).Do(context.Background())
-	if err != nil {
-		fmt.Printf("Failed to send transaction: %v\n", err)
-	}
-
-	// wait for confirmation
-	_, err = transaction.WaitForConfirmation(algod_client, pending_txn_id, 4, context.Background())
-	if err != nil {
-		fmt.Printf("Error waiting for confirmation: %v\n", err)
-	}
+	asset_id uint64,
+	htlcs []HTLC, // pending htlcs of the closing state
+) (uint64, error) {
+	// the fees of the payouts are paid from the app account
+	return NewChannelClient(algod_client, app_id, sender_account).FinalizeChannelClosing(counterparty_address, asset_id, htlcs)
 }
 
 func CooperativeCloseChannel(
 	algod_client *algod.Client,
-	sender_account crypto.Account,
+	sender_account Signer,
 	counterparty_address string,
 	// for signed hash
-	algorand_port uint64,
+	genesis_hash []byte,
 	app_id uint64,
 	alice_balance uint64,
 	bob_balance uint64,
 	timestamp uint64,
 	// END for signed hash
+	asset_id uint64,
 	alice_signature []byte,
 	bob_signature []byte,
-) {
-	sp, err := algod_client.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	algorandPortBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(algorandPortBytes, algorand_port)
-
-	aliceBalanceBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(aliceBalanceBytes, alice_balance)
-
-	bobBalanceBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(bobBalanceBytes, bob_balance)
-
-	timestampBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(timestampBytes, timestamp)
-
-	app_args := [][]byte{
-		[]byte("cooperativeClose"),
-		// BEGIN SIGNED VALUES
-		algorandPortBytes, // algorand_port
-		aliceBalanceBytes, // alice_balance
-		bobBalanceBytes,   // bob_balance
-		timestampBytes,    // timestamp
-		// END SIGNED VALUES
-		alice_signature,
-		bob_signature,
-	}
-	callCooperativeCloseTxn, err := transaction.MakeApplicationNoOpTx(
-		app_id,   // app_id
-		app_args, // app_args
-		[]string{
-			sender_account.Address.String(),
-			counterparty_address,
-		}, // accounts
-		nil,                    // foreign_apps
-		nil,                    // foreign_assets
-		sp,                     // sp
-		sender_account.Address, // sender
-		nil,                    // note
-		types.Digest{},         // group
-		[32]byte{},             // lease
-		types.ZeroAddress,      // rekey_to
-	)
-	if err != nil {
-		fmt.Printf("Error creating application call 'cooperativeClose' transaction: %v\n", err)
-	}
-
-	// increase budget and send transaction
-	IncreaseBudgetSignAndSendTransaction(
-		algod_client,
-		app_id,
-		sender_account,
-		callCooperativeCloseTxn,
-		3930) // 1x Sha3_256 a 130 + 2x Ed25519Verify a 1900
+) (uint64, error) {
+	state := ChannelState{
+		Domain:       CLOSE_CHANNEL_DOMAIN,
+		GenesisHash:  genesis_hash,
+		AppID:        app_id,
+		AssetID:      asset_id,
+		AliceBalance: alice_balance,
+		BobBalance:   bob_balance,
+		Timestamp:    int64(timestamp),
+	}
+	return NewChannelClient(algod_client, app_id, sender_account).CooperativeClose(counterparty_address, state, alice_signature, bob_signature)
 }
 
 func uint64ToBytes(val uint64) []byte {
@@ -590,90 +326,3 @@ func uint64ToBytes(val uint64) []byte {
 	binary.BigEndian.PutUint64(b, val)
 	return b
 }
-
-func IncreaseBudgetSignAndSendTransaction(
-	client *algod.Client,
-	appID uint64,
-	sender crypto.Account,
-	unsignedMainTransaction types.Transaction,
-	targetAmount uint64,
-) {
-	// get suggested params
-	sp, err := client.SuggestedParams().Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error getting suggested params: %v\n", err)
-	}
-
-	amountOfIncreaseBudgetTransactions := math.Ceil(float64(targetAmount) / 700)
-
-	// create unsigned transactions
-	var unsignedIncreaseBudgetTransactions []types.Transaction
-	for i := 0; i < int(amountOfIncreaseBudgetTransactions); i++ {
-		increaseBudgetAppTxn, err := transaction.MakeApplicationNoOpTx(
-			appID, // app_id
-			[][]byte{
-				[]byte("increaseBudget"),
-				[]byte(strconv.Itoa(i)),
-			}, // app_args
-			nil,               // accounts
-			nil,               // foreign_apps
-			nil,               // foreign_assets
-			sp,                // suggested params
-			sender.Address,    // sender
-			nil,               // note
-			types.Digest{},    // group
-			[32]byte{},        // lease
-			types.ZeroAddress, // rekey_to
-		)
-		if err != nil {
-			fmt.Printf("Error creating application call 'increaseBudget' transaction: %v\n", err)
-		}
-		unsignedIncreaseBudgetTransactions = append(unsignedIncreaseBudgetTransactions, increaseBudgetAppTxn)
-	}
-
-	// compute group id
-	group_id, err := crypto.ComputeGroupID(append([]types.Transaction{unsignedMainTransaction}, unsignedIncreaseBudgetTransactions...))
-	if err != nil {
-		fmt.Printf("Error computing group id: %v\n", err)
-	}
-	unsignedMainTransaction.Group = group_id
-	for i := 0; i < len(unsignedIncreaseBudgetTransactions); i++ {
-		unsignedIncreaseBudgetTransactions[i].Group = group_id
-	}
-
-	// sign transactions
-
-	_, signedMainTransaction, err := crypto.SignTransaction(sender.PrivateKey, unsignedMainTransaction)
-	if err != nil {
-		fmt.Printf("Error signing main transaction: %v\n", err)
-	}
-
-	var signedIncreaseBudgetTransactions [][]byte
-	// iterate over unsignedIncreaseBudgetTransactions and sign them
-	for _, increaseBudgetAppTxn := range unsignedIncreaseBudgetTransactions {
-		_, signedIncreaseBudgetAppTxn, err := crypto.SignTransaction(sender.PrivateKey, increaseBudgetAppTxn)
-		if err != nil {
-			fmt.Printf("Error signing 'increaseBudget' transaction: %v\n", err)
-		}
-		signedIncreaseBudgetTransactions = append(signedIncreaseBudgetTransactions, signedIncreaseBudgetAppTxn)
-	}
-
-	// append signed transactions to group
-	var signedGroupTxns []byte
-	signedGroupTxns = append(signedGroupTxns, signedMainTransaction...)
-	for _, signedTxn := range signedIncreaseBudgetTransactions {
-		signedGroupTxns = append(signedGroupTxns, signedTxn...)
-	}
-
-	// submit group transaction
-	pending_txn_id, err := client.SendRawTransaction(signedGroupTxns).Do(context.Background())
-	if err != nil {
-		fmt.Printf("Error submitting transaction: %v\n", err)
-	}
-
-	// wait for confirmation
-	_, err = transaction.WaitForConfirmation(client, pending_txn_id, 4, context.Background())
-	if err != nil {
-		fmt.Printf("Error waiting for confirmation: %v\n", err)
-	}
-}
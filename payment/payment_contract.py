@@ -5,6 +5,28 @@ import os
 
 FILENAME = "payment"
 
+# layout of one pending htlc inside the signed state:
+# offerer (1 byte, 0 = alice, 1 = bob) | amount (8 bytes) | hashlock (32 bytes) | timelock (8 bytes)
+HTLC_SIZE = Int(49)
+HTLC_OFFERED_BY_ALICE = Int(0)
+
+# signed messages start with the protocol version and the genesis hash of the network
+PROTOCOL_VERSION = 1
+
+# the contract implements the ARC-4 abi, the interface is described in contracts/payment_abi_v<N>.json
+ABI_RETURN_PREFIX = Bytes("base16", "151f7c75")
+STATE_ARGS = "byte[32],uint64,uint64,uint64,byte[],byte[64],byte[64]"
+
+
+# byte[] arguments are prefixed with their length
+def abiBytes(arg: Expr) -> Expr:
+	return Suffix(arg, Int(2))
+
+
+# logs the return value of a method returning an uint64
+def abiReturn(value: Expr) -> Expr:
+	return Log(Concat(ABI_RETURN_PREFIX, Itob(value)))
+
 
 def approval_program():
 	alice_address = Bytes("alice_address")			# byte_slice: creator and funder of the smart contract												
@@ -20,11 +42,25 @@ def approval_program():
 	latest_bob_balance = Bytes("latest_bob_balance")          	# uint: part of application specific state; value variable during execution
 	latest_state_timestamp = Bytes("latest_timestamp")			# uint: part of general state; when the latest transaction was signed by alice and bob
 	total_deposit = Bytes("total_deposit")						# uint: part of application specific state; value set by funding transaction
+	latest_htlcs_hash = Bytes("latest_htlcs_hash")				# byte_slice: sha3 hash of the pending htlcs of the latest state
+	settled_htlcs = Bytes("settled_htlcs")						# uint: bitmask of the pending htlcs that were settled on chain
+	asset_id = Bytes("asset_id")								# uint: asa the channel is denominated in, 0 for algo
+	bob_accepted = Bytes("bob_accepted")						# uint: 1 once bob accepted the channel, alice can no longer abort it
+	alice_signing_key = Bytes("alice_signing_key")				# byte_slice: key alice signs off-chain states with, set on creation or by the funding
+	bob_signing_key = Bytes("bob_signing_key")					# byte_slice: key bob signs off-chain states with, set when bob accepts
+	genesis_hash = Bytes("genesis_hash")						# byte_slice: network of the channel, part of every signed message
 
 
 	# closes the channel and pays out the funds to the respective parties
 	@Subroutine(TealType.none)
 	def closeAccountTo(beneficiary: Expr) -> Expr:
+		return If(App.globalGet(asset_id) == Int(0)).Then(
+			closeAlgoAccountTo(beneficiary)
+		).Else(
+			closeAssetAccountTo(beneficiary)
+		)
+
+	def closeAlgoAccountTo(beneficiary: Expr) -> Expr:
 		return Seq(
 			InnerTxnBuilder.Begin(),
 
@@ -50,6 +86,75 @@ def approval_program():
 			InnerTxnBuilder.Submit(),
 		)
 
+	# pays out the asset balances, opts the app account out of the asset and returns the remaining algos
+	def closeAssetAccountTo(beneficiary: Expr) -> Expr:
+		return Seq(
+			InnerTxnBuilder.Begin(),
+
+			# pay Bob
+			InnerTxnBuilder.SetFields(
+				{
+					TxnField.type_enum: TxnType.AssetTransfer,
+					TxnField.xfer_asset: App.globalGet(asset_id),
+					TxnField.asset_amount: App.globalGet(latest_bob_balance),
+					TxnField.asset_receiver: App.globalGet(bob_address),
+				}
+			),
+			InnerTxnBuilder.Next(),
+
+			# pay Alice
+			InnerTxnBuilder.SetFields(
+				{
+					TxnField.type_enum: TxnType.AssetTransfer,
+					TxnField.xfer_asset: App.globalGet(asset_id),
+					TxnField.asset_amount: App.globalGet(latest_alice_balance),
+					TxnField.asset_receiver: App.globalGet(alice_address),
+					TxnField.asset_close_to: App.globalGet(alice_address),
+				}
+			),
+			InnerTxnBuilder.Next(),
+
+			# return the algos that paid for the minimum balance and fees
+			InnerTxnBuilder.SetFields(
+				{
+					TxnField.type_enum: TxnType.Payment,
+					TxnField.amount: Int(0),
+					TxnField.receiver: beneficiary,
+					TxnField.close_remainder_to: beneficiary,
+				}
+			),
+			InnerTxnBuilder.Submit(),
+		)
+
+	# sums up the amounts locked in the pending htlcs of a state
+	@Subroutine(TealType.uint64)
+	def sumHTLCAmounts(htlcs: Expr) -> Expr:
+		i = ScratchVar(TealType.uint64)
+		total = ScratchVar(TealType.uint64)
+		return Seq(
+			Assert(Len(htlcs) % HTLC_SIZE == Int(0)),
+			total.store(Int(0)),
+			For(i.store(Int(0)), i.load() < Len(htlcs), i.store(i.load() + HTLC_SIZE)).Do(
+				total.store(total.load() + ExtractUint64(htlcs, i.load() + Int(1)))
+			),
+			total.load(),
+		)
+
+	# gives the amounts of all htlcs that were not settled back to their offerer, only possible after they expired
+	@Subroutine(TealType.none)
+	def refundExpiredHTLCs(htlcs: Expr) -> Expr:
+		i = ScratchVar(TealType.uint64)
+		return For(i.store(Int(0)), i.load() * HTLC_SIZE < Len(htlcs), i.store(i.load() + Int(1))).Do(
+			If(GetBit(App.globalGet(settled_htlcs), i.load()) == Int(0)).Then(
+				Assert(Global.round() > ExtractUint64(htlcs, i.load() * HTLC_SIZE + Int(41))),
+				If(GetByte(htlcs, i.load() * HTLC_SIZE) == HTLC_OFFERED_BY_ALICE).Then(
+					App.globalPut(latest_alice_balance, App.globalGet(latest_alice_balance) + ExtractUint64(htlcs, i.load() * HTLC_SIZE + Int(1)))
+				).Else(
+					App.globalPut(latest_bob_balance, App.globalGet(latest_bob_balance) + ExtractUint64(htlcs, i.load() * HTLC_SIZE + Int(1)))
+				),
+			)
+		)
+
 	# rebalances the channel by moving funds from one party to the other
 	def rebalance(sender_balance, recipient_balance, payment_amount):
 		return Seq(
@@ -61,34 +166,138 @@ def approval_program():
 		)
 
 	# Initialization
+	# funds the smart contract in the same group as its creation, the funding transactions pay to the predicted app address
+	prev_txn_index = Txn.group_index() - Int(1)
+	next_txn_index = Txn.group_index() + Int(1)
+	on_create_funding = If(App.globalGet(asset_id) == Int(0)).Then(
+		# [algo funding, create]
+		Seq(
+			Assert(
+				And(
+					Gtxn[prev_txn_index].sender() == Txn.sender(),
+					Gtxn[prev_txn_index].type_enum() == TxnType.Payment,
+					Gtxn[prev_txn_index].amount() > App.globalGet(penalty_reserve) + Global.min_txn_fee(),
+					Gtxn[prev_txn_index].receiver() == Global.current_application_address(),
+				)
+			),
+			App.globalPut(latest_alice_balance, Gtxn[prev_txn_index].amount()),
+			App.globalPut(total_deposit, Gtxn[prev_txn_index].amount()),
+		)
+	).Else(
+		# [algo reserve, create, asset funding]
+		Seq(
+			Assert(
+				And(
+					Gtxn[prev_txn_index].sender() == Txn.sender(),
+					Gtxn[prev_txn_index].type_enum() == TxnType.Payment,
+					Gtxn[prev_txn_index].receiver() == Global.current_application_address(),
+				)
+			),
+			InnerTxnBuilder.Begin(),
+			InnerTxnBuilder.SetFields(
+				{
+					TxnField.type_enum: TxnType.AssetTransfer,
+					TxnField.xfer_asset: App.globalGet(asset_id),
+					TxnField.asset_receiver: Global.current_application_address(),
+					TxnField.asset_amount: Int(0),
+				}
+			),
+			InnerTxnBuilder.Submit(),
+			Assert(
+				And(
+					Gtxn[next_txn_index].sender() == Txn.sender(),
+					Gtxn[next_txn_index].type_enum() == TxnType.AssetTransfer,
+					Gtxn[next_txn_index].xfer_asset() == App.globalGet(asset_id),
+					Gtxn[next_txn_index].asset_amount() > App.globalGet(penalty_reserve),
+					Gtxn[next_txn_index].asset_receiver() == Global.current_application_address(),
+				)
+			),
+			App.globalPut(latest_alice_balance, Gtxn[next_txn_index].asset_amount()),
+			App.globalPut(total_deposit, Gtxn[next_txn_index].asset_amount()),
+		)
+	)
+
 	on_create = Seq(
 		# can be called by anyone
 		#
-		Assert(Txn.application_args.length() == Int(3)),
+		Assert(Txn.application_args.length() == Int(7)),
+		Assert(Txn.application_args[0] == MethodSignature("create(address,uint64,uint64,uint64,address,byte[32])void")),
+		Assert(Len(Txn.application_args[5]) == Int(32)),
+		Assert(Len(Txn.application_args[6]) == Int(32)),
 		# Set alice to sender of initial tx
 		App.globalPut(alice_address, Txn.sender()),
-		App.globalPut(bob_address, Txn.application_args[0]),
-		App.globalPut(penalty_reserve, Btoi(Txn.application_args[1])),
-		App.globalPut(dispute_window, Btoi(Txn.application_args[2])),
+		App.globalPut(bob_address, Txn.application_args[1]),
+		App.globalPut(penalty_reserve, Btoi(Txn.application_args[2])),
+		App.globalPut(dispute_window, Btoi(Txn.application_args[3])),
+		App.globalPut(asset_id, Btoi(Txn.application_args[4])),
+		App.globalPut(alice_signing_key, Txn.application_args[5]),
+		App.globalPut(genesis_hash, Txn.application_args[6]),
+		App.globalPut(latest_htlcs_hash, Sha3_256(Bytes(""))),
+		If(Global.group_size() > Int(1)).Then(on_create_funding),
 		Approve()
 	)
  
 	# funds the smart contract with the amount sent in the first transaction of the group
 	funding_txn_index = Txn.group_index() - Int(1)
 	on_funding = Seq(
+		# can only be called by alice
+		# the channel is funded once, before bob can accept it
+		Assert(App.globalGet(total_deposit) == Int(0)),
+		# alice registers the key derived from the id of the created app
+		Assert(Len(Txn.application_args[1]) == Int(32)),
+		App.globalPut(alice_signing_key, Txn.application_args[1]),
+		If(App.globalGet(asset_id) == Int(0)).Then(
+			Assert(
+				And(
+					Gtxn[funding_txn_index].sender() == Txn.sender(),
+					Gtxn[funding_txn_index].sender() == App.globalGet(alice_address),
+					Gtxn[funding_txn_index].type_enum() == TxnType.Payment,
+					Gtxn[funding_txn_index].amount() > App.globalGet(penalty_reserve) + Global.min_txn_fee(),
+					Gtxn[funding_txn_index].receiver() == Global.current_application_address(),
+				)
+			),
+
+			App.globalPut(latest_alice_balance, Gtxn[funding_txn_index].amount()),
+			App.globalPut(total_deposit, Gtxn[funding_txn_index].amount()),
+		).Else(
+			# asa channels are funded with an asset transfer, fees are paid from the algos of the app account
+			Assert(
+				And(
+					Gtxn[funding_txn_index].sender() == Txn.sender(),
+					Gtxn[funding_txn_index].sender() == App.globalGet(alice_address),
+					Gtxn[funding_txn_index].type_enum() == TxnType.AssetTransfer,
+					Gtxn[funding_txn_index].xfer_asset() == App.globalGet(asset_id),
+					Gtxn[funding_txn_index].asset_amount() > App.globalGet(penalty_reserve),
+					Gtxn[funding_txn_index].asset_receiver() == Global.current_application_address(),
+				)
+			),
+
+			App.globalPut(latest_alice_balance, Gtxn[funding_txn_index].asset_amount()),
+			App.globalPut(total_deposit, Gtxn[funding_txn_index].asset_amount()),
+		),
+		abiReturn(App.globalGet(total_deposit)),
+		Approve(),
+	)
+
+	# opts the app account into the asa of the channel, so that it can be funded
+	on_optInAsset = Seq(
 		# can only be called by alice
 		Assert(
 			And(
-				Gtxn[funding_txn_index].sender() == Txn.sender(),
-				Gtxn[funding_txn_index].sender() == App.globalGet(alice_address),
-				Gtxn[funding_txn_index].type_enum() == TxnType.Payment,
-				Gtxn[funding_txn_index].amount() > App.globalGet(penalty_reserve) + Global.min_txn_fee(),
-				Gtxn[funding_txn_index].receiver() == Global.current_application_address(),
+				Txn.sender() == App.globalGet(alice_address),
+				App.globalGet(asset_id) != Int(0),
 			)
 		),
-
-		App.globalPut(latest_alice_balance, Gtxn[funding_txn_index].amount()),
-		App.globalPut(total_deposit, Gtxn[funding_txn_index].amount()),
+		InnerTxnBuilder.Begin(),
+		InnerTxnBuilder.SetFields(
+			{
+				TxnField.type_enum: TxnType.AssetTransfer,
+				TxnField.xfer_asset: App.globalGet(asset_id),
+				TxnField.asset_receiver: Global.current_application_address(),
+				TxnField.asset_amount: Int(0),
+			}
+		),
+		InnerTxnBuilder.Submit(),
 		Approve(),
 	)
 
@@ -112,26 +321,32 @@ def approval_program():
 		Approve(),
 	)
 
-	algorand_port = Txn.application_args[1]
+	signed_genesis_hash = Txn.application_args[1] # genesis hash of the network the states were signed for
 	alice_balance = Txn.application_args[2]
 	bob_balance = Txn.application_args[3]
 	timestamp = Txn.application_args[4]
-	alice_signature = Txn.application_args[5] # 64 bytes
-	bob_signature = Txn.application_args[6] # 64 bytes
-	hash = Sha3_256( # cost: 130, takes 1 argument: data
+	htlcs = abiBytes(Txn.application_args[5]) # multiple of HTLC_SIZE bytes
+	alice_signature = Txn.application_args[6] # 64 bytes
+	bob_signature = Txn.application_args[7] # 64 bytes
+	state_update_hash = Sha3_256( # cost: 130, takes 1 argument: data
 			Concat(
 				Bytes("STATE_UPDATE"),
-				algorand_port,
+				Itob(Int(PROTOCOL_VERSION)),
+				App.globalGet(genesis_hash),
 				Bytes(","),
 				Itob(Global.current_application_id()),
 				Bytes(","),
+				Itob(App.globalGet(asset_id)),
+				Bytes(","),
 				alice_balance, 
 				Bytes(","),
 				bob_balance,
 				Bytes(","),
 				timestamp,
+				Bytes(","),
+				htlcs,
 				Bytes("END_STATE_UPDATE"),
-			)) # in bytes "alice_balance, bob_balance"
+			)) # in bytes "alice_balance, bob_balance, htlcs"
 	on_initiateChannelClosing = Seq(
 		# can only be called by alice or bob
 		Assert(
@@ -140,19 +355,25 @@ def approval_program():
 				Txn.sender() == App.globalGet(bob_address)
 			)
 		),
+		# a closing can only be initiated once, raiseDispute replaces its state
+		Assert(App.globalGet(timeout) == Int(0)),
 		If (And(
+				signed_genesis_hash == App.globalGet(genesis_hash),
 				# https://pyteal.readthedocs.io/en/stable/crypto.html
 				Ed25519Verify_Bare(	# cost: 1900, takes 3 arguments: data, sig 64 bytes, key 32 bytes
-					hash,
+					state_update_hash,
 					alice_signature, # signature
-					App.globalGet(alice_address), # has to be comitted on chain
+					App.globalGet(alice_signing_key), # has to be comitted on chain
 				),
 				Ed25519Verify_Bare(
-					hash,
+					state_update_hash,
 					bob_signature,
-					App.globalGet(bob_address),
+					App.globalGet(bob_signing_key),
 				),
-				Btoi(alice_balance) + Btoi(bob_balance) == App.globalGet(total_deposit),
+				Btoi(alice_balance) + Btoi(bob_balance) + sumHTLCAmounts(htlcs) == App.globalGet(total_deposit),
+				# states signed before the latest deposit or withdrawal must not be closed with,
+				# the state recorded by it may be
+				App.globalGet(latest_state_timestamp) <= Btoi(timestamp),
 			)
 		).Then(
 			# set closing initiator
@@ -167,17 +388,25 @@ def approval_program():
 			App.globalPut(latest_state_timestamp, Btoi(timestamp)), 					# store state timestamp
 			App.globalPut(latest_alice_balance, Btoi(alice_balance)),					# store latest balances of alice
 			App.globalPut(latest_bob_balance, Btoi(bob_balance)),						# store latest balances of bob
+			App.globalPut(latest_htlcs_hash, Sha3_256(htlcs)),							# store pending htlcs
+			App.globalPut(settled_htlcs, Int(0)),										# none of them is settled yet
 		),
+		abiReturn(App.globalGet(timeout)),
 		Approve(),
 	)
 
-	hash = Sha3_256( # cost: 130, takes 1 argument: data
+	close_alice_signature = Txn.application_args[5] # 64 bytes
+	close_bob_signature = Txn.application_args[6] # 64 bytes
+	close_channel_hash = Sha3_256( # cost: 130, takes 1 argument: data
 			Concat(
 				Bytes("CLOSE_CHANNEL"),
-				algorand_port,
+				Itob(Int(PROTOCOL_VERSION)),
+				App.globalGet(genesis_hash),
 				Bytes(","),
 				Itob(Global.current_application_id()),
 				Bytes(","),
+				Itob(App.globalGet(asset_id)),
+				Bytes(","),
 				alice_balance, 
 				Bytes(","),
 				bob_balance,
@@ -185,7 +414,10 @@ def approval_program():
 				timestamp,
 				Bytes("END_CLOSE_CHANNEL"),
 			)) # in bytes "alice_balance, bob_balance"
-	on_cooperativeClose = Seq(
+	# adds funds to an open channel; alice and bob co-sign the new state, in which the depositor's balance increased by the deposit
+	deposit_txn_index = Txn.group_index() - Int(1)
+	deposit_amount = ScratchVar(TealType.uint64)
+	on_deposit = Seq(
 		# can only be called by alice or bob
 		Assert(
 			Or(
@@ -197,17 +429,232 @@ def approval_program():
 		Assert(
 			App.globalGet(timeout) == Int(0)
 		),
-		If(
+		Assert(Gtxn[deposit_txn_index].sender() == Txn.sender()),
+		If(App.globalGet(asset_id) == Int(0)).Then(
+			Assert(
+				And(
+					Gtxn[deposit_txn_index].type_enum() == TxnType.Payment,
+					Gtxn[deposit_txn_index].receiver() == Global.current_application_address(),
+				)
+			),
+			deposit_amount.store(Gtxn[deposit_txn_index].amount()),
+		).Else(
+			Assert(
+				And(
+					Gtxn[deposit_txn_index].type_enum() == TxnType.AssetTransfer,
+					Gtxn[deposit_txn_index].xfer_asset() == App.globalGet(asset_id),
+					Gtxn[deposit_txn_index].asset_receiver() == Global.current_application_address(),
+				)
+			),
+			deposit_amount.store(Gtxn[deposit_txn_index].asset_amount()),
+		),
+		Assert(
 			And(
+				signed_genesis_hash == App.globalGet(genesis_hash),
 				Ed25519Verify_Bare(
-					hash,
+					state_update_hash,
 					alice_signature,
-					App.globalGet(alice_address),
+					App.globalGet(alice_signing_key),
 				),
 				Ed25519Verify_Bare(
-					hash,
+					state_update_hash,
 					bob_signature,
-					App.globalGet(bob_address),
+					App.globalGet(bob_signing_key),
+				),
+				# states signed before the deposit no longer add up to the total deposit
+				Btoi(alice_balance) + Btoi(bob_balance) + sumHTLCAmounts(htlcs) == App.globalGet(total_deposit) + deposit_amount.load(),
+				App.globalGet(latest_state_timestamp) < Btoi(timestamp),
+			)
+		),
+		App.globalPut(total_deposit, App.globalGet(total_deposit) + deposit_amount.load()),
+		App.globalPut(latest_state_timestamp, Btoi(timestamp)), 					# store state timestamp
+		App.globalPut(latest_alice_balance, Btoi(alice_balance)),					# store latest balances of alice
+		App.globalPut(latest_bob_balance, Btoi(bob_balance)),						# store latest balances of bob
+		abiReturn(App.globalGet(total_deposit)),
+		Approve(),
+	)
+
+	withdraw_amount = Txn.application_args[8]
+	withdraw_hash = Sha3_256( # cost: 130, takes 1 argument: data
+			Concat(
+				Bytes("WITHDRAW"),
+				Itob(Int(PROTOCOL_VERSION)),
+				App.globalGet(genesis_hash),
+				Bytes(","),
+				Itob(Global.current_application_id()),
+				Bytes(","),
+				Itob(App.globalGet(asset_id)),
+				Bytes(","),
+				alice_balance,
+				Bytes(","),
+				bob_balance,
+				Bytes(","),
+				timestamp,
+				Bytes(","),
+				htlcs,
+				Bytes(","),
+				withdraw_amount,
+				Bytes(","),
+				Txn.sender(), # only the withdrawer authorized by both parties receives the funds
+				Bytes("END_WITHDRAW"),
+			))
+	on_withdraw = Seq(
+		# can only be called by alice or bob
+		Assert(
+			Or(
+				Txn.sender() == App.globalGet(alice_address),
+				Txn.sender() == App.globalGet(bob_address)
+			)
+		),
+		# can only be called if dispute window has not been initiated
+		Assert(
+			App.globalGet(timeout) == Int(0)
+		),
+		Assert(
+			And(
+				signed_genesis_hash == App.globalGet(genesis_hash),
+				Ed25519Verify_Bare(
+					withdraw_hash,
+					alice_signature,
+					App.globalGet(alice_signing_key),
+				),
+				Ed25519Verify_Bare(
+					withdraw_hash,
+					bob_signature,
+					App.globalGet(bob_signing_key),
+				),
+				# states signed before the withdrawal no longer add up to the total deposit
+				Btoi(alice_balance) + Btoi(bob_balance) + sumHTLCAmounts(htlcs) + Btoi(withdraw_amount) == App.globalGet(total_deposit),
+				App.globalGet(latest_state_timestamp) < Btoi(timestamp),
+			)
+		),
+		App.globalPut(total_deposit, App.globalGet(total_deposit) - Btoi(withdraw_amount)),
+		App.globalPut(latest_state_timestamp, Btoi(timestamp)), 					# store state timestamp
+		App.globalPut(latest_alice_balance, Btoi(alice_balance)),					# store latest balances of alice
+		App.globalPut(latest_bob_balance, Btoi(bob_balance)),						# store latest balances of bob
+		# the inner transaction fee is pooled from the app call
+		If(App.globalGet(asset_id) == Int(0)).Then(
+			Seq(
+				InnerTxnBuilder.Begin(),
+				InnerTxnBuilder.SetFields(
+					{
+						TxnField.type_enum: TxnType.Payment,
+						TxnField.amount: Btoi(withdraw_amount),
+						TxnField.receiver: Txn.sender(),
+						TxnField.fee: Int(0),
+					}
+				),
+				InnerTxnBuilder.Submit(),
+			)
+		).Else(
+			Seq(
+				InnerTxnBuilder.Begin(),
+				InnerTxnBuilder.SetFields(
+					{
+						TxnField.type_enum: TxnType.AssetTransfer,
+						TxnField.xfer_asset: App.globalGet(asset_id),
+						TxnField.asset_amount: Btoi(withdraw_amount),
+						TxnField.asset_receiver: Txn.sender(),
+						TxnField.fee: Int(0),
+					}
+				),
+				InnerTxnBuilder.Submit(),
+			)
+		),
+		abiReturn(App.globalGet(total_deposit)),
+		Approve(),
+	)
+
+	on_acceptChannel = Seq(
+		# can only be called by bob
+		Assert(Txn.sender() == App.globalGet(bob_address)),
+		Assert(App.globalGet(timeout) == Int(0)),
+		# the channel must be funded
+		Assert(App.globalGet(total_deposit) > Int(0)),
+		# the signing key of bob must not change once states were signed with it
+		Assert(App.globalGet(bob_accepted) == Int(0)),
+		# bob registers the key for signing off-chain states
+		Assert(Len(Txn.application_args[1]) == Int(32)),
+		App.globalPut(bob_signing_key, Txn.application_args[1]),
+		App.globalPut(bob_accepted, Int(1)),
+		Approve(),
+	)
+
+	# refunds alice if bob never accepted the channel
+	on_abortChannelOpen = Seq(
+		# can only be called by alice
+		Assert(Txn.sender() == App.globalGet(alice_address)),
+		# bob never signed a state before accepting
+		Assert(App.globalGet(bob_accepted) == Int(0)),
+		Assert(App.globalGet(timeout) == Int(0)),
+		App.globalPut(total_deposit, Int(0)),
+		App.globalPut(latest_alice_balance, Int(0)),
+		If(App.globalGet(asset_id) == Int(0)).Then(
+			Seq(
+				InnerTxnBuilder.Begin(),
+				InnerTxnBuilder.SetFields(
+					{
+						TxnField.type_enum: TxnType.Payment,
+						TxnField.amount: Int(0),
+						TxnField.receiver: App.globalGet(alice_address),
+						TxnField.close_remainder_to: App.globalGet(alice_address),
+					}
+				),
+				InnerTxnBuilder.Submit(),
+			)
+		).Else(
+			Seq(
+				InnerTxnBuilder.Begin(),
+				# return the asset and opt out of it
+				InnerTxnBuilder.SetFields(
+					{
+						TxnField.type_enum: TxnType.AssetTransfer,
+						TxnField.xfer_asset: App.globalGet(asset_id),
+						TxnField.asset_amount: Int(0),
+						TxnField.asset_receiver: App.globalGet(alice_address),
+						TxnField.asset_close_to: App.globalGet(alice_address),
+					}
+				),
+				InnerTxnBuilder.Next(),
+				# return the remaining algos
+				InnerTxnBuilder.SetFields(
+					{
+						TxnField.type_enum: TxnType.Payment,
+						TxnField.amount: Int(0),
+						TxnField.receiver: App.globalGet(alice_address),
+						TxnField.close_remainder_to: App.globalGet(alice_address),
+					}
+				),
+				InnerTxnBuilder.Submit(),
+			)
+		),
+		Approve(),
+	)
+
+	on_cooperativeClose = Seq(
+		# can only be called by alice or bob
+		Assert(
+			Or(
+				Txn.sender() == App.globalGet(alice_address),
+				Txn.sender() == App.globalGet(bob_address)
+			)
+		),
+		# can only be called if dispute window has not been initiated
+		Assert(
+			App.globalGet(timeout) == Int(0)
+		),
+		If(
+			And(
+				signed_genesis_hash == App.globalGet(genesis_hash),
+				Ed25519Verify_Bare(
+					close_channel_hash,
+					close_alice_signature,
+					App.globalGet(alice_signing_key),
+				),
+				Ed25519Verify_Bare(
+					close_channel_hash,
+					close_bob_signature,
+					App.globalGet(bob_signing_key),
 				),
 				Btoi(alice_balance) + Btoi(bob_balance) == App.globalGet(total_deposit),
 			)
@@ -226,18 +673,19 @@ def approval_program():
 		# can only be called by anyone to enable third party to raise dispute, preventing dos attacks
 		#
 		If (And(
+				signed_genesis_hash == App.globalGet(genesis_hash),
 				# https://pyteal.readthedocs.io/en/stable/crypto.html
 				Ed25519Verify_Bare(	# cost: 1900, takes 3 arguments: data, sig 64 bytes, key 32 bytes
-					hash,
+					state_update_hash,
 					alice_signature, # signature
-					App.globalGet(alice_address), # has to be comitted on chain
+					App.globalGet(alice_signing_key), # has to be comitted on chain
 				),
 				Ed25519Verify_Bare(
-					hash,
+					state_update_hash,
 					bob_signature,
-					App.globalGet(bob_address),
+					App.globalGet(bob_signing_key),
 				),
-				Btoi(alice_balance) + Btoi(bob_balance) == App.globalGet(total_deposit),
+				Btoi(alice_balance) + Btoi(bob_balance) + sumHTLCAmounts(htlcs) == App.globalGet(total_deposit),
 				App.globalGet(latest_state_timestamp) < Btoi(timestamp), # indeed a newer state
 			)
 		).Then(			
@@ -245,6 +693,8 @@ def approval_program():
 			App.globalPut(latest_state_timestamp, Btoi(timestamp)), 						# store state timestamp
 			App.globalPut(latest_alice_balance, Btoi(alice_balance)),				# store latest balances of alice
 			App.globalPut(latest_bob_balance, Btoi(bob_balance)),					# store latest balances of bob
+			App.globalPut(latest_htlcs_hash, Sha3_256(htlcs)),						# store pending htlcs
+			App.globalPut(settled_htlcs, Int(0)),									# none of them is settled yet
 
 			# punish closing initiator
 			If(App.globalGet(closing_initiator) == Bytes("alice")).Then(
@@ -259,11 +709,47 @@ def approval_program():
 	)
 
 
+	# settles a pending htlc of the closing state by revealing its preimage before the htlc expires
+	settle_htlcs = abiBytes(Txn.application_args[1])
+	settle_htlc_offset = Btoi(Txn.application_args[2]) * HTLC_SIZE
+	settle_htlc_preimage = abiBytes(Txn.application_args[3])
+	on_settleHTLC = Seq(
+		# can be called by anyone who knows the preimage
+		#
+		Assert(
+			And(
+				App.globalGet(timeout) > Int(0),										# channel is closing
+				Sha3_256(settle_htlcs) == App.globalGet(latest_htlcs_hash),			# htlcs of the closing state
+				settle_htlc_offset < Len(settle_htlcs),
+				GetBit(App.globalGet(settled_htlcs), Btoi(Txn.application_args[2])) == Int(0),	# not settled yet
+				Sha256(settle_htlc_preimage) == Extract(settle_htlcs, settle_htlc_offset + Int(9), Int(32)),
+				Global.round() <= ExtractUint64(settle_htlcs, settle_htlc_offset + Int(41)),	# not expired
+			)
+		),
+		App.globalPut(settled_htlcs, SetBit(App.globalGet(settled_htlcs), Btoi(Txn.application_args[2]), Int(1))),
+
+		# pay the receiver, i.e. the party that did not offer the htlc
+		If(GetByte(settle_htlcs, settle_htlc_offset) == HTLC_OFFERED_BY_ALICE).Then(
+			App.globalPut(latest_bob_balance, App.globalGet(latest_bob_balance) + ExtractUint64(settle_htlcs, settle_htlc_offset + Int(1)))
+		).Else(
+			App.globalPut(latest_alice_balance, App.globalGet(latest_alice_balance) + ExtractUint64(settle_htlcs, settle_htlc_offset + Int(1)))
+		),
+		Approve(),
+	)
+
 	on_finalizeChannelClosing = Seq(
 		# can be called by anyone
 		# 
-		If (Global.round() > App.globalGet(timeout)).Then(
+		If (And(
+				App.globalGet(timeout) > Int(0),			# channel is closing
+				Global.round() > App.globalGet(timeout),
+			)
+		).Then(
 			# timeout has passed
+			# refund htlcs that were not settled
+			Assert(Sha3_256(abiBytes(Txn.application_args[1])) == App.globalGet(latest_htlcs_hash)),
+			refundExpiredHTLCs(abiBytes(Txn.application_args[1])),
+
 			# send funds to alice
 			closeAccountTo(App.globalGet(alice_address)),
 		),
@@ -272,24 +758,32 @@ def approval_program():
 
 	# NoOp call
 	# Implements payment logic
-	# methods, send transaction, load latest state, settle dispute
+	# methods are routed by their ARC-4 selector, send transaction, load latest state, settle dispute
 	on_call_method = Txn.application_args[0]
 	on_call = Seq(
 		Cond(
-			[on_call_method == Bytes("fund"), on_funding],
+			[on_call_method == MethodSignature("fund(txn,address)uint64"), on_funding],
+			[on_call_method == MethodSignature("optInAsset()void"), on_optInAsset],
+			[on_call_method == MethodSignature(f"deposit(txn,{STATE_ARGS})uint64"), on_deposit],
+			[on_call_method == MethodSignature(f"withdraw({STATE_ARGS},uint64)uint64"), on_withdraw],
+			[on_call_method == MethodSignature("acceptChannel(address)void"), on_acceptChannel],
+			[on_call_method == MethodSignature("abortChannelOpen()void"), on_abortChannelOpen],
 			# [on_call_method == Bytes("transact"), on_transacting],
-			[on_call_method == Bytes("increaseBudget"), on_increaseBudget],
-			[on_call_method == Bytes("initiateChannelClosing"), on_initiateChannelClosing],
-			[on_call_method == Bytes("raiseDispute"), on_raiseDispute],
-			[on_call_method == Bytes("finalizeChannelClosing"), on_finalizeChannelClosing],
-			[on_call_method == Bytes("cooperativeClose"), on_cooperativeClose],
+			[on_call_method == MethodSignature("increaseBudget(uint64)void"), on_increaseBudget],
+			[on_call_method == MethodSignature(f"initiateChannelClosing({STATE_ARGS})uint64"), on_initiateChannelClosing],
+			[on_call_method == MethodSignature(f"raiseDispute({STATE_ARGS})void"), on_raiseDispute],
+			[on_call_method == MethodSignature("settleHTLC(byte[],uint64,byte[])void"), on_settleHTLC],
+			[on_call_method == MethodSignature("finalizeChannelClosing(byte[])void"), on_finalizeChannelClosing],
+			[on_call_method == MethodSignature("cooperativeClose(byte[32],uint64,uint64,uint64,byte[64],byte[64])void"), on_cooperativeClose],
 		)
 	)
 
-	# Only the owner is allowed to delete the application
-	on_delete = Seq(
-		# [Txn.sender() == App.globalGet(alice_address), Approve()]
-		Reject(),
+	# Only the owner is allowed to delete the application, once the app account was closed out
+	on_delete = Return(
+		And(
+			Txn.sender() == App.globalGet(alice_address),
+			Balance(Global.current_application_address()) == Int(0),
+		)
 	)
 
 	program = Cond(
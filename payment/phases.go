@@ -0,0 +1,97 @@
+package payment
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// phases recorded by a PhaseRecorder
+const (
+	PHASE_SIGNING              = "signing"
+	PHASE_P2P_ROUND_TRIP       = "p2p_round_trip"
+	PHASE_PARTNER_VERIFICATION = "partner_verification"
+	PHASE_SIMULATION           = "simulation"
+	PHASE_SUBMISSION           = "transaction_submission"
+	PHASE_CONFIRMATION         = "confirmation_wait"
+)
+
+// PhaseSpan is the time one phase of a call took
+type PhaseSpan struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// PhaseRecorder collects the phase spans of one call. It is safe for concurrent use, a nil recorder records nothing.
+type PhaseRecorder struct {
+	mu    sync.Mutex
+	spans []PhaseSpan
+}
+
+func NewPhaseRecorder() *PhaseRecorder {
+	return &PhaseRecorder{}
+}
+
+// Start begins a span of the phase name, calling the returned function ends it
+func (p *PhaseRecorder) Start(name string) (end func()) {
+	if p == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.spans = append(p.spans, PhaseSpan{Name: name, Start: start, End: time.Now()})
+	}
+}
+
+// Spans returns the recorded spans in the order they started
+func (p *PhaseRecorder) Spans() []PhaseSpan {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	spans := append([]PhaseSpan{}, p.spans...)
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+	return spans
+}
+
+// recordingSigner records a signing span for every signature of the wrapped signer,
+// the payment operations record the phases of their transactions into its recorder
+type recordingSigner struct {
+	Signer
+	recorder *PhaseRecorder
+}
+
+// WithPhaseRecorder returns a signer which signs with signer and records the phases of the operations it is passed to
+func WithPhaseRecorder(signer Signer, recorder *PhaseRecorder) Signer {
+	if recorder == nil {
+		return signer
+	}
+	return &recordingSigner{Signer: signer, recorder: recorder}
+}
+
+// PhaseRecorderOf returns the recorder of a signer created by WithPhaseRecorder, nil otherwise
+func PhaseRecorderOf(signer Signer) *PhaseRecorder {
+	recording_signer, ok := signer.(*recordingSigner)
+	if !ok {
+		return nil
+	}
+	return recording_signer.recorder
+}
+
+func (r *recordingSigner) SignBytes(data []byte) ([]byte, error) {
+	defer r.recorder.Start(PHASE_SIGNING)()
+	return r.Signer.SignBytes(data)
+}
+
+func (r *recordingSigner) SignTransaction(txn types.Transaction) (string, []byte, error) {
+	defer r.recorder.Start(PHASE_SIGNING)()
+	return r.Signer.SignTransaction(txn)
+}
@@ -0,0 +1,273 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Signer signs transactions and off-chain states on behalf of an account, so its keys can live outside of the node
+type Signer interface {
+	// Address is the account the signer signs for
+	Address() types.Address
+	// SignBytes signs raw bytes, as verified by ed25519verify_bare in the contract
+	SignBytes(data []byte) ([]byte, error)
+	// SignTransaction returns the id and the msgpack encoded signed transaction
+	SignTransaction(txn types.Transaction) (txid string, signed_txn []byte, err error)
+}
+
+// KeyExporter is implemented by signers which can hand out the private key, e.g. to decrypt onions sent to the account
+type KeyExporter interface {
+	ExportPrivateKey() (ed25519.PrivateKey, error)
+}
+
+// AccountSigner signs with a private key held in memory
+type AccountSigner struct {
+	account crypto.Account
+}
+
+func NewAccountSigner(account crypto.Account) *AccountSigner {
+	return &AccountSigner{account: account}
+}
+
+func (a *AccountSigner) Address() types.Address {
+	return a.account.Address
+}
+
+func (a *AccountSigner) SignBytes(data []byte) ([]byte, error) {
+	signed_bytes := ed25519.Sign(a.account.PrivateKey, data)
+	if signed_bytes == nil {
+		return nil, errors.New("error signing bytes")
+	}
+	return signed_bytes, nil
+}
+
+func (a *AccountSigner) SignTransaction(txn types.Transaction) (string, []byte, error) {
+	return crypto.SignTransaction(a.account.PrivateKey, txn)
+}
+
+func (a *AccountSigner) ExportPrivateKey() (ed25519.PrivateKey, error) {
+	return a.account.PrivateKey, nil
+}
+
+// AuthorizedSigner signs for a funding account whose authorizing key differs from its address,
+// i.e. a rekeyed account or a multisig account. Off-chain states are signed with a single-sig key.
+type AuthorizedSigner struct {
+	address   types.Address
+	keys      []ed25519.PrivateKey    // authorizing key, or the multisig keys meeting the threshold
+	multisig  *crypto.MultisigAccount // set if the authorizing address is a multisig account
+	state_key ed25519.PrivateKey      // signs raw bytes, ed25519verify_bare cannot check multisig signatures
+}
+
+// NewAuthorizedSigner looks up the authorizing address of the account via algod and checks that the keys
+// can authorize it, either as a single key or as the given multisig account.
+func NewAuthorizedSigner(algodClient *algod.Client, address string, keys []ed25519.PrivateKey, multisig *crypto.MultisigAccount) (*AuthorizedSigner, error) {
+	decoded_address, err := types.DecodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no authorizing keys")
+	}
+	account_info, err := algodClient.AccountInformation(address).Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	auth_address := decoded_address
+	if account_info.AuthAddr != "" {
+		auth_address, err = types.DecodeAddress(account_info.AuthAddr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if multisig == nil {
+		if len(keys) != 1 {
+			return nil, errors.New("a single-sig account is authorized by exactly one key")
+		}
+		key_address, err := crypto.GenerateAddressFromSK(keys[0])
+		if err != nil {
+			return nil, err
+		}
+		if key_address != auth_address {
+			return nil, fmt.Errorf("%s is authorized by %s, not by the given key", address, auth_address)
+		}
+	} else {
+		multisig_address, err := multisig.Address()
+		if err != nil {
+			return nil, err
+		}
+		if multisig_address != auth_address {
+			return nil, fmt.Errorf("%s is authorized by %s, not by the given multisig account", address, auth_address)
+		}
+		// every key has to belong to the multisig account, and enough keys to meet the threshold
+		for _, key := range keys {
+			is_member := false
+			for _, public_key := range multisig.Pks {
+				is_member = is_member || bytes.Equal(public_key, key.Public().(ed25519.PublicKey))
+			}
+			if !is_member {
+				return nil, errors.New("key is not part of the multisig account")
+			}
+		}
+		if len(keys) < int(multisig.Threshold) {
+			return nil, fmt.Errorf("multisig account needs %d keys, got %d", multisig.Threshold, len(keys))
+		}
+	}
+
+	return &AuthorizedSigner{
+		address:   decoded_address,
+		keys:      keys,
+		multisig:  multisig,
+		state_key: keys[0],
+	}, nil
+}
+
+func (a *AuthorizedSigner) Address() types.Address {
+	return a.address
+}
+
+func (a *AuthorizedSigner) SignBytes(data []byte) ([]byte, error) {
+	return ed25519.Sign(a.state_key, data), nil
+}
+
+// SignTransaction sets the authorizing address of the signed transaction if it differs from the sender
+func (a *AuthorizedSigner) SignTransaction(txn types.Transaction) (string, []byte, error) {
+	if a.multisig == nil {
+		return crypto.SignTransaction(a.keys[0], txn)
+	}
+	txid, signed_txn, err := crypto.SignMultisigTransaction(a.keys[0], *a.multisig, txn)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, key := range a.keys[1:] {
+		txid, signed_txn, err = crypto.AppendMultisigTransaction(key, *a.multisig, signed_txn)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return txid, signed_txn, nil
+}
+
+// DeriveChannelSigner derives the key signing the off-chain states of the channel app_id from the account key.
+// ed25519 signatures are deterministic, so the key can be recovered from the account alone.
+// The key of a kmd signer never leaves kmd, its channel keys are derived from the channel seed it was configured with.
+func DeriveChannelSigner(account Signer, appID uint64) (Signer, error) {
+	derivation_message := append([]byte("CHANNEL_SIGNING_KEY"), uint64ToBytes(appID)...)
+	if recording_signer, ok := account.(*recordingSigner); ok {
+		account = recording_signer.Signer
+	}
+	var seed [32]byte
+	if kmd_signer, ok := account.(*KMDSigner); ok {
+		seed = sha512.Sum512_256(append(append([]byte{}, kmd_signer.channel_seed...), derivation_message...))
+	} else {
+		derivation_signature, err := account.SignBytes(derivation_message)
+		if err != nil {
+			return nil, err
+		}
+		seed = sha512.Sum512_256(derivation_signature)
+	}
+	channel_account, err := crypto.AccountFromPrivateKey(ed25519.NewKeyFromSeed(seed[:]))
+	if err != nil {
+		return nil, err
+	}
+	return NewAccountSigner(channel_account), nil
+}
+
+// KMDSigner signs transactions with a key kept in a kmd wallet. kmd cannot sign raw bytes,
+// so off-chain states are signed with the channel keys derived by DeriveChannelSigner.
+type KMDSigner struct {
+	client          kmd.Client
+	wallet_id       string
+	wallet_password string
+	address         types.Address
+	channel_seed    []byte // derives the channel keys, the account key is never exported
+}
+
+// NewKMDSigner looks up the wallet with the given name, which has to hold the key of address.
+// The channel keys are derived from channelSeed, which has to be kept as safe as the wallet to recover them.
+func NewKMDSigner(client kmd.Client, wallet_name string, wallet_password string, address string, channelSeed []byte) (*KMDSigner, error) {
+	decoded_address, err := types.DecodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(channelSeed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("channel seed must have %d bytes, got %d", ed25519.SeedSize, len(channelSeed))
+	}
+	wallets, err := client.ListWallets()
+	if err != nil {
+		return nil, err
+	}
+	for _, wallet := range wallets.Wallets {
+		if wallet.Name != wallet_name {
+			continue
+		}
+		k := &KMDSigner{
+			client:          client,
+			wallet_id:       wallet.ID,
+			wallet_password: wallet_password,
+			address:         decoded_address,
+			channel_seed:    channelSeed,
+		}
+		// make sure the wallet holds the key
+		err = k.withWalletHandle(func(wallet_handle string) error {
+			keys, err := client.ListKeys(wallet_handle)
+			if err != nil {
+				return err
+			}
+			for _, key := range keys.Addresses {
+				if key == address {
+					return nil
+				}
+			}
+			return errors.New("kmd wallet does not hold the key of " + address)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+	return nil, errors.New("kmd wallet " + wallet_name + " not found")
+}
+
+func (k *KMDSigner) Address() types.Address {
+	return k.address
+}
+
+// SignBytes fails, kmd has no endpoint for raw signatures and the key is never exported
+func (k *KMDSigner) SignBytes(data []byte) ([]byte, error) {
+	return nil, errors.New("kmd cannot sign raw bytes, states are signed with the derived channel key")
+}
+
+func (k *KMDSigner) SignTransaction(txn types.Transaction) (string, []byte, error) {
+	var signed_txn []byte
+	err := k.withWalletHandle(func(wallet_handle string) error {
+		response, err := k.client.SignTransaction(wallet_handle, k.wallet_password, txn)
+		if err != nil {
+			return err
+		}
+		signed_txn = response.SignedTransaction
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return crypto.GetTxID(txn), signed_txn, nil
+}
+
+func (k *KMDSigner) withWalletHandle(f func(wallet_handle string) error) error {
+	handle, err := k.client.InitWalletHandle(k.wallet_id, k.wallet_password)
+	if err != nil {
+		return err
+	}
+	defer k.client.ReleaseWalletHandle(handle.WalletHandleToken)
+	return f(handle.WalletHandleToken)
+}
@@ -0,0 +1,122 @@
+package payment
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	sandbox "github.com/dancodery/algorand-state-channels/payment/testing"
+)
+
+// startMockSandbox points the clients of the sandbox helpers at a mock sandbox for the duration of the test
+func startMockSandbox(t *testing.T) *sandbox.MockSandbox {
+	mock_sandbox := sandbox.StartMockSandbox()
+	t.Cleanup(mock_sandbox.Close)
+	t.Setenv("ALGOD_ADDRESS", mock_sandbox.AlgodAddress())
+	t.Setenv("KMD_ADDRESS", mock_sandbox.KmdAddress())
+	return mock_sandbox
+}
+
+func TestSandboxAccountsOnMockSandbox(t *testing.T) {
+	mock_sandbox := startMockSandbox(t)
+
+	accounts, err := sandbox.GetSandboxAccounts()
+	if err != nil {
+		t.Fatalf("listing sandbox accounts: %v", err)
+	}
+	if len(accounts) != sandbox.MOCK_SANDBOX_ACCOUNTS {
+		t.Fatalf("kmd holds %d accounts, expected %d", len(accounts), sandbox.MOCK_SANDBOX_ACCOUNTS)
+	}
+
+	recipient := crypto.GenerateAccount()
+	sandbox.FundAccount(sandbox.GetAlgodClient(), recipient.Address.String(), 5_000_000)
+	if balance := mock_sandbox.Ledger.Balance(recipient.Address); balance != 5_000_000 {
+		t.Errorf("funded account holds %d, expected 5000000", balance)
+	}
+}
+
+func TestKMDSignerOpensChannelOnMockSandbox(t *testing.T) {
+	mock_sandbox := startMockSandbox(t)
+	algod_client := sandbox.GetAlgodClient()
+	alice_account := crypto.GenerateAccount()
+	mock_sandbox.ImportAccount(alice_account)
+	sandbox.FundAccount(algod_client, alice_account.Address.String(), testAccountFunding)
+	bob := NewAccountSigner(mock_sandbox.Ledger.NewAccount(testAccountFunding))
+
+	channel_seed := crypto.GenerateAccount().PrivateKey.Seed()
+	alice, err := NewKMDSigner(sandbox.GetKmdClient(), sandbox.KMD_WALLET_NAME, sandbox.KMD_WALLET_PASSWORD, alice_account.Address.String(), channel_seed)
+	if err != nil {
+		t.Fatalf("making kmd signer: %v", err)
+	}
+	_, err = NewKMDSigner(sandbox.GetKmdClient(), sandbox.KMD_WALLET_NAME, sandbox.KMD_WALLET_PASSWORD, bob.Address().String(), channel_seed)
+	if err == nil {
+		t.Errorf("made a kmd signer for a key the wallet does not hold")
+	}
+	_, err = NewKMDSigner(sandbox.GetKmdClient(), sandbox.KMD_WALLET_NAME, sandbox.KMD_WALLET_PASSWORD, alice_account.Address.String(), nil)
+	if err == nil {
+		t.Errorf("made a kmd signer without channel seed")
+	}
+
+	// transactions are signed by kmd, the channel keys are derived from the channel seed,
+	// only the sandbox helpers funding the accounts export keys
+	funding_exports := mock_sandbox.KMD.ExportedKeys()
+	channel := newTestChannel(t, mock_sandbox.Ledger, algod_client, 0, alice, bob)
+	channel.accept()
+	exported_keys := mock_sandbox.KMD.ExportedKeys()
+	if exported_keys != funding_exports {
+		t.Errorf("kmd exported %d keys to open the channel", exported_keys-funding_exports)
+	}
+
+	// the channel key is recovered from the channel seed alone
+	recovered_signer, err := NewKMDSigner(sandbox.GetKmdClient(), sandbox.KMD_WALLET_NAME, sandbox.KMD_WALLET_PASSWORD, alice_account.Address.String(), channel_seed)
+	if err != nil {
+		t.Fatalf("making kmd signer: %v", err)
+	}
+	recovered_signing, err := DeriveChannelSigner(recovered_signer, channel.app_id)
+	if err != nil {
+		t.Fatalf("deriving signing key: %v", err)
+	}
+	if channel.alice_signing.Address() != recovered_signing.Address() {
+		t.Errorf("kmd signer derived the signing key %s, recovered %s", channel.alice_signing.Address(), recovered_signing.Address())
+	}
+	other_signing, err := DeriveChannelSigner(recovered_signer, channel.app_id+1)
+	if err != nil {
+		t.Fatalf("deriving signing key: %v", err)
+	}
+	if other_signing.Address() == recovered_signing.Address() {
+		t.Errorf("channels %d and %d share the signing key", channel.app_id, channel.app_id+1)
+	}
+	if app_state := channel.appState(); app_state.AliceAddress != alice_account.Address || !app_state.BobAccepted {
+		t.Errorf("channel of %s was not opened", app_state.AliceAddress)
+	}
+
+	// states are signed with the derived key, the key stays in kmd
+	channel.signedState(STATE_UPDATE_DOMAIN, testChannelFunding, 0, 1, nil)
+	_, err = alice.SignBytes([]byte("state"))
+	if err == nil {
+		t.Errorf("kmd signer signed raw bytes")
+	}
+	if mock_sandbox.KMD.ExportedKeys() != exported_keys {
+		t.Errorf("kmd exported %d keys after the channel was opened", mock_sandbox.KMD.ExportedKeys()-exported_keys)
+	}
+}
+
+func TestCompileTealFailsOnMockSandbox(t *testing.T) {
+	startMockSandbox(t)
+	algod_client := sandbox.GetAlgodClient()
+
+	if compiled := CompileTeal(algod_client, filepath.Join(t.TempDir(), "missing.teal")); compiled != nil {
+		t.Errorf("compiled a missing file into %x", compiled)
+	}
+
+	// algod rejects source it cannot assemble
+	path := filepath.Join(t.TempDir(), "invalid.teal")
+	err := os.WriteFile(path, []byte("#pragma version 7\nnot an opcode\n"), 0o644)
+	if err != nil {
+		t.Fatalf("writing teal file: %v", err)
+	}
+	if compiled := CompileTeal(algod_client, path); compiled != nil {
+		t.Errorf("compiled invalid teal into %x", compiled)
+	}
+}
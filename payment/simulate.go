@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// MAX_GROUP_SIZE is the maximum number of transactions in an atomic group
+const MAX_GROUP_SIZE = 16
+
+// APP_CALL_BUDGET is the opcode budget every app call adds to the pooled budget of its group
+const APP_CALL_BUDGET = 700
+
+// SimulationError is returned if algod rejects a group in simulation, nothing was submitted and no fees were spent
+type SimulationError struct {
+	FailedAt []uint64 // path to the failing transaction, starting with its index in the group
+	Message  string
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("simulation failed at transaction %v: %s", e.FailedAt, e.Message)
+}
+
+// GroupEstimate describes a group sized by simulation before it is signed and submitted
+type GroupEstimate struct {
+	BudgetConsumed     uint64 // opcodes used by all app calls of the group
+	BudgetPaddingCalls int    // increaseBudget calls appended to the group
+	Fee                uint64 // sum of the fees of all transactions of the group in microalgos
+}
+
+// SimulateGroup simulates the unsigned transactions as one group on the latest round.
+// extraBudget lifts the opcode budget, so that the consumption of groups lacking budget can be measured.
+func SimulateGroup(client *algod.Client, unsignedTransactions []types.Transaction, extraBudget uint64) (models.SimulateTransactionGroupResult, error) {
+	group := append([]types.Transaction{}, unsignedTransactions...)
+	if len(group) > 1 {
+		group_id, err := crypto.ComputeGroupID(group)
+		if err != nil {
+			return models.SimulateTransactionGroupResult{}, err
+		}
+		for i := range group {
+			group[i].Group = group_id
+		}
+	}
+
+	signed_txns := make([]types.SignedTxn, 0, len(group))
+	for _, txn := range group {
+		signed_txns = append(signed_txns, types.SignedTxn{Txn: txn})
+	}
+	response, err := client.SimulateTransaction(models.SimulateRequest{
+		AllowEmptySignatures: true,
+		ExtraOpcodeBudget:    extraBudget,
+		TxnGroups:            []models.SimulateRequestTransactionGroup{{Txns: signed_txns}},
+	}).Do(context.Background())
+	if err != nil {
+		return models.SimulateTransactionGroupResult{}, err
+	}
+	if len(response.TxnGroups) != 1 {
+		return models.SimulateTransactionGroupResult{}, fmt.Errorf("expected 1 simulated group, got %d", len(response.TxnGroups))
+	}
+
+	result := response.TxnGroups[0]
+	if result.FailureMessage != "" {
+		return result, &SimulationError{FailedAt: result.FailedAt, Message: result.FailureMessage}
+	}
+	return result, nil
+}
+
+func countAppCalls(group []types.Transaction) int {
+	app_calls := 0
+	for _, txn := range group {
+		if txn.Type == types.ApplicationCallTx {
+			app_calls++
+		}
+	}
+	return app_calls
+}
@@ -0,0 +1,375 @@
+package simulator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// value types of models.TealValue
+const (
+	tealBytesType = 1
+	tealUintType  = 2
+)
+
+// longest time a wait-for-block-after request blocks, algod gives up after a minute as well
+const MAX_BLOCK_WAIT = time.Minute
+
+// ServeHTTP answers the requests of the algod client, the api token is not checked
+func (l *Ledger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != "v2" {
+		writeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && matches(path, "v2", "transactions", "params"):
+		l.handleParams(w)
+	case r.Method == http.MethodGet && matches(path, "v2", "status"):
+		l.handleStatus(w)
+	case r.Method == http.MethodGet && matches(path, "v2", "status", "wait-for-block-after", "*"):
+		l.handleWaitForBlock(w, path[3])
+	case r.Method == http.MethodGet && matches(path, "v2", "blocks", "*"):
+		l.handleBlock(w, path[2])
+	case r.Method == http.MethodPost && matches(path, "v2", "transactions"):
+		l.handleSendTransactions(w, r)
+	case r.Method == http.MethodPost && matches(path, "v2", "transactions", "simulate"):
+		l.handleSimulate(w, r)
+	case r.Method == http.MethodPost && matches(path, "v2", "teal", "compile"):
+		l.handleCompile(w, r)
+	case r.Method == http.MethodGet && matches(path, "v2", "transactions", "pending", "*"):
+		l.handlePendingTransaction(w, path[3])
+	case r.Method == http.MethodGet && matches(path, "v2", "accounts", "*"):
+		l.handleAccount(w, path[2])
+	case r.Method == http.MethodGet && matches(path, "v2", "accounts", "*", "assets", "*"):
+		l.handleAccountAsset(w, path[2], path[4])
+	case r.Method == http.MethodGet && matches(path, "v2", "applications", "*"):
+		l.handleApplication(w, path[2])
+	case r.Method == http.MethodGet && matches(path, "v2", "assets", "*"):
+		l.handleAsset(w, path[2])
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+// matches compares the path with a pattern, "*" matches any segment
+func matches(path []string, pattern ...string) bool {
+	if len(path) != len(pattern) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Ledger) handleParams(w http.ResponseWriter) {
+	writeJSON(w, models.TransactionParametersResponse{
+		ConsensusVersion: CONSENSUS_VERSION,
+		Fee:              0,
+		GenesisHash:      l.GenesisHash(),
+		GenesisId:        GENESIS_ID,
+		LastRound:        l.Round(),
+		MinFee:           MIN_TXN_FEE,
+	})
+}
+
+func (l *Ledger) handleStatus(w http.ResponseWriter) {
+	writeJSON(w, models.NodeStatusResponse{
+		LastRound:            l.Round(),
+		LastVersion:          CONSENSUS_VERSION,
+		NextVersion:          CONSENSUS_VERSION,
+		NextVersionSupported: true,
+	})
+}
+
+func (l *Ledger) handleWaitForBlock(w http.ResponseWriter, round_param string) {
+	round, err := strconv.ParseUint(round_param, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid round "+round_param)
+		return
+	}
+	l.waitForRound(round+1, MAX_BLOCK_WAIT)
+	l.handleStatus(w)
+}
+
+func (l *Ledger) handleBlock(w http.ResponseWriter, round_param string) {
+	round, err := strconv.ParseUint(round_param, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid round "+round_param)
+		return
+	}
+	l.mu.Lock()
+	block, ok := l.blocks[round]
+	l.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("block %d not found", round))
+		return
+	}
+	writeMsgpack(w, models.BlockResponse{Block: block})
+}
+
+func (l *Ledger) handleSendTransactions(w http.ResponseWriter, r *http.Request) {
+	signed_txns, err := decodeSignedTxns(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	txids, err := l.SubmitGroup(signed_txns)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "TransactionPool.Remember: "+err.Error())
+		return
+	}
+	writeJSON(w, models.PostTransactionsResponse{Txid: txids[0]})
+}
+
+func (l *Ledger) handleSimulate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var request models.SimulateRequest
+	err = msgpack.Decode(body, &request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := models.SimulateResponse{Version: 2, LastRound: l.Round()}
+	for _, group := range request.TxnGroups {
+		result := l.SimulateGroup(group.Txns, request.AllowEmptySignatures, request.ExtraOpcodeBudget)
+		response.TxnGroups = append(response.TxnGroups, result)
+	}
+	writeJSON(w, response)
+}
+
+// handleCompile looks up the program registered for the source
+func (l *Ledger) handleCompile(w http.ResponseWriter, r *http.Request) {
+	source, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	l.mu.Lock()
+	program, found := l.programs[string(source)]
+	l.mu.Unlock()
+	if !found {
+		writeError(w, http.StatusBadRequest, "no program registered for the teal source")
+		return
+	}
+	writeJSON(w, models.CompileResponse{
+		Hash:   crypto.AddressFromProgram(program).String(),
+		Result: base64.StdEncoding.EncodeToString(program),
+	})
+}
+
+func (l *Ledger) handlePendingTransaction(w http.ResponseWriter, txid string) {
+	confirmed_txn, err := l.pendingTransaction(txid)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeMsgpack(w, confirmed_txn)
+}
+
+func (l *Ledger) handleAccount(w http.ResponseWriter, address_param string) {
+	address, err := types.DecodeAddress(address_param)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	account_info := models.Account{
+		Address: address.String(),
+		Round:   l.round,
+		Status:  "Offline",
+	}
+	if account, ok := l.state.accounts[address]; ok {
+		account_info.Amount = account.amount
+		account_info.AmountWithoutPendingRewards = account.amount
+		if !account.auth_addr.IsZero() {
+			account_info.AuthAddr = account.auth_addr.String()
+		}
+		asset_ids := make([]uint64, 0, len(account.assets))
+		for asset_id := range account.assets {
+			asset_ids = append(asset_ids, asset_id)
+		}
+		for _, asset_id := range sortUint64s(asset_ids) {
+			account_info.Assets = append(account_info.Assets, models.AssetHolding{AssetId: asset_id, Amount: account.assets[asset_id]})
+		}
+		account_info.TotalAssetsOptedIn = uint64(len(account.assets))
+	}
+	created_apps := l.state.createdApps(address)
+	app_ids := make([]uint64, 0, len(created_apps))
+	for app_id := range created_apps {
+		app_ids = append(app_ids, app_id)
+	}
+	for _, app_id := range sortUint64s(app_ids) {
+		created_app := created_apps[app_id]
+		account_info.CreatedApps = append(account_info.CreatedApps, created_app.model(app_id))
+		account_info.AppsTotalSchema.NumUint += created_app.global_schema.NumUint
+		account_info.AppsTotalSchema.NumByteSlice += created_app.global_schema.NumByteSlice
+		account_info.AppsTotalExtraPages += uint64(created_app.extra_pages)
+	}
+	account_info.TotalCreatedApps = uint64(len(created_apps))
+	writeJSON(w, account_info)
+}
+
+func (l *Ledger) handleAccountAsset(w http.ResponseWriter, address_param string, asset_param string) {
+	address, err := types.DecodeAddress(address_param)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	asset_id, err := strconv.ParseUint(asset_param, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid asset id "+asset_param)
+		return
+	}
+	amount, opted_in := l.AssetBalance(address, asset_id)
+	if !opted_in {
+		writeError(w, http.StatusNotFound, "account asset info not found")
+		return
+	}
+	writeJSON(w, models.AccountAssetResponse{
+		AssetHolding: models.AssetHolding{AssetId: asset_id, Amount: amount},
+		Round:        l.Round(),
+	})
+}
+
+func (l *Ledger) handleApplication(w http.ResponseWriter, app_param string) {
+	app_id, err := strconv.ParseUint(app_param, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid application id "+app_param)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	existing, ok := l.state.apps[app_id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "application does not exist")
+		return
+	}
+	writeJSON(w, existing.model(app_id))
+}
+
+func (l *Ledger) handleAsset(w http.ResponseWriter, asset_param string) {
+	asset_id, err := strconv.ParseUint(asset_param, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid asset id "+asset_param)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	existing, ok := l.state.assets[asset_id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "asset does not exist")
+		return
+	}
+	writeJSON(w, models.Asset{
+		Index: asset_id,
+		Params: models.AssetParams{
+			Creator:  existing.creator.String(),
+			Decimals: uint64(existing.params.Decimals),
+			Name:     existing.params.AssetName,
+			Total:    existing.params.Total,
+			UnitName: existing.params.UnitName,
+			Url:      existing.params.URL,
+		},
+	})
+}
+
+// model is the app as returned by algod, the global state is sorted by key
+func (a *app) model(appID uint64) models.Application {
+	params := models.ApplicationParams{
+		ApprovalProgram:   a.approval_program,
+		ClearStateProgram: a.clear_program,
+		Creator:           a.creator.String(),
+		ExtraProgramPages: uint64(a.extra_pages),
+		GlobalStateSchema: models.ApplicationStateSchema{NumUint: a.global_schema.NumUint, NumByteSlice: a.global_schema.NumByteSlice},
+		LocalStateSchema:  models.ApplicationStateSchema{NumUint: a.local_schema.NumUint, NumByteSlice: a.local_schema.NumByteSlice},
+	}
+	keys := make([]string, 0, len(a.global_state))
+	for key := range a.global_state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := a.global_state[key]
+		teal_value := models.TealValue{Type: tealUintType, Uint: value.uint}
+		if value.is_bytes {
+			teal_value = models.TealValue{Type: tealBytesType, Bytes: base64.StdEncoding.EncodeToString(value.bytes)}
+		}
+		params.GlobalState = append(params.GlobalState, models.TealKeyValue{
+			Key:   base64.StdEncoding.EncodeToString([]byte(key)),
+			Value: teal_value,
+		})
+	}
+	return models.Application{Id: appID, Params: params}
+}
+
+// decodeSignedTxns decodes the concatenated msgpack encoded transactions of a group
+func decodeSignedTxns(body io.Reader) ([]types.SignedTxn, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	var signed_txns []types.SignedTxn
+	for {
+		var signed_txn types.SignedTxn
+		err := decoder.Decode(&signed_txn)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding signed transaction %d: %v", len(signed_txns), err)
+		}
+		signed_txns = append(signed_txns, signed_txn)
+	}
+	if len(signed_txns) == 0 {
+		return nil, errors.New("no transactions in request body")
+	}
+	return signed_txns, nil
+}
+
+func sortUint64s(values []uint64) []uint64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(json.Encode(response))
+}
+
+func writeMsgpack(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/msgpack")
+	w.Write(msgpack.Encode(response))
+}
+
+// writeError answers like algod, the client reports the message of the body
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(json.Encode(map[string]string{"message": message}))
+}
@@ -0,0 +1,901 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+)
+
+// the contract mirrored here is payment/build_contracts/payment_approval.teal, generated by payment_contract.py.
+// Changes of the contract have to be made here as well, the scenario tests of the payment package run against this copy.
+
+// PROTOCOL_VERSION is signed as part of every state, close and withdraw message
+const PROTOCOL_VERSION = 1
+
+// HTLC_SIZE is the number of bytes of one encoded htlc: offerer | amount | hashlock | timelock
+const HTLC_SIZE = 49
+
+// STATE_ARGS are the abi arguments of a signed state followed by the signatures of alice and bob
+const STATE_ARGS = "byte[32],uint64,uint64,uint64,byte[],byte[64],byte[64]"
+
+var ABI_RETURN_PREFIX = []byte{0x15, 0x1f, 0x7c, 0x75}
+
+// opcode costs of the hashing and signature opcodes, they dominate the budget of the contract.
+// The remaining opcodes cost 1 each and are counted per branch of the program.
+const (
+	SHA256_COST             = 35
+	SHA3_256_COST           = 130
+	ED25519VERIFY_BARE_COST = 1900
+)
+
+// logicError aborts the approval program like a failing opcode, it is raised by panicking and recovered by run
+type logicError struct {
+	message   string
+	failed_at []uint64 // path to a failing inner transaction
+}
+
+// contractCall is one execution of the approval program of the payment channel contract
+type contractCall struct {
+	state   *ledgerState
+	group   *groupContext
+	index   int
+	txn     types.Transaction
+	app_id  uint64
+	app     *app
+	touched map[types.Address]bool
+
+	logs       [][]byte
+	inner_txns []models.PendingTransactionResponse
+}
+
+// innerTxn is an inner transaction built by the contract, its fee defaults to what the fee credit of the group leaves to pay
+type innerTxn struct {
+	txn        types.Transaction
+	fee_is_set bool
+}
+
+// applyAppCall creates or calls an app. Every app on the ledger runs the payment channel contract, whatever its approval program.
+func (s *ledgerState) applyAppCall(group *groupContext, index int, confirmed_txn models.PendingTransactionInfoResponse, touched map[types.Address]bool) (models.PendingTransactionInfoResponse, *evalError) {
+	txn := group.txns[index]
+	err := s.debit(txn.Sender, uint64(txn.Fee), 0)
+	if err != nil {
+		return confirmed_txn, &evalError{message: err.Error()}
+	}
+
+	app_id := uint64(txn.ApplicationID)
+	if app_id == 0 {
+		if len(txn.ApprovalProgram) == 0 || len(txn.ClearStateProgram) == 0 {
+			return confirmed_txn, &evalError{message: "app programs must not be empty"}
+		}
+		app_id = s.txn_counter
+		s.apps[app_id] = &app{
+			creator:          txn.Sender,
+			approval_program: txn.ApprovalProgram,
+			clear_program:    txn.ClearStateProgram,
+			global_schema:    txn.GlobalStateSchema,
+			local_schema:     txn.LocalStateSchema,
+			extra_pages:      txn.ExtraProgramPages,
+			global_state:     make(map[string]tealValue),
+		}
+		confirmed_txn.ApplicationIndex = app_id
+	}
+	called_app, ok := s.apps[app_id]
+	if !ok {
+		return confirmed_txn, &evalError{message: fmt.Sprintf("application %d does not exist", app_id)}
+	}
+	if txn.OnCompletion == types.ClearStateOC {
+		return confirmed_txn, &evalError{message: fmt.Sprintf("%s is not currently opted in to app %d", txn.Sender, app_id)}
+	}
+
+	call := &contractCall{state: s, group: group, index: index, txn: txn, app_id: app_id, app: called_app, touched: touched}
+	approved, eval_err := call.run()
+	confirmed_txn.Logs = call.logs
+	confirmed_txn.InnerTxns = call.inner_txns
+	if eval_err != nil {
+		return confirmed_txn, eval_err
+	}
+	if !approved {
+		return confirmed_txn, &evalError{message: "transaction rejected by ApprovalProgram"}
+	}
+	if txn.OnCompletion == types.DeleteApplicationOC {
+		delete(s.apps, app_id)
+	}
+
+	err = s.applyRekey(txn)
+	if err == nil {
+		err = s.checkMinBalances(touched)
+	}
+	if err != nil {
+		return confirmed_txn, &evalError{message: err.Error()}
+	}
+	return confirmed_txn, nil
+}
+
+// run executes the approval program and reports whether it approved the call
+func (c *contractCall) run() (approved bool, eval_err *evalError) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		logic_error, ok := recovered.(logicError)
+		if !ok {
+			panic(recovered)
+		}
+		approved = false
+		eval_err = &evalError{failed_at: logic_error.failed_at, message: "logic eval error: " + logic_error.message}
+	}()
+	return c.approvalProgram(), nil
+}
+
+func (c *contractCall) approvalProgram() bool {
+	c.ops(4)
+	if c.txn.ApplicationID == 0 {
+		return c.onCreate()
+	}
+	switch c.txn.OnCompletion {
+	case types.UpdateApplicationOC, types.CloseOutOC:
+		return false
+	case types.NoOpOC:
+		return c.onCall()
+	case types.DeleteApplicationOC:
+		return c.onDelete()
+	}
+	c.fail("err opcode executed")
+	return false
+}
+
+// onCall routes the call by its ARC-4 method selector
+func (c *contractCall) onCall() bool {
+	methods := []struct {
+		signature string
+		handler   func() bool
+	}{
+		{"fund(txn,address)uint64", c.onFund},
+		{"optInAsset()void", c.onOptInAsset},
+		{"deposit(txn," + STATE_ARGS + ")uint64", c.onDeposit},
+		{"withdraw(" + STATE_ARGS + ",uint64)uint64", c.onWithdraw},
+		{"acceptChannel(address)void", c.onAcceptChannel},
+		{"abortChannelOpen()void", c.onAbortChannelOpen},
+		{"increaseBudget(uint64)void", c.onIncreaseBudget},
+		{"initiateChannelClosing(" + STATE_ARGS + ")uint64", c.onInitiateChannelClosing},
+		{"raiseDispute(" + STATE_ARGS + ")void", c.onRaiseDispute},
+		{"settleHTLC(byte[],uint64,byte[])void", c.onSettleHTLC},
+		{"finalizeChannelClosing(byte[])void", c.onFinalizeChannelClosing},
+		{"cooperativeClose(byte[32],uint64,uint64,uint64,byte[64],byte[64])void", c.onCooperativeClose},
+	}
+	selector := c.arg(0)
+	for _, method := range methods {
+		c.ops(4)
+		if bytes.Equal(selector, methodSelector(method.signature)) {
+			return method.handler()
+		}
+	}
+	c.fail("err opcode executed")
+	return false
+}
+
+func (c *contractCall) onCreate() bool {
+	c.ops(40)
+	c.assert(len(c.txn.ApplicationArgs) == 7, "create takes 6 arguments")
+	c.assert(bytes.Equal(c.arg(0), methodSelector("create(address,uint64,uint64,uint64,address,byte[32])void")), "create selector")
+	c.assert(len(c.arg(5)) == 32, "alice_signing_key is 32 bytes")
+	c.assert(len(c.arg(6)) == 32, "genesis_hash is 32 bytes")
+	c.putBytes("alice_address", c.txn.Sender[:])
+	c.putBytes("bob_address", c.arg(1))
+	c.putUint("penalty_reserve", c.btoi(c.arg(2)))
+	c.putUint("dispute_window", c.btoi(c.arg(3)))
+	c.putUint("asset_id", c.btoi(c.arg(4)))
+	c.putBytes("alice_signing_key", c.arg(5))
+	c.putBytes("genesis_hash", c.arg(6))
+	c.putBytes("latest_htlcs_hash", c.sha3(nil))
+	if len(c.group.txns) > 1 {
+		c.onCreateFunding()
+	}
+	return true
+}
+
+// onCreateFunding funds the channel in the group creating it, the funding transactions pay to the predicted app address
+func (c *contractCall) onCreateFunding() {
+	c.ops(30)
+	prev_txn := c.gtxn(c.sub(uint64(c.index), 1))
+	app_address := c.appAddress()
+	if c.globalUint("asset_id") == 0 {
+		// [algo funding, create]
+		min_amount := c.add(c.globalUint("penalty_reserve"), MIN_TXN_FEE)
+		c.assert(prev_txn.Sender == c.txn.Sender &&
+			prev_txn.Type == types.PaymentTx &&
+			uint64(prev_txn.Amount) > min_amount &&
+			prev_txn.Receiver == app_address, "algo funding of the create call")
+		c.putUint("latest_alice_balance", uint64(prev_txn.Amount))
+		c.putUint("total_deposit", uint64(prev_txn.Amount))
+		return
+	}
+
+	// [algo reserve, create, asset funding]
+	c.assert(prev_txn.Sender == c.txn.Sender &&
+		prev_txn.Type == types.PaymentTx &&
+		prev_txn.Receiver == app_address, "algo reserve of the create call")
+	c.submitInner(c.assetTransfer(app_address, 0))
+	next_txn := c.gtxn(c.add(uint64(c.index), 1))
+	asset_id := c.globalUint("asset_id")
+	penalty_reserve := c.globalUint("penalty_reserve")
+	c.assert(next_txn.Sender == c.txn.Sender &&
+		next_txn.Type == types.AssetTransferTx &&
+		uint64(next_txn.XferAsset) == asset_id &&
+		next_txn.AssetAmount > penalty_reserve &&
+		next_txn.AssetReceiver == app_address, "asset funding of the create call")
+	c.putUint("latest_alice_balance", next_txn.AssetAmount)
+	c.putUint("total_deposit", next_txn.AssetAmount)
+}
+
+func (c *contractCall) onFund() bool {
+	c.ops(30)
+	c.assert(c.globalUint("total_deposit") == 0, "channel is not funded")
+	c.assert(len(c.arg(1)) == 32, "alice_signing_key is 32 bytes")
+	c.putBytes("alice_signing_key", c.arg(1))
+	funding_txn := c.gtxn(c.sub(uint64(c.index), 1))
+	alice_address := c.globalAddress("alice_address")
+	app_address := c.appAddress()
+	if c.globalUint("asset_id") == 0 {
+		min_amount := c.add(c.globalUint("penalty_reserve"), MIN_TXN_FEE)
+		c.assert(funding_txn.Sender == c.txn.Sender &&
+			funding_txn.Sender == alice_address &&
+			funding_txn.Type == types.PaymentTx &&
+			uint64(funding_txn.Amount) > min_amount &&
+			funding_txn.Receiver == app_address, "algo funding")
+		c.putUint("latest_alice_balance", uint64(funding_txn.Amount))
+		c.putUint("total_deposit", uint64(funding_txn.Amount))
+	} else {
+		asset_id := c.globalUint("asset_id")
+		penalty_reserve := c.globalUint("penalty_reserve")
+		c.assert(funding_txn.Sender == c.txn.Sender &&
+			funding_txn.Sender == alice_address &&
+			funding_txn.Type == types.AssetTransferTx &&
+			uint64(funding_txn.XferAsset) == asset_id &&
+			funding_txn.AssetAmount > penalty_reserve &&
+			funding_txn.AssetReceiver == app_address, "asset funding")
+		c.putUint("latest_alice_balance", funding_txn.AssetAmount)
+		c.putUint("total_deposit", funding_txn.AssetAmount)
+	}
+	c.logReturn(c.globalUint("total_deposit"))
+	return true
+}
+
+func (c *contractCall) onOptInAsset() bool {
+	c.ops(15)
+	alice_address := c.globalAddress("alice_address")
+	asset_id := c.globalUint("asset_id")
+	c.assert(c.txn.Sender == alice_address && asset_id != 0, "opt-in by alice into the asset of the channel")
+	c.submitInner(c.assetTransfer(c.appAddress(), 0))
+	return true
+}
+
+func (c *contractCall) onDeposit() bool {
+	c.ops(60)
+	c.assertSenderIsParty()
+	c.assert(c.globalUint("timeout") == 0, "channel is not closing")
+	deposit_txn := c.gtxn(c.sub(uint64(c.index), 1))
+	c.assert(deposit_txn.Sender == c.txn.Sender, "deposit is sent by the caller")
+	app_address := c.appAddress()
+	var deposit_amount uint64
+	if c.globalUint("asset_id") == 0 {
+		c.assert(deposit_txn.Type == types.PaymentTx && deposit_txn.Receiver == app_address, "algo deposit")
+		deposit_amount = uint64(deposit_txn.Amount)
+	} else {
+		asset_id := c.globalUint("asset_id")
+		c.assert(deposit_txn.Type == types.AssetTransferTx &&
+			uint64(deposit_txn.XferAsset) == asset_id &&
+			deposit_txn.AssetReceiver == app_address, "asset deposit")
+		deposit_amount = deposit_txn.AssetAmount
+	}
+
+	htlcs := c.abiBytes(c.arg(5))
+	state_hash := c.signedMessageHash("STATE_UPDATE", c.arg(2), c.arg(3), c.arg(4), htlcs)
+	valid := c.verifySignedState(state_hash, c.arg(6), c.arg(7))
+	// states signed before the deposit no longer add up to the total deposit
+	adds_up := c.stateTotal(htlcs) == c.add(c.globalUint("total_deposit"), deposit_amount)
+	newer := c.globalUint("latest_timestamp") < c.btoi(c.arg(4))
+	c.assert(valid && adds_up && newer, "deposit state")
+
+	c.putUint("total_deposit", c.add(c.globalUint("total_deposit"), deposit_amount))
+	c.putLatestState()
+	c.logReturn(c.globalUint("total_deposit"))
+	return true
+}
+
+func (c *contractCall) onWithdraw() bool {
+	c.ops(70)
+	c.assertSenderIsParty()
+	c.assert(c.globalUint("timeout") == 0, "channel is not closing")
+
+	htlcs := c.abiBytes(c.arg(5))
+	withdraw_hash := c.signedMessageHash("WITHDRAW", c.arg(2), c.arg(3), c.arg(4), htlcs, c.arg(8), c.txn.Sender[:])
+	valid := c.verifySignedState(withdraw_hash, c.arg(6), c.arg(7))
+	// states signed before the withdrawal no longer add up to the total deposit
+	withdraw_amount := c.btoi(c.arg(8))
+	adds_up := c.add(c.stateTotal(htlcs), withdraw_amount) == c.globalUint("total_deposit")
+	newer := c.globalUint("latest_timestamp") < c.btoi(c.arg(4))
+	c.assert(valid && adds_up && newer, "withdraw state")
+
+	c.putUint("total_deposit", c.sub(c.globalUint("total_deposit"), withdraw_amount))
+	c.putLatestState()
+	// the inner transaction fee is pooled from the app call
+	var payout innerTxn
+	if c.globalUint("asset_id") == 0 {
+		payout = c.payment(c.txn.Sender, withdraw_amount, types.ZeroAddress)
+	} else {
+		payout = c.assetTransfer(c.txn.Sender, withdraw_amount)
+	}
+	payout.txn.Fee = 0
+	payout.fee_is_set = true
+	c.submitInner(payout)
+	c.logReturn(c.globalUint("total_deposit"))
+	return true
+}
+
+func (c *contractCall) onAcceptChannel() bool {
+	c.ops(20)
+	c.assert(c.txn.Sender == c.globalAddress("bob_address"), "sender is bob")
+	c.assert(c.globalUint("timeout") == 0, "channel is not closing")
+	c.assert(c.globalUint("total_deposit") > 0, "channel is funded")
+	c.assert(c.globalUint("bob_accepted") == 0, "bob has not accepted yet")
+	c.assert(len(c.arg(1)) == 32, "bob_signing_key is 32 bytes")
+	c.putBytes("bob_signing_key", c.arg(1))
+	c.putUint("bob_accepted", 1)
+	return true
+}
+
+func (c *contractCall) onAbortChannelOpen() bool {
+	c.ops(30)
+	alice_address := c.globalAddress("alice_address")
+	c.assert(c.txn.Sender == alice_address, "sender is alice")
+	c.assert(c.globalUint("bob_accepted") == 0, "bob has not accepted")
+	c.assert(c.globalUint("timeout") == 0, "channel is not closing")
+	c.putUint("total_deposit", 0)
+	c.putUint("latest_alice_balance", 0)
+	if c.globalUint("asset_id") == 0 {
+		c.submitInner(c.payment(alice_address, 0, alice_address))
+	} else {
+		// return the asset and opt out of it, then return the remaining algos
+		asset_refund := c.assetTransfer(alice_address, 0)
+		asset_refund.txn.AssetCloseTo = alice_address
+		c.submitInner(asset_refund, c.payment(alice_address, 0, alice_address))
+	}
+	return true
+}
+
+func (c *contractCall) onIncreaseBudget() bool {
+	c.ops(2)
+	return true
+}
+
+func (c *contractCall) onInitiateChannelClosing() bool {
+	c.ops(60)
+	c.assertSenderIsParty()
+	c.assert(c.globalUint("timeout") == 0, "channel is not closing")
+	htlcs := c.abiBytes(c.arg(5))
+	state_hash := c.signedMessageHash("STATE_UPDATE", c.arg(2), c.arg(3), c.arg(4), htlcs)
+	valid := c.verifySignedState(state_hash, c.arg(6), c.arg(7))
+	adds_up := c.stateTotal(htlcs) == c.globalUint("total_deposit")
+	not_older := c.globalUint("latest_timestamp") <= c.btoi(c.arg(4))
+	if valid && adds_up && not_older {
+		if c.txn.Sender == c.globalAddress("alice_address") {
+			c.putBytes("closing_initiator", []byte("alice"))
+		} else {
+			c.putBytes("closing_initiator", []byte("bob"))
+		}
+		c.putUint("timeout", c.add(c.group.round, c.globalUint("dispute_window")))
+		c.putLatestState()
+		c.putBytes("latest_htlcs_hash", c.sha3(htlcs))
+		c.putUint("settled_htlcs", 0)
+	}
+	c.logReturn(c.globalUint("timeout"))
+	return true
+}
+
+// onRaiseDispute can be called by anyone, so that a third party can raise a dispute for an offline party
+func (c *contractCall) onRaiseDispute() bool {
+	c.ops(60)
+	htlcs := c.abiBytes(c.arg(5))
+	state_hash := c.signedMessageHash("STATE_UPDATE", c.arg(2), c.arg(3), c.arg(4), htlcs)
+	valid := c.verifySignedState(state_hash, c.arg(6), c.arg(7))
+	adds_up := c.stateTotal(htlcs) == c.globalUint("total_deposit")
+	newer := c.globalUint("latest_timestamp") < c.btoi(c.arg(4))
+	if valid && adds_up && newer {
+		c.putLatestState()
+		c.putBytes("latest_htlcs_hash", c.sha3(htlcs))
+		c.putUint("settled_htlcs", 0)
+
+		// punish the closing initiator
+		if bytes.Equal(c.globalBytes("closing_initiator"), []byte("alice")) {
+			return c.rebalance("latest_alice_balance", "latest_bob_balance", c.globalUint("penalty_reserve"))
+		}
+		return c.rebalance("latest_bob_balance", "latest_alice_balance", c.globalUint("penalty_reserve"))
+	}
+	return true
+}
+
+// rebalance moves amount from one balance of the latest state to the other
+func (c *contractCall) rebalance(senderBalance string, recipientBalance string, amount uint64) bool {
+	c.ops(12)
+	c.putUint(senderBalance, c.sub(c.globalUint(senderBalance), amount))
+	c.putUint(recipientBalance, c.add(c.globalUint(recipientBalance), amount))
+	return true
+}
+
+// onSettleHTLC can be called by anyone who knows the preimage of a pending htlc of the closing state before it expires
+func (c *contractCall) onSettleHTLC() bool {
+	c.ops(50)
+	htlcs := c.abiBytes(c.arg(1))
+	htlc_index := c.btoi(c.arg(2))
+	offset := c.mul(htlc_index, HTLC_SIZE)
+	preimage := c.abiBytes(c.arg(3))
+
+	closing := c.globalUint("timeout") > 0
+	known_htlcs := bytes.Equal(c.sha3(htlcs), c.globalBytes("latest_htlcs_hash"))
+	in_range := offset < uint64(len(htlcs))
+	not_settled := c.getBit(c.globalUint("settled_htlcs"), htlc_index) == 0
+	preimage_matches := bytes.Equal(c.sha256(preimage), c.extract(htlcs, c.add(offset, 9), 32))
+	not_expired := c.group.round <= c.extractUint64(htlcs, c.add(offset, 41))
+	c.assert(closing && known_htlcs && in_range && not_settled && preimage_matches && not_expired, "settle htlc")
+	c.putUint("settled_htlcs", c.setBit(c.globalUint("settled_htlcs"), htlc_index))
+
+	// pay the receiver, i.e. the party that did not offer the htlc
+	amount := c.extractUint64(htlcs, c.add(offset, 1))
+	if c.extract(htlcs, offset, 1)[0] == 0 {
+		c.putUint("latest_bob_balance", c.add(c.globalUint("latest_bob_balance"), amount))
+	} else {
+		c.putUint("latest_alice_balance", c.add(c.globalUint("latest_alice_balance"), amount))
+	}
+	return true
+}
+
+// onFinalizeChannelClosing can be called by anyone, it pays out the channel once the dispute window passed
+func (c *contractCall) onFinalizeChannelClosing() bool {
+	c.ops(10)
+	if c.globalUint("timeout") > 0 && c.group.round > c.globalUint("timeout") {
+		htlcs := c.abiBytes(c.arg(1))
+		c.assert(bytes.Equal(c.sha3(htlcs), c.globalBytes("latest_htlcs_hash")), "htlcs of the closing state")
+		c.refundExpiredHTLCs(htlcs)
+		c.closeAccountTo(c.globalAddress("alice_address"))
+	}
+	return true
+}
+
+// refundExpiredHTLCs gives the amounts of the htlcs which were not settled back to their offerer, once they expired
+func (c *contractCall) refundExpiredHTLCs(htlcs []byte) {
+	for i := uint64(0); c.mul(i, HTLC_SIZE) < uint64(len(htlcs)); i++ {
+		c.ops(20)
+		if c.getBit(c.globalUint("settled_htlcs"), i) != 0 {
+			continue
+		}
+		offset := i * HTLC_SIZE
+		c.assert(c.group.round > c.extractUint64(htlcs, offset+41), "htlc expired")
+		amount := c.extractUint64(htlcs, offset+1)
+		if htlcs[offset] == 0 {
+			c.putUint("latest_alice_balance", c.add(c.globalUint("latest_alice_balance"), amount))
+		} else {
+			c.putUint("latest_bob_balance", c.add(c.globalUint("latest_bob_balance"), amount))
+		}
+	}
+}
+
+func (c *contractCall) onCooperativeClose() bool {
+	c.ops(60)
+	c.assertSenderIsParty()
+	c.assert(c.globalUint("timeout") == 0, "channel is not closing")
+	close_hash := c.signedMessageHash("CLOSE_CHANNEL", c.arg(2), c.arg(3), c.arg(4))
+	valid := c.verifySignedState(close_hash, c.arg(5), c.arg(6))
+	adds_up := c.add(c.btoi(c.arg(2)), c.btoi(c.arg(3))) == c.globalUint("total_deposit")
+	if valid && adds_up {
+		c.putUint("latest_timestamp", c.btoi(c.arg(4)))
+		c.putUint("latest_alice_balance", c.btoi(c.arg(2)))
+		c.putUint("latest_bob_balance", c.btoi(c.arg(3)))
+		c.closeAccountTo(c.globalAddress("alice_address"))
+	}
+	return true
+}
+
+// onDelete lets alice delete the app once the app account was closed out
+func (c *contractCall) onDelete() bool {
+	c.ops(8)
+	return c.txn.Sender == c.globalAddress("alice_address") && c.balance(c.appAddress()) == 0
+}
+
+// closeAccountTo pays out the latest balances and closes the app account to beneficiary
+func (c *contractCall) closeAccountTo(beneficiary types.Address) {
+	c.ops(30)
+	alice_address := c.globalAddress("alice_address")
+	bob_address := c.globalAddress("bob_address")
+	if c.globalUint("asset_id") == 0 {
+		c.submitInner(
+			c.payment(bob_address, c.sub(c.globalUint("latest_bob_balance"), MIN_TXN_FEE), types.ZeroAddress),
+			c.payment(alice_address, c.sub(c.globalUint("latest_alice_balance"), MIN_TXN_FEE), beneficiary),
+		)
+		return
+	}
+	// pay out the asset balances, opt out of the asset and return the algos that paid for the minimum balance and fees
+	alice_payout := c.assetTransfer(alice_address, c.globalUint("latest_alice_balance"))
+	alice_payout.txn.AssetCloseTo = alice_address
+	c.submitInner(
+		c.assetTransfer(bob_address, c.globalUint("latest_bob_balance")),
+		alice_payout,
+		c.payment(beneficiary, 0, beneficiary),
+	)
+}
+
+// putLatestState stores the balances and timestamp of the state in the arguments as the latest state
+func (c *contractCall) putLatestState() {
+	c.putUint("latest_timestamp", c.btoi(c.arg(4)))
+	c.putUint("latest_alice_balance", c.btoi(c.arg(2)))
+	c.putUint("latest_bob_balance", c.btoi(c.arg(3)))
+}
+
+func (c *contractCall) assertSenderIsParty() {
+	alice_address := c.globalAddress("alice_address")
+	bob_address := c.globalAddress("bob_address")
+	c.assert(c.txn.Sender == alice_address || c.txn.Sender == bob_address, "sender is alice or bob")
+}
+
+// signedMessageHash hashes a message signed by alice and bob:
+// domain | itob(PROTOCOL_VERSION) | genesis_hash | "," app_id | "," asset_id [| "," field]... | "END_" domain
+func (c *contractCall) signedMessageHash(domain string, fields ...[]byte) []byte {
+	c.ops(10 + 2*len(fields))
+	message := []byte(domain)
+	message = append(message, itob(PROTOCOL_VERSION)...)
+	message = append(message, c.globalBytes("genesis_hash")...)
+	message = append(message, ","...)
+	message = append(message, itob(c.app_id)...)
+	message = append(message, ","...)
+	message = append(message, itob(c.globalUint("asset_id"))...)
+	for _, field := range fields {
+		message = append(message, ","...)
+		message = append(message, field...)
+	}
+	message = append(message, "END_"+domain...)
+	return c.sha3(message)
+}
+
+// verifySignedState checks the network of the state in the arguments and the signatures of alice and bob.
+// Like the contract it verifies both signatures even if the first one fails.
+func (c *contractCall) verifySignedState(hash []byte, aliceSignature []byte, bobSignature []byte) bool {
+	same_network := bytes.Equal(c.arg(1), c.globalBytes("genesis_hash"))
+	alice_signed := c.ed25519VerifyBare(hash, aliceSignature, c.globalBytes("alice_signing_key"))
+	bob_signed := c.ed25519VerifyBare(hash, bobSignature, c.globalBytes("bob_signing_key"))
+	return same_network && alice_signed && bob_signed
+}
+
+// stateTotal sums the balances and the amounts of the pending htlcs of the state in the arguments
+func (c *contractCall) stateTotal(htlcs []byte) uint64 {
+	return c.add(c.add(c.btoi(c.arg(2)), c.btoi(c.arg(3))), c.sumHTLCAmounts(htlcs))
+}
+
+func (c *contractCall) sumHTLCAmounts(htlcs []byte) uint64 {
+	c.ops(8)
+	c.assert(len(htlcs)%HTLC_SIZE == 0, "htlcs are a multiple of HTLC_SIZE")
+	total := uint64(0)
+	for offset := 0; offset < len(htlcs); offset += HTLC_SIZE {
+		c.ops(12)
+		total = c.add(total, c.extractUint64(htlcs, uint64(offset+1)))
+	}
+	return total
+}
+
+// payment is an inner payment from the app account, which closes it to closeTo unless that is zero
+func (c *contractCall) payment(receiver types.Address, amount uint64, closeTo types.Address) innerTxn {
+	return innerTxn{txn: types.Transaction{
+		Type: types.PaymentTx,
+		PaymentTxnFields: types.PaymentTxnFields{
+			Receiver:         receiver,
+			Amount:           types.MicroAlgos(amount),
+			CloseRemainderTo: closeTo,
+		},
+	}}
+}
+
+// assetTransfer is an inner transfer of the asset of the channel from the app account
+func (c *contractCall) assetTransfer(receiver types.Address, amount uint64) innerTxn {
+	return innerTxn{txn: types.Transaction{
+		Type: types.AssetTransferTx,
+		AssetTransferTxnFields: types.AssetTransferTxnFields{
+			XferAsset:     types.AssetIndex(c.globalUint("asset_id")),
+			AssetAmount:   amount,
+			AssetReceiver: receiver,
+		},
+	}}
+}
+
+// submitInner executes the inner transactions as one group sent by the app account. Fees that are not set
+// are paid from the fee credit of the group as far as it goes, the app account pays the rest.
+func (c *contractCall) submitInner(inner_txns ...innerTxn) {
+	c.ops(5 * len(inner_txns))
+	app_address := c.appAddress()
+	for _, inner := range inner_txns {
+		txn := inner.txn
+		txn.Sender = app_address
+		txn.FirstValid = types.Round(c.group.round)
+		txn.LastValid = types.Round(c.group.round + MAX_TXN_LIFE)
+		txn.GenesisHash = c.txn.GenesisHash
+
+		if !inner.fee_is_set {
+			credit := c.group.fee_credit
+			if credit > MIN_TXN_FEE {
+				credit = MIN_TXN_FEE
+			}
+			txn.Fee = types.MicroAlgos(MIN_TXN_FEE - credit)
+		}
+		if uint64(txn.Fee) >= MIN_TXN_FEE {
+			c.group.fee_credit += uint64(txn.Fee) - MIN_TXN_FEE
+		} else if c.group.fee_credit >= MIN_TXN_FEE-uint64(txn.Fee) {
+			c.group.fee_credit -= MIN_TXN_FEE - uint64(txn.Fee)
+		} else {
+			c.failInner("fee too small")
+		}
+
+		for _, address := range []types.Address{txn.Receiver, txn.CloseRemainderTo, txn.AssetReceiver, txn.AssetCloseTo} {
+			if !address.IsZero() && !c.isAvailable(address) {
+				c.failInner("unavailable Account %s", address)
+			}
+		}
+		if txn.Type == types.AssetTransferTx && !c.isAvailableAsset(uint64(txn.XferAsset)) {
+			c.failInner("unavailable Asset %d", txn.XferAsset)
+		}
+
+		c.state.txn_counter++
+		var err error
+		switch txn.Type {
+		case types.PaymentTx:
+			err = c.state.applyPayment(txn, c.touched)
+		case types.AssetTransferTx:
+			err = c.state.applyAssetTransfer(txn, c.touched)
+		}
+		if err != nil {
+			c.failInner("%v", err)
+		}
+		c.inner_txns = append(c.inner_txns, models.PendingTransactionResponse{
+			Transaction: types.SignedTxn{Txn: txn},
+		})
+	}
+}
+
+// isAvailable reports whether the app call may send funds to the address, teal v7 makes only the sender,
+// the foreign accounts and the app accounts of the current and foreign apps available
+func (c *contractCall) isAvailable(address types.Address) bool {
+	if address == c.txn.Sender || address == c.appAddress() {
+		return true
+	}
+	for _, account := range c.txn.Accounts {
+		if account == address {
+			return true
+		}
+	}
+	for _, foreign_app := range c.txn.ForeignApps {
+		if crypto.GetApplicationAddress(uint64(foreign_app)) == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *contractCall) isAvailableAsset(assetID uint64) bool {
+	for _, foreign_asset := range c.txn.ForeignAssets {
+		if uint64(foreign_asset) == assetID {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *contractCall) fail(format string, args ...interface{}) {
+	panic(logicError{message: fmt.Sprintf(format, args...)})
+}
+
+// failInner fails the inner transaction about to be executed, the ones executed before it are part of inner_txns
+func (c *contractCall) failInner(format string, args ...interface{}) {
+	panic(logicError{message: fmt.Sprintf(format, args...), failed_at: []uint64{uint64(len(c.inner_txns))}})
+}
+
+func (c *contractCall) assert(condition bool, what string) {
+	c.ops(1)
+	if !condition {
+		c.fail("assert failed: %s", what)
+	}
+}
+
+// ops consumes the budget of amount opcodes from the pooled budget of the group
+func (c *contractCall) ops(amount int) {
+	c.group.budget_consumed += uint64(amount)
+	if c.group.budget_consumed > c.group.budget_added {
+		c.fail("dynamic cost budget exceeded, executing %d opcodes with a budget of %d", c.group.budget_consumed, c.group.budget_added)
+	}
+}
+
+func (c *contractCall) arg(index int) []byte {
+	if index >= len(c.txn.ApplicationArgs) {
+		c.fail("invalid ApplicationArgs index %d", index)
+	}
+	return c.txn.ApplicationArgs[index]
+}
+
+// gtxn returns the transaction at index of the group
+func (c *contractCall) gtxn(index uint64) types.Transaction {
+	if index >= uint64(len(c.group.txns)) {
+		c.fail("gtxn lookup %d in a group of %d", index, len(c.group.txns))
+	}
+	return c.group.txns[index]
+}
+
+func (c *contractCall) appAddress() types.Address {
+	return crypto.GetApplicationAddress(c.app_id)
+}
+
+func (c *contractCall) balance(address types.Address) uint64 {
+	if account, ok := c.state.accounts[address]; ok {
+		return account.amount
+	}
+	return 0
+}
+
+// globalUint reads an uint of the global state, keys which were never written read as 0
+func (c *contractCall) globalUint(key string) uint64 {
+	value, ok := c.app.global_state[key]
+	if ok && value.is_bytes {
+		c.fail("global %s holds bytes, expected an uint", key)
+	}
+	return value.uint
+}
+
+// globalBytes reads bytes of the global state, keys which were never written read as the uint 0 and fail
+func (c *contractCall) globalBytes(key string) []byte {
+	value, ok := c.app.global_state[key]
+	if !ok || !value.is_bytes {
+		c.fail("global %s holds an uint, expected bytes", key)
+	}
+	return value.bytes
+}
+
+func (c *contractCall) globalAddress(key string) types.Address {
+	value := c.globalBytes(key)
+	var address types.Address
+	if len(value) != len(address) {
+		return address
+	}
+	copy(address[:], value)
+	return address
+}
+
+func (c *contractCall) putUint(key string, value uint64) {
+	c.put(key, tealValue{uint: value})
+}
+
+func (c *contractCall) putBytes(key string, value []byte) {
+	c.put(key, tealValue{is_bytes: true, bytes: append([]byte{}, value...)})
+}
+
+// put writes the global state, which must stay within the schema of the app
+func (c *contractCall) put(key string, value tealValue) {
+	c.app.global_state[key] = value
+	uints, byte_slices := uint64(0), uint64(0)
+	for _, stored := range c.app.global_state {
+		if stored.is_bytes {
+			byte_slices++
+		} else {
+			uints++
+		}
+	}
+	if uints > c.app.global_schema.NumUint {
+		c.fail("store integer count %d exceeds schema integer count %d", uints, c.app.global_schema.NumUint)
+	}
+	if byte_slices > c.app.global_schema.NumByteSlice {
+		c.fail("store bytes count %d exceeds schema bytes count %d", byte_slices, c.app.global_schema.NumByteSlice)
+	}
+}
+
+func (c *contractCall) logReturn(value uint64) {
+	c.ops(4)
+	c.logs = append(c.logs, append(append([]byte{}, ABI_RETURN_PREFIX...), itob(value)...))
+}
+
+func (c *contractCall) btoi(value []byte) uint64 {
+	if len(value) > 8 {
+		c.fail("btoi arg too long, got %d bytes", len(value))
+	}
+	result := uint64(0)
+	for _, b := range value {
+		result = result<<8 | uint64(b)
+	}
+	return result
+}
+
+func (c *contractCall) add(a uint64, b uint64) uint64 {
+	if a+b < a {
+		c.fail("+ overflowed")
+	}
+	return a + b
+}
+
+func (c *contractCall) sub(a uint64, b uint64) uint64 {
+	if b > a {
+		c.fail("- would result negative")
+	}
+	return a - b
+}
+
+func (c *contractCall) mul(a uint64, b uint64) uint64 {
+	if a != 0 && (a*b)/a != b {
+		c.fail("* overflowed")
+	}
+	return a * b
+}
+
+// abiBytes strips the length prefix of a byte[] argument
+func (c *contractCall) abiBytes(arg []byte) []byte {
+	return c.extract(arg, 2, uint64(len(arg))-2)
+}
+
+func (c *contractCall) extract(value []byte, start uint64, length uint64) []byte {
+	if start > uint64(len(value)) || length > uint64(len(value))-start {
+		c.fail("extraction out of range, %d bytes from %d of %d", length, start, len(value))
+	}
+	return value[start : start+length]
+}
+
+func (c *contractCall) extractUint64(value []byte, start uint64) uint64 {
+	return binary.BigEndian.Uint64(c.extract(value, start, 8))
+}
+
+func (c *contractCall) getBit(value uint64, bit uint64) uint64 {
+	if bit >= 64 {
+		c.fail("getbit index %d beyond 64 bits", bit)
+	}
+	return value >> bit & 1
+}
+
+func (c *contractCall) setBit(value uint64, bit uint64) uint64 {
+	if bit >= 64 {
+		c.fail("setbit index %d beyond 64 bits", bit)
+	}
+	return value | 1<<bit
+}
+
+func (c *contractCall) sha3(data []byte) []byte {
+	c.ops(SHA3_256_COST)
+	hash := sha3.Sum256(data)
+	return hash[:]
+}
+
+func (c *contractCall) sha256(data []byte) []byte {
+	c.ops(SHA256_COST)
+	hash := sha256.Sum256(data)
+	return hash[:]
+}
+
+func (c *contractCall) ed25519VerifyBare(data []byte, signature []byte, publicKey []byte) bool {
+	c.ops(ED25519VERIFY_BARE_COST)
+	if len(publicKey) != ed25519.PublicKeySize {
+		c.fail("invalid public key")
+	}
+	if len(signature) != ed25519.SignatureSize {
+		c.fail("invalid signature")
+	}
+	return ed25519.Verify(publicKey, data, signature)
+}
+
+// methodSelector is the ARC-4 selector of a method signature
+func methodSelector(signature string) []byte {
+	hash := sha512.Sum512_256([]byte(signature))
+	return hash[:4]
+}
+
+func itob(value uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, value)
+}
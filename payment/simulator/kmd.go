@@ -0,0 +1,328 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+const KMD_DRIVER_NAME = "sqlite"
+
+// KMD is an in-memory fake of the key management daemon, it serves the kmd endpoints the node uses
+// to the kmd client of the SDK:
+//
+//	server := httptest.NewServer(simulator.NewKMD())
+//	kmd_client, err := kmd.MakeClient(server.URL, "")
+//
+// Wallet handles do not expire and the api token is not checked.
+type KMD struct {
+	mu            sync.Mutex
+	wallets       []*kmdWallet
+	handles       map[string]*kmdWallet // by wallet handle token
+	exported_keys int
+}
+
+type kmdWallet struct {
+	id       string
+	name     string
+	password string
+	keys     []ed25519.PrivateKey // in the order they were added
+}
+
+// NewKMD returns a kmd without any wallets
+func NewKMD() *KMD {
+	return &KMD{handles: make(map[string]*kmdWallet)}
+}
+
+// CreateWallet adds an empty wallet and returns its id
+func (k *KMD) CreateWallet(name string, password string) string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	wallet := &kmdWallet{id: randomHex(16), name: name, password: password}
+	k.wallets = append(k.wallets, wallet)
+	return wallet.id
+}
+
+// ImportKey adds the key of the account to the wallet with the given id
+func (k *KMD) ImportKey(walletID string, account crypto.Account) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, wallet := range k.wallets {
+		if wallet.id == walletID {
+			wallet.addKey(account.PrivateKey)
+			return true
+		}
+	}
+	return false
+}
+
+func (w *kmdWallet) addKey(privateKey ed25519.PrivateKey) types.Address {
+	account, _ := crypto.AccountFromPrivateKey(privateKey)
+	if w.key(account.Address) == nil {
+		w.keys = append(w.keys, privateKey)
+	}
+	return account.Address
+}
+
+func (w *kmdWallet) key(address types.Address) ed25519.PrivateKey {
+	for _, private_key := range w.keys {
+		if bytes.Equal(private_key.Public().(ed25519.PublicKey), address[:]) {
+			return private_key
+		}
+	}
+	return nil
+}
+
+func (w *kmdWallet) model() kmd.APIV1Wallet {
+	return kmd.APIV1Wallet{
+		ID:                    w.id,
+		Name:                  w.name,
+		DriverName:            KMD_DRIVER_NAME,
+		DriverVersion:         1,
+		SupportsMnemonicUX:    false,
+		SupportedTransactions: []types.TxType{types.PaymentTx, types.KeyRegistrationTx, types.AssetConfigTx, types.AssetTransferTx, types.AssetFreezeTx, types.ApplicationCallTx},
+	}
+}
+
+// ServeHTTP answers the requests of the kmd client, which sends its parameters as json body even for GET requests
+func (k *KMD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && path == "versions":
+		writeJSON(w, kmd.VersionsResponse{Versions: []string{"v1"}})
+	case r.Method == http.MethodGet && path == "v1/wallets":
+		k.handleListWallets(w)
+	case r.Method == http.MethodPost && path == "v1/wallet/init":
+		var request kmd.InitWalletHandleRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleInitWalletHandle(w, request)
+		}
+	case r.Method == http.MethodPost && path == "v1/wallet/release":
+		var request kmd.ReleaseWalletHandleRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleReleaseWalletHandle(w, request)
+		}
+	case r.Method == http.MethodPost && path == "v1/key/list":
+		var request kmd.ListKeysRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleListKeys(w, request)
+		}
+	case r.Method == http.MethodPost && path == "v1/key":
+		var request kmd.GenerateKeyRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleGenerateKey(w, request)
+		}
+	case r.Method == http.MethodPost && path == "v1/key/import":
+		var request kmd.ImportKeyRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleImportKey(w, request)
+		}
+	case r.Method == http.MethodPost && path == "v1/key/export":
+		var request kmd.ExportKeyRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleExportKey(w, request)
+		}
+	case r.Method == http.MethodPost && path == "v1/transaction/sign":
+		var request kmd.SignTransactionRequest
+		if decodeKMDRequest(w, r, &request) {
+			k.handleSignTransaction(w, request)
+		}
+	default:
+		writeKMDError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	}
+}
+
+func (k *KMD) handleListWallets(w http.ResponseWriter) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	response := kmd.ListWalletsResponse{Wallets: []kmd.APIV1Wallet{}}
+	for _, wallet := range k.wallets {
+		response.Wallets = append(response.Wallets, wallet.model())
+	}
+	writeJSON(w, response)
+}
+
+func (k *KMD) handleInitWalletHandle(w http.ResponseWriter, request kmd.InitWalletHandleRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, wallet := range k.wallets {
+		if wallet.id != request.WalletID {
+			continue
+		}
+		if wallet.password != request.WalletPassword {
+			writeKMDError(w, http.StatusUnauthorized, "wrong password")
+			return
+		}
+		token := randomHex(32)
+		k.handles[token] = wallet
+		writeJSON(w, kmd.InitWalletHandleResponse{WalletHandleToken: token})
+		return
+	}
+	writeKMDError(w, http.StatusNotFound, "wallet not found")
+}
+
+func (k *KMD) handleReleaseWalletHandle(w http.ResponseWriter, request kmd.ReleaseWalletHandleRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.handles[request.WalletHandleToken] == nil {
+		writeKMDError(w, http.StatusUnauthorized, "invalid wallet handle")
+		return
+	}
+	delete(k.handles, request.WalletHandleToken)
+	writeJSON(w, kmd.ReleaseWalletHandleResponse{})
+}
+
+func (k *KMD) handleListKeys(w http.ResponseWriter, request kmd.ListKeysRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	wallet := k.walletOfHandle(w, request.WalletHandleToken)
+	if wallet == nil {
+		return
+	}
+	response := kmd.ListKeysResponse{Addresses: []string{}}
+	for _, private_key := range wallet.keys {
+		account, _ := crypto.AccountFromPrivateKey(private_key)
+		response.Addresses = append(response.Addresses, account.Address.String())
+	}
+	writeJSON(w, response)
+}
+
+func (k *KMD) handleGenerateKey(w http.ResponseWriter, request kmd.GenerateKeyRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	wallet := k.walletOfHandle(w, request.WalletHandleToken)
+	if wallet == nil {
+		return
+	}
+	address := wallet.addKey(crypto.GenerateAccount().PrivateKey)
+	writeJSON(w, kmd.GenerateKeyResponse{Address: address.String()})
+}
+
+func (k *KMD) handleImportKey(w http.ResponseWriter, request kmd.ImportKeyRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	wallet := k.walletOfHandle(w, request.WalletHandleToken)
+	if wallet == nil {
+		return
+	}
+	if len(request.PrivateKey) != ed25519.PrivateKeySize {
+		writeKMDError(w, http.StatusBadRequest, "invalid private key")
+		return
+	}
+	address := wallet.addKey(request.PrivateKey)
+	writeJSON(w, kmd.ImportKeyResponse{Address: address.String()})
+}
+
+func (k *KMD) handleExportKey(w http.ResponseWriter, request kmd.ExportKeyRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	wallet := k.walletOfHandle(w, request.WalletHandleToken)
+	if wallet == nil {
+		return
+	}
+	if wallet.password != request.WalletPassword {
+		writeKMDError(w, http.StatusUnauthorized, "wrong password")
+		return
+	}
+	address, err := types.DecodeAddress(request.Address)
+	if err != nil {
+		writeKMDError(w, http.StatusBadRequest, "invalid address "+request.Address)
+		return
+	}
+	private_key := wallet.key(address)
+	if private_key == nil {
+		writeKMDError(w, http.StatusNotFound, "key does not exist in this wallet")
+		return
+	}
+	k.exported_keys++
+	writeJSON(w, kmd.ExportKeyResponse{PrivateKey: private_key})
+}
+
+// ExportedKeys is the number of keys exported so far
+func (k *KMD) ExportedKeys() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.exported_keys
+}
+
+// handleSignTransaction signs with the key of the sender, or of the given public key for rekeyed senders
+func (k *KMD) handleSignTransaction(w http.ResponseWriter, request kmd.SignTransactionRequest) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	wallet := k.walletOfHandle(w, request.WalletHandleToken)
+	if wallet == nil {
+		return
+	}
+	if wallet.password != request.WalletPassword {
+		writeKMDError(w, http.StatusUnauthorized, "wrong password")
+		return
+	}
+	var txn types.Transaction
+	err := msgpack.Decode(request.Transaction, &txn)
+	if err != nil {
+		writeKMDError(w, http.StatusBadRequest, "decoding transaction: "+err.Error())
+		return
+	}
+	signer := txn.Sender
+	if len(request.PublicKey) != 0 {
+		copy(signer[:], request.PublicKey)
+	}
+	private_key := wallet.key(signer)
+	if private_key == nil {
+		writeKMDError(w, http.StatusNotFound, "key does not exist in this wallet")
+		return
+	}
+	_, signed_txn, err := crypto.SignTransaction(private_key, txn)
+	if err != nil {
+		writeKMDError(w, http.StatusBadRequest, "signing transaction: "+err.Error())
+		return
+	}
+	writeJSON(w, kmd.SignTransactionResponse{SignedTransaction: signed_txn})
+}
+
+// walletOfHandle looks up the wallet of a handle token, k.mu must be held
+func (k *KMD) walletOfHandle(w http.ResponseWriter, token string) *kmdWallet {
+	wallet := k.handles[token]
+	if wallet == nil {
+		writeKMDError(w, http.StatusUnauthorized, "invalid wallet handle")
+	}
+	return wallet
+}
+
+// decodeKMDRequest decodes the json body of a request, answering with an error if that fails
+func decodeKMDRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Decode(body, request)
+	}
+	if err != nil {
+		writeKMDError(w, http.StatusBadRequest, "decoding request: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeKMDError answers like kmd, the client reports the message of the response envelope
+func writeKMDError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(json.Encode(kmd.APIV1ResponseEnvelope{Error: true, Message: message}))
+}
+
+func randomHex(size int) string {
+	random := make([]byte, size)
+	rand.Read(random)
+	return hex.EncodeToString(random)
+}
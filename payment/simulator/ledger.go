@@ -0,0 +1,695 @@
+// Package simulator is an in-process fake of an algod node in dev mode, for tests without a network.
+// Its ledger executes payments, asset transfers and calls of the payment channel contract, whose
+// semantics are implemented in Go, and commits every accepted group in a block of its own.
+// The ledger serves the algod REST endpoints the node uses, so the algod client of the SDK
+// talks to it like to a real node:
+//
+//	ledger := simulator.NewLedger()
+//	server := httptest.NewServer(ledger)
+//	algod_client, err := algod.MakeClient(server.URL, "")
+package simulator
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	GENESIS_ID        = "simulator-v1"
+	CONSENSUS_VERSION = "future"
+
+	MIN_TXN_FEE    = 1_000
+	MAX_TXN_LIFE   = 1_000
+	MAX_GROUP_SIZE = 16
+
+	// minimum balance of an account, and the increase for every asset it holds
+	MIN_BALANCE = 100_000
+	// minimum balance increase of the creator of an app, per extra program page and per global state entry
+	APP_FLAT_PARAMS_MIN_BALANCE = 100_000
+	SCHEMA_UINT_MIN_BALANCE     = 28_500
+	SCHEMA_BYTES_MIN_BALANCE    = 50_000
+
+	// opcode budget every app call adds to the pooled budget of its group
+	APP_CALL_BUDGET = 700
+
+	// transactions committed before the first block of the simulator, app and asset ids start above it
+	INITIAL_TXN_COUNTER = 1_000
+)
+
+var errUnknownTxn = errors.New("transaction not found")
+
+// Ledger is the state of the simulated network. It is safe for concurrent use.
+type Ledger struct {
+	mu sync.Mutex
+
+	genesis_hash types.Digest
+	round        uint64
+	state        *ledgerState
+	blocks       map[uint64]types.Block
+	confirmed    map[string]models.PendingTransactionInfoResponse // by transaction id
+	programs     map[string][]byte                                // compiled programs by teal source
+
+	round_advanced chan struct{} // closed and replaced whenever a block is committed
+}
+
+// ledgerState is everything a group can change, groups are evaluated on a copy which replaces it once they succeed
+type ledgerState struct {
+	accounts    map[types.Address]*account
+	apps        map[uint64]*app
+	assets      map[uint64]*asset
+	txn_counter uint64
+}
+
+type account struct {
+	amount    uint64
+	auth_addr types.Address     // zero unless the account was rekeyed
+	assets    map[uint64]uint64 // holdings of the assets the account opted into
+}
+
+type app struct {
+	creator          types.Address
+	approval_program []byte
+	clear_program    []byte
+	global_schema    types.StateSchema
+	local_schema     types.StateSchema
+	extra_pages      uint32
+	global_state     map[string]tealValue
+}
+
+type asset struct {
+	creator types.Address
+	params  types.AssetParams
+}
+
+// tealValue is a value of the global state of an app, either bytes or an uint
+type tealValue struct {
+	is_bytes bool
+	bytes    []byte
+	uint     uint64
+}
+
+// NewLedger returns a ledger at round 1 without any accounts
+func NewLedger() *Ledger {
+	l := &Ledger{
+		genesis_hash: sha512.Sum512_256([]byte(GENESIS_ID)),
+		state: &ledgerState{
+			accounts:    make(map[types.Address]*account),
+			apps:        make(map[uint64]*app),
+			assets:      make(map[uint64]*asset),
+			txn_counter: INITIAL_TXN_COUNTER,
+		},
+		blocks:         make(map[uint64]types.Block),
+		confirmed:      make(map[string]models.PendingTransactionInfoResponse),
+		programs:       make(map[string][]byte),
+		round_advanced: make(chan struct{}),
+	}
+	l.commitBlock(l.state)
+	return l
+}
+
+// GenesisHash identifies the simulated network, transactions and signed channel states are bound to it
+func (l *Ledger) GenesisHash() []byte {
+	return l.genesis_hash[:]
+}
+
+// Round returns the last committed round
+func (l *Ledger) Round() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.round
+}
+
+// AdvanceRounds commits amount empty blocks, e.g. to let a dispute window or the timelock of an htlc pass
+func (l *Ledger) AdvanceRounds(amount uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := uint64(0); i < amount; i++ {
+		l.commitBlock(l.state)
+	}
+}
+
+// RegisterProgram makes the compile endpoint return the program for the teal source, the simulator cannot assemble teal
+func (l *Ledger) RegisterProgram(source []byte, program []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.programs[string(source)] = program
+}
+
+// Fund mints amount microalgos into the account, like the dispenser of a dev network
+func (l *Ledger) Fund(address types.Address, amount uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state.account(address).amount += amount
+}
+
+// NewAccount generates an account funded with amount microalgos
+func (l *Ledger) NewAccount(amount uint64) crypto.Account {
+	new_account := crypto.GenerateAccount()
+	l.Fund(new_account.Address, amount)
+	return new_account
+}
+
+// Balance returns the microalgos held by the account
+func (l *Ledger) Balance(address types.Address) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if account, ok := l.state.accounts[address]; ok {
+		return account.amount
+	}
+	return 0
+}
+
+// AssetBalance returns the amount of the asset held by the account, and whether it opted into the asset
+func (l *Ledger) AssetBalance(address types.Address, assetID uint64) (uint64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	account, ok := l.state.accounts[address]
+	if !ok {
+		return 0, false
+	}
+	amount, opted_in := account.assets[assetID]
+	return amount, opted_in
+}
+
+// SubmitGroup evaluates the signed transactions as one atomic group and commits them in a new block.
+// A group failing any check changes nothing. It returns the ids of the transactions.
+func (l *Ledger) SubmitGroup(signedTxns []types.SignedTxn) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	txids := make([]string, 0, len(signedTxns))
+	for _, signed_txn := range signedTxns {
+		txid := crypto.GetTxID(signed_txn.Txn)
+		if _, ok := l.confirmed[txid]; ok {
+			return nil, fmt.Errorf("transaction already in ledger: %s", txid)
+		}
+		txids = append(txids, txid)
+	}
+
+	state := l.state.clone()
+	result := l.evalGroup(state, signedTxns, false, 0)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	l.commitBlock(state)
+	for i, txid := range txids {
+		confirmed_txn := result.txns[i]
+		confirmed_txn.ConfirmedRound = l.round
+		l.confirmed[txid] = confirmed_txn
+	}
+	return txids, nil
+}
+
+// SimulateGroup evaluates the group on the next round without committing it. Transactions without any
+// signature pass if allowEmptySignatures is set, extraBudget is added to the pooled opcode budget.
+func (l *Ledger) SimulateGroup(signedTxns []types.SignedTxn, allowEmptySignatures bool, extraBudget uint64) models.SimulateTransactionGroupResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := l.evalGroup(l.state.clone(), signedTxns, allowEmptySignatures, extraBudget)
+	simulation := models.SimulateTransactionGroupResult{
+		AppBudgetAdded:    result.budget_added,
+		AppBudgetConsumed: result.budget_consumed,
+		TxnResults:        make([]models.SimulateTransactionResult, len(signedTxns)),
+	}
+	for i := range signedTxns {
+		simulation.TxnResults[i].TxnResult = models.PendingTransactionResponse{Transaction: signedTxns[i]}
+		if i < len(result.txns) {
+			simulation.TxnResults[i].TxnResult = models.PendingTransactionResponse(result.txns[i])
+			simulation.TxnResults[i].AppBudgetConsumed = result.txn_budgets[i]
+		}
+	}
+	if result.err != nil {
+		simulation.FailedAt = result.err.failed_at
+		simulation.FailureMessage = result.err.Error()
+	}
+	return simulation
+}
+
+// pendingTransaction returns a confirmed transaction by its id
+func (l *Ledger) pendingTransaction(txid string) (models.PendingTransactionInfoResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	confirmed_txn, ok := l.confirmed[txid]
+	if !ok {
+		return models.PendingTransactionInfoResponse{}, errUnknownTxn
+	}
+	return confirmed_txn, nil
+}
+
+// commitBlock makes state the state of a new round, the caller holds the lock
+func (l *Ledger) commitBlock(state *ledgerState) {
+	l.state = state
+	l.round++
+	l.blocks[l.round] = types.Block{
+		BlockHeader: types.BlockHeader{
+			Round:       types.Round(l.round),
+			TimeStamp:   time.Now().Unix(),
+			GenesisID:   GENESIS_ID,
+			GenesisHash: l.genesis_hash,
+			TxnCounter:  state.txn_counter,
+		},
+	}
+	close(l.round_advanced)
+	l.round_advanced = make(chan struct{})
+}
+
+// waitForRound returns once the round was committed, or after maxWait
+func (l *Ledger) waitForRound(round uint64, maxWait time.Duration) {
+	deadline := time.After(maxWait)
+	for {
+		l.mu.Lock()
+		reached := l.round >= round
+		round_advanced := l.round_advanced
+		l.mu.Unlock()
+		if reached {
+			return
+		}
+		select {
+		case <-round_advanced:
+		case <-deadline:
+			return
+		}
+	}
+}
+
+// evalError is the reason a group was rejected, failed_at is the path to the failing transaction
+type evalError struct {
+	failed_at []uint64
+	message   string
+}
+
+func (e *evalError) Error() string {
+	if len(e.failed_at) == 0 {
+		return e.message
+	}
+	return fmt.Sprintf("transaction %v: %s", e.failed_at, e.message)
+}
+
+// groupResult is the outcome of evaluating a group, txns holds the results of the transactions evaluated before a failure
+type groupResult struct {
+	txns            []models.PendingTransactionInfoResponse
+	txn_budgets     []uint64
+	budget_added    uint64
+	budget_consumed uint64
+	err             *evalError
+}
+
+// groupContext is shared by all transactions of a group while it is evaluated
+type groupContext struct {
+	txns       []types.Transaction
+	round      uint64
+	fee_credit uint64 // fees paid above the minimum, they pay for inner transactions
+
+	budget_added    uint64
+	budget_consumed uint64
+}
+
+// evalGroup checks and applies the group to state, the caller holds the lock
+func (l *Ledger) evalGroup(state *ledgerState, signedTxns []types.SignedTxn, allowEmptySignatures bool, extraBudget uint64) groupResult {
+	result := groupResult{}
+	fail := func(index int, format string, args ...interface{}) groupResult {
+		result.err = &evalError{message: fmt.Sprintf(format, args...)}
+		if index >= 0 {
+			result.err.failed_at = []uint64{uint64(index)}
+		}
+		return result
+	}
+
+	if len(signedTxns) == 0 {
+		return fail(-1, "empty transaction group")
+	}
+	if len(signedTxns) > MAX_GROUP_SIZE {
+		return fail(-1, "group of %d transactions exceeds the maximum of %d", len(signedTxns), MAX_GROUP_SIZE)
+	}
+
+	group := &groupContext{round: l.round + 1}
+	fees := uint64(0)
+	for i, signed_txn := range signedTxns {
+		group.txns = append(group.txns, signed_txn.Txn)
+		err := l.checkWellFormed(signed_txn.Txn, group.round)
+		if err != nil {
+			return fail(i, "%v", err)
+		}
+		fees += uint64(signed_txn.Txn.Fee)
+		if signed_txn.Txn.Type == types.ApplicationCallTx {
+			group.budget_added += APP_CALL_BUDGET
+		}
+	}
+	group.budget_added += extraBudget
+	result.budget_added = group.budget_added
+
+	err := checkGroupID(group.txns)
+	if err != nil {
+		return fail(-1, "%v", err)
+	}
+	minimum_fees := uint64(MIN_TXN_FEE * len(signedTxns))
+	if fees < minimum_fees {
+		return fail(-1, "txgroup had %d in fees, which is less than the minimum %d", fees, minimum_fees)
+	}
+	group.fee_credit = fees - minimum_fees
+
+	for i, signed_txn := range signedTxns {
+		err := state.checkAuthorization(signed_txn, allowEmptySignatures)
+		if err != nil {
+			return fail(i, "%v", err)
+		}
+
+		consumed_before := group.budget_consumed
+		confirmed_txn, eval_err := state.applyTxn(group, i)
+		confirmed_txn.Transaction = signed_txn
+		result.budget_consumed = group.budget_consumed
+		result.txns = append(result.txns, confirmed_txn)
+		result.txn_budgets = append(result.txn_budgets, group.budget_consumed-consumed_before)
+		if eval_err != nil {
+			eval_err.failed_at = append([]uint64{uint64(i)}, eval_err.failed_at...)
+			result.err = eval_err
+			return result
+		}
+	}
+	return result
+}
+
+// checkWellFormed checks the transaction can be committed in round on this network
+func (l *Ledger) checkWellFormed(txn types.Transaction, round uint64) error {
+	if txn.GenesisHash != l.genesis_hash {
+		return errors.New("transaction is for a different network, genesis hash mismatch")
+	}
+	if txn.GenesisID != "" && txn.GenesisID != GENESIS_ID {
+		return fmt.Errorf("transaction is for genesis id %q, expected %q", txn.GenesisID, GENESIS_ID)
+	}
+	if uint64(txn.FirstValid) > round || round > uint64(txn.LastValid) {
+		return fmt.Errorf("round %d outside of %d--%d", round, txn.FirstValid, txn.LastValid)
+	}
+	if txn.LastValid-txn.FirstValid > MAX_TXN_LIFE {
+		return fmt.Errorf("transaction window size %d exceeds the maximum of %d", txn.LastValid-txn.FirstValid, MAX_TXN_LIFE)
+	}
+	return nil
+}
+
+// checkGroupID checks that a group of several transactions is bound together by its group id
+func checkGroupID(txns []types.Transaction) error {
+	ungrouped := make([]types.Transaction, len(txns))
+	needs_group := len(txns) > 1
+	for i, txn := range txns {
+		ungrouped[i] = txn
+		ungrouped[i].Group = types.Digest{}
+		needs_group = needs_group || txn.Group != types.Digest{}
+	}
+	if !needs_group {
+		return nil
+	}
+	group_id, err := crypto.ComputeGroupID(ungrouped)
+	if err != nil {
+		return err
+	}
+	for _, txn := range txns {
+		if txn.Group != group_id {
+			return errors.New("incomplete group: group id does not match the transactions")
+		}
+	}
+	return nil
+}
+
+// checkAuthorization verifies the signature of the transaction against the key authorizing its sender
+func (s *ledgerState) checkAuthorization(signedTxn types.SignedTxn, allowEmptySignatures bool) error {
+	txn := signedTxn.Txn
+	authorizer := txn.Sender
+	if sender, ok := s.accounts[txn.Sender]; ok && !sender.auth_addr.IsZero() {
+		authorizer = sender.auth_addr
+	}
+	if !signedTxn.AuthAddr.IsZero() && signedTxn.AuthAddr != authorizer {
+		return fmt.Errorf("should have been authorized by %s but was actually authorized by %s", authorizer, signedTxn.AuthAddr)
+	}
+
+	has_sig := signedTxn.Sig != types.Signature{}
+	has_msig := len(signedTxn.Msig.Subsigs) > 0
+	has_lsig := len(signedTxn.Lsig.Logic) > 0
+	message := append([]byte("TX"), msgpack.Encode(txn)...)
+	switch {
+	case has_sig && !has_msig && !has_lsig:
+		if !ed25519.Verify(authorizer[:], message, signedTxn.Sig[:]) {
+			return fmt.Errorf("signature does not verify against %s", authorizer)
+		}
+	case has_msig && !has_sig && !has_lsig:
+		if !crypto.VerifyMultisig(authorizer, message, signedTxn.Msig) {
+			return fmt.Errorf("multisig signature does not verify against %s", authorizer)
+		}
+	case has_lsig:
+		return errors.New("logic signatures are not supported by the simulator")
+	case !has_sig && !has_msig:
+		if !allowEmptySignatures {
+			return errors.New("transaction is not signed")
+		}
+	default:
+		return errors.New("transaction has more than one kind of signature")
+	}
+	return nil
+}
+
+// applyTxn applies the transaction at index of the group and checks the minimum balances of the accounts it touched
+func (s *ledgerState) applyTxn(group *groupContext, index int) (models.PendingTransactionInfoResponse, *evalError) {
+	txn := group.txns[index]
+	confirmed_txn := models.PendingTransactionInfoResponse{Transaction: types.SignedTxn{Txn: txn}}
+	touched := map[types.Address]bool{txn.Sender: true}
+	s.txn_counter++
+
+	var err error
+	switch txn.Type {
+	case types.PaymentTx:
+		err = s.applyPayment(txn, touched)
+	case types.AssetTransferTx:
+		err = s.applyAssetTransfer(txn, touched)
+	case types.AssetConfigTx:
+		confirmed_txn.AssetIndex, err = s.applyAssetConfig(txn)
+	case types.ApplicationCallTx:
+		return s.applyAppCall(group, index, confirmed_txn, touched)
+	default:
+		err = fmt.Errorf("transaction type %q is not supported by the simulator", txn.Type)
+	}
+	if err == nil {
+		err = s.applyRekey(txn)
+	}
+	if err == nil {
+		err = s.checkMinBalances(touched)
+	}
+	if err != nil {
+		return confirmed_txn, &evalError{message: err.Error()}
+	}
+	return confirmed_txn, nil
+}
+
+// debit takes the fee and amount from the account
+func (s *ledgerState) debit(address types.Address, fee uint64, amount uint64) error {
+	sender := s.account(address)
+	if sender.amount < fee || sender.amount-fee < amount {
+		return fmt.Errorf("overspend: account %s tried to spend %d with a balance of %d", address, fee+amount, sender.amount)
+	}
+	sender.amount -= fee + amount
+	return nil
+}
+
+func (s *ledgerState) applyPayment(txn types.Transaction, touched map[types.Address]bool) error {
+	err := s.debit(txn.Sender, uint64(txn.Fee), uint64(txn.Amount))
+	if err != nil {
+		return err
+	}
+	s.account(txn.Receiver).amount += uint64(txn.Amount)
+	touched[txn.Receiver] = true
+
+	if txn.CloseRemainderTo.IsZero() {
+		return nil
+	}
+	sender := s.account(txn.Sender)
+	if len(sender.assets) > 0 {
+		return fmt.Errorf("cannot close account %s with active asset holdings", txn.Sender)
+	}
+	if len(s.createdApps(txn.Sender)) > 0 {
+		return fmt.Errorf("cannot close account %s which created apps", txn.Sender)
+	}
+	s.account(txn.CloseRemainderTo).amount += sender.amount
+	sender.amount = 0
+	touched[txn.CloseRemainderTo] = true
+	return nil
+}
+
+func (s *ledgerState) applyAssetTransfer(txn types.Transaction, touched map[types.Address]bool) error {
+	err := s.debit(txn.Sender, uint64(txn.Fee), 0)
+	if err != nil {
+		return err
+	}
+	asset_id := uint64(txn.XferAsset)
+	asset_params, ok := s.assets[asset_id]
+	if !ok {
+		return fmt.Errorf("asset %d does not exist", asset_id)
+	}
+	if !txn.AssetSender.IsZero() {
+		return errors.New("clawback transfers are not supported by the simulator")
+	}
+
+	sender := s.account(txn.Sender)
+	sender_holding, opted_in := sender.assets[asset_id]
+	// an opt-in is a transfer of 0 to oneself
+	if txn.AssetReceiver == txn.Sender && txn.AssetAmount == 0 && !opted_in {
+		sender.assets[asset_id] = 0
+		return nil
+	}
+	if !opted_in {
+		return fmt.Errorf("asset %d missing from %s", asset_id, txn.Sender)
+	}
+	if sender_holding < txn.AssetAmount {
+		return fmt.Errorf("underflow on subtracting %d from sender amount %d of asset %d", txn.AssetAmount, sender_holding, asset_id)
+	}
+	err = s.creditAsset(txn.AssetReceiver, asset_id, txn.AssetAmount)
+	if err != nil {
+		return err
+	}
+	sender.assets[asset_id] -= txn.AssetAmount
+	touched[txn.AssetReceiver] = true
+
+	if txn.AssetCloseTo.IsZero() {
+		return nil
+	}
+	if txn.Sender == asset_params.creator {
+		return fmt.Errorf("cannot close asset %d in its creator account", asset_id)
+	}
+	err = s.creditAsset(txn.AssetCloseTo, asset_id, sender.assets[asset_id])
+	if err != nil {
+		return err
+	}
+	delete(sender.assets, asset_id)
+	touched[txn.AssetCloseTo] = true
+	return nil
+}
+
+func (s *ledgerState) creditAsset(address types.Address, assetID uint64, amount uint64) error {
+	receiver := s.account(address)
+	if _, opted_in := receiver.assets[assetID]; !opted_in {
+		return fmt.Errorf("receiver error: %s must optin to asset %d", address, assetID)
+	}
+	receiver.assets[assetID] += amount
+	return nil
+}
+
+// applyAssetConfig creates an asset, its creator holds the whole supply
+func (s *ledgerState) applyAssetConfig(txn types.Transaction) (uint64, error) {
+	err := s.debit(txn.Sender, uint64(txn.Fee), 0)
+	if err != nil {
+		return 0, err
+	}
+	if txn.ConfigAsset != 0 {
+		return 0, errors.New("only asset creation is supported by the simulator")
+	}
+	asset_id := s.txn_counter
+	s.assets[asset_id] = &asset{creator: txn.Sender, params: txn.AssetParams}
+	s.account(txn.Sender).assets[asset_id] = txn.AssetParams.Total
+	return asset_id, nil
+}
+
+func (s *ledgerState) applyRekey(txn types.Transaction) error {
+	if txn.RekeyTo.IsZero() {
+		return nil
+	}
+	sender := s.account(txn.Sender)
+	if txn.RekeyTo == txn.Sender {
+		sender.auth_addr = types.ZeroAddress
+	} else {
+		sender.auth_addr = txn.RekeyTo
+	}
+	return nil
+}
+
+// checkMinBalances checks that accounts holding algos keep their minimum balance, closed accounts hold none
+func (s *ledgerState) checkMinBalances(addresses map[types.Address]bool) error {
+	for address := range addresses {
+		account, ok := s.accounts[address]
+		if !ok || account.amount == 0 && len(account.assets) == 0 && len(s.createdApps(address)) == 0 {
+			continue
+		}
+		min_balance := s.minBalance(address)
+		if account.amount < min_balance {
+			return fmt.Errorf("account %s balance %d below min %d", address, account.amount, min_balance)
+		}
+	}
+	return nil
+}
+
+func (s *ledgerState) minBalance(address types.Address) uint64 {
+	min_balance := uint64(MIN_BALANCE)
+	if account, ok := s.accounts[address]; ok {
+		min_balance += uint64(MIN_BALANCE * len(account.assets))
+	}
+	for _, created_app := range s.createdApps(address) {
+		min_balance += created_app.minBalance()
+	}
+	return min_balance
+}
+
+// minBalance is the increase of the minimum balance of the creator of the app
+func (a *app) minBalance() uint64 {
+	return APP_FLAT_PARAMS_MIN_BALANCE*(1+uint64(a.extra_pages)) +
+		SCHEMA_UINT_MIN_BALANCE*a.global_schema.NumUint +
+		SCHEMA_BYTES_MIN_BALANCE*a.global_schema.NumByteSlice
+}
+
+// createdApps returns the apps created by the account by their id
+func (s *ledgerState) createdApps(address types.Address) map[uint64]*app {
+	created_apps := make(map[uint64]*app)
+	for app_id, created_app := range s.apps {
+		if created_app.creator == address {
+			created_apps[app_id] = created_app
+		}
+	}
+	return created_apps
+}
+
+// account returns the account, it is created on first use
+func (s *ledgerState) account(address types.Address) *account {
+	existing, ok := s.accounts[address]
+	if !ok {
+		existing = &account{assets: make(map[uint64]uint64)}
+		s.accounts[address] = existing
+	}
+	return existing
+}
+
+// clone copies the state, so that a group can be evaluated without touching the original
+func (s *ledgerState) clone() *ledgerState {
+	cloned := &ledgerState{
+		accounts:    make(map[types.Address]*account, len(s.accounts)),
+		apps:        make(map[uint64]*app, len(s.apps)),
+		assets:      make(map[uint64]*asset, len(s.assets)),
+		txn_counter: s.txn_counter,
+	}
+	for address, original := range s.accounts {
+		cloned_account := *original
+		cloned_account.assets = make(map[uint64]uint64, len(original.assets))
+		for asset_id, amount := range original.assets {
+			cloned_account.assets[asset_id] = amount
+		}
+		cloned.accounts[address] = &cloned_account
+	}
+	for app_id, original := range s.apps {
+		cloned_app := *original
+		cloned_app.global_state = make(map[string]tealValue, len(original.global_state))
+		for key, value := range original.global_state {
+			cloned_app.global_state[key] = value
+		}
+		cloned.apps[app_id] = &cloned_app
+	}
+	for asset_id, original := range s.assets {
+		cloned_asset := *original
+		cloned.assets[asset_id] = &cloned_asset
+	}
+	return cloned
+}
@@ -0,0 +1,103 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+)
+
+// StateDomain tags what a signed channel state authorizes, so that a signature of one kind
+// of message can never be replayed as another
+type StateDomain string
+
+const (
+	// STATE_UPDATE_DOMAIN signs a state which can be disputed on chain, including its pending htlcs
+	STATE_UPDATE_DOMAIN StateDomain = "STATE_UPDATE"
+	// CLOSE_CHANNEL_DOMAIN signs the final balances of a cooperative close, it has no htlcs
+	CLOSE_CHANNEL_DOMAIN StateDomain = "CLOSE_CHANNEL"
+)
+
+// ChannelState is a balance split of a channel signed by alice and bob
+type ChannelState struct {
+	Domain      StateDomain
+	GenesisHash []byte // network the state is valid on
+	AppID       uint64
+	AssetID     uint64 // 0 for algo channels
+
+	AliceBalance uint64
+	BobBalance   uint64
+	Timestamp    int64  // unix timestamp in nanoseconds, orders the states of a channel
+	HTLCs        []HTLC // only part of STATE_UPDATE_DOMAIN states
+}
+
+// Encode returns the canonical encoding of the state, byte for byte the Concat(...) of payment_contract.py:
+// DOMAIN | uint64(PROTOCOL_VERSION) | genesis_hash | "," | app_id | "," | asset_id | "," | alice_balance | ","
+// | bob_balance | "," | timestamp [| "," | htlcs] | "END_" DOMAIN
+func (c ChannelState) Encode() ([]byte, error) {
+	if c.Domain != STATE_UPDATE_DOMAIN && c.Domain != CLOSE_CHANNEL_DOMAIN {
+		return nil, fmt.Errorf("unknown state domain %q", c.Domain)
+	}
+	if c.Domain == CLOSE_CHANNEL_DOMAIN && len(c.HTLCs) != 0 {
+		return nil, fmt.Errorf("%s states cannot have htlcs", c.Domain)
+	}
+	if len(c.HTLCs) > MAX_HTLCS {
+		return nil, fmt.Errorf("state has %d htlcs, at most %d are allowed", len(c.HTLCs), MAX_HTLCS)
+	}
+
+	data_raw := make([]byte, 0)
+	data_raw = append(data_raw, []byte(c.Domain)...)
+	data_raw = append(data_raw, domainSeparator(c.GenesisHash)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(c.AppID)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(c.AssetID)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(c.AliceBalance)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(c.BobBalance)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(uint64(c.Timestamp))...)
+	if c.Domain == STATE_UPDATE_DOMAIN {
+		data_raw = append(data_raw, []byte(",")...)
+		data_raw = append(data_raw, EncodeHTLCs(c.HTLCs)...)
+	}
+	data_raw = append(data_raw, []byte("END_"+c.Domain)...)
+	return data_raw, nil
+}
+
+// Hash returns the sha3-256 hash of the encoded state, the message verified by ed25519verify_bare in the contract
+func (c ChannelState) Hash() ([32]byte, error) {
+	data_raw, err := c.Encode()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha3.Sum256(data_raw), nil
+}
+
+// Sign signs the hash of the state with the channel signing key of account
+func (c ChannelState) Sign(account Signer) ([]byte, error) {
+	data_hashed, err := c.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return account.SignBytes(data_hashed[:])
+}
+
+// Verify checks the signature of the state against the signing key with the given address
+func (c ChannelState) Verify(signature []byte, algo_address string) bool {
+	data_hashed, err := c.Hash()
+	if err != nil {
+		fmt.Printf("Error hashing state: %v\n", err)
+		return false
+	}
+	decoded_address, err := types.DecodeAddress(algo_address)
+	if err != nil {
+		fmt.Printf("Error decoding address: %v\n", err)
+		return false
+	}
+
+	pub_key := ed25519.PublicKey(decoded_address[:])
+	return ed25519.Verify(pub_key, data_hashed[:], signature)
+}
@@ -0,0 +1,242 @@
+package payment
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+)
+
+// stateVector is a golden vector of testdata/state_vectors.json, generated by testdata/generate_state_vectors.py
+type stateVector struct {
+	Name         string `json:"name"`
+	Domain       string `json:"domain"`
+	GenesisHash  string `json:"genesis_hash"`
+	AppID        uint64 `json:"app_id"`
+	AssetID      uint64 `json:"asset_id"`
+	AliceBalance uint64 `json:"alice_balance"`
+	BobBalance   uint64 `json:"bob_balance"`
+	Timestamp    int64  `json:"timestamp"`
+	HTLCs        []struct {
+		OfferedByAlice bool   `json:"offered_by_alice"`
+		Amount         uint64 `json:"amount"`
+		Hashlock       string `json:"hashlock"`
+		Timelock       uint64 `json:"timelock"`
+	} `json:"htlcs"`
+	Encoded string `json:"encoded"`
+	Hash    string `json:"hash"`
+}
+
+func loadStateVectors(t *testing.T) []stateVector {
+	data, err := os.ReadFile("testdata/state_vectors.json")
+	if err != nil {
+		t.Fatalf("reading vectors: %v", err)
+	}
+	var vectors []stateVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("decoding vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no vectors")
+	}
+	return vectors
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decoding hex %q: %v", s, err)
+	}
+	return decoded
+}
+
+func (v stateVector) channelState(t *testing.T) ChannelState {
+	state := ChannelState{
+		Domain:       StateDomain(v.Domain),
+		GenesisHash:  mustDecodeHex(t, v.GenesisHash),
+		AppID:        v.AppID,
+		AssetID:      v.AssetID,
+		AliceBalance: v.AliceBalance,
+		BobBalance:   v.BobBalance,
+		Timestamp:    v.Timestamp,
+	}
+	for _, h := range v.HTLCs {
+		htlc := HTLC{OfferedByAlice: h.OfferedByAlice, Amount: h.Amount, Timelock: h.Timelock}
+		copy(htlc.Hashlock[:], mustDecodeHex(t, h.Hashlock))
+		state.HTLCs = append(state.HTLCs, htlc)
+	}
+	return state
+}
+
+func TestChannelStateGoldenVectors(t *testing.T) {
+	for _, vector := range loadStateVectors(t) {
+		t.Run(vector.Name, func(t *testing.T) {
+			state := vector.channelState(t)
+
+			encoded, err := state.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if got := hex.EncodeToString(encoded); got != vector.Encoded {
+				t.Errorf("encoding mismatch\n got: %s\nwant: %s", got, vector.Encoded)
+			}
+
+			hash, err := state.Hash()
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if got := hex.EncodeToString(hash[:]); got != vector.Hash {
+				t.Errorf("hash mismatch\n got: %s\nwant: %s", got, vector.Hash)
+			}
+		})
+	}
+}
+
+func TestChannelStateSignVerify(t *testing.T) {
+	account := crypto.GenerateAccount()
+	signer := NewAccountSigner(account)
+
+	for _, vector := range loadStateVectors(t) {
+		t.Run(vector.Name, func(t *testing.T) {
+			state := vector.channelState(t)
+			signature, err := state.Sign(signer)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if !state.Verify(signature, account.Address.String()) {
+				t.Fatal("signature of the state does not verify")
+			}
+
+			// the signature must not verify for the other domain or a changed balance
+			tampered := state
+			tampered.AliceBalance++
+			if tampered.Verify(signature, account.Address.String()) {
+				t.Error("signature verifies for a different balance")
+			}
+			if len(state.HTLCs) == 0 {
+				other_domain := state
+				other_domain.Domain = STATE_UPDATE_DOMAIN
+				if state.Domain == STATE_UPDATE_DOMAIN {
+					other_domain.Domain = CLOSE_CHANNEL_DOMAIN
+				}
+				if other_domain.Verify(signature, account.Address.String()) {
+					t.Error("signature verifies in another domain")
+				}
+			}
+		})
+	}
+}
+
+func TestSignStateMatchesChannelState(t *testing.T) {
+	account := crypto.GenerateAccount()
+	signer := NewAccountSigner(account)
+	genesis_hash := make([]byte, 32)
+
+	signature, err := SignState(1, 2, signer, 3, 4, genesis_hash, 5, nil)
+	if err != nil {
+		t.Fatalf("SignState: %v", err)
+	}
+	state := ChannelState{Domain: STATE_UPDATE_DOMAIN, GenesisHash: genesis_hash, AppID: 1, AssetID: 2, AliceBalance: 3, BobBalance: 4, Timestamp: 5}
+	if !state.Verify(signature, account.Address.String()) || !VerifyState(1, 2, 3, 4, genesis_hash, signature, account.Address.String(), 5, nil) {
+		t.Error("SignState signature does not verify")
+	}
+
+	signature, err = SignClose(1, 2, signer, 3, 4, genesis_hash, 5)
+	if err != nil {
+		t.Fatalf("SignClose: %v", err)
+	}
+	state.Domain = CLOSE_CHANNEL_DOMAIN
+	if !state.Verify(signature, account.Address.String()) || !VerifyClose(1, 2, 3, 4, genesis_hash, signature, account.Address.String(), 5) {
+		t.Error("SignClose signature does not verify")
+	}
+}
+
+func TestMemoSignatureIsBoundToTheState(t *testing.T) {
+	account := crypto.GenerateAccount()
+	genesis_hash := make([]byte, 32)
+	memo := []byte("coffee")
+
+	signature, err := SignMemo(1, NewAccountSigner(account), genesis_hash, 5, memo)
+	if err != nil {
+		t.Fatalf("SignMemo: %v", err)
+	}
+	if !VerifyMemo(1, signature, account.Address.String(), genesis_hash, 5, memo) {
+		t.Error("SignMemo signature does not verify")
+	}
+
+	// the memo cannot be moved to another channel, network or state
+	other_genesis_hash := make([]byte, 32)
+	other_genesis_hash[0] = 1
+	if VerifyMemo(2, signature, account.Address.String(), genesis_hash, 5, memo) {
+		t.Error("memo verifies for another app")
+	}
+	if VerifyMemo(1, signature, account.Address.String(), other_genesis_hash, 5, memo) {
+		t.Error("memo verifies on another network")
+	}
+	if VerifyMemo(1, signature, account.Address.String(), genesis_hash, 6, memo) {
+		t.Error("memo verifies for another state")
+	}
+}
+
+func TestChannelStateEncodeRejectsInvalidStates(t *testing.T) {
+	invalid_states := map[string]ChannelState{
+		"unknown domain":       {Domain: "WITHDRAW"},
+		"close with htlcs":     {Domain: CLOSE_CHANNEL_DOMAIN, HTLCs: []HTLC{{}}},
+		"too many htlcs":       {Domain: STATE_UPDATE_DOMAIN, HTLCs: make([]HTLC, MAX_HTLCS+1)},
+		"missing domain":       {},
+		"lowercase the domain": {Domain: "state_update"},
+	}
+	for name, state := range invalid_states {
+		if _, err := state.Encode(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// TestContractHashLayout checks that the Concat(...) hashed by the contract still has the layout of ChannelState.Encode,
+// the golden vectors are generated from the same layout
+func TestContractHashLayout(t *testing.T) {
+	source, err := os.ReadFile("payment_contract.py")
+	if err != nil {
+		t.Fatalf("reading contract: %v", err)
+	}
+	layouts := map[StateDomain][]string{
+		STATE_UPDATE_DOMAIN: {
+			`Bytes("STATE_UPDATE")`, `Itob(Int(PROTOCOL_VERSION))`, `App.globalGet(genesis_hash)`,
+			`Bytes(",")`, `Itob(Global.current_application_id())`, `Bytes(",")`, `Itob(App.globalGet(asset_id))`,
+			`Bytes(",")`, `alice_balance`, `Bytes(",")`, `bob_balance`, `Bytes(",")`, `timestamp`,
+			`Bytes(",")`, `htlcs`, `Bytes("END_STATE_UPDATE")`,
+		},
+		CLOSE_CHANNEL_DOMAIN: {
+			`Bytes("CLOSE_CHANNEL")`, `Itob(Int(PROTOCOL_VERSION))`, `App.globalGet(genesis_hash)`,
+			`Bytes(",")`, `Itob(Global.current_application_id())`, `Bytes(",")`, `Itob(App.globalGet(asset_id))`,
+			`Bytes(",")`, `alice_balance`, `Bytes(",")`, `bob_balance`, `Bytes(",")`, `timestamp`,
+			`Bytes("END_CLOSE_CHANNEL")`,
+		},
+	}
+	comment := regexp.MustCompile(`#.*`)
+	for domain, expected_layout := range layouts {
+		// the Concat starts with the domain tag and ends with the line closing Concat and Sha3_256
+		start := strings.Index(string(source), `Concat(`+"\n\t\t\t\t"+`Bytes("`+string(domain)+`")`)
+		if start < 0 {
+			t.Fatalf("%s: Concat not found in contract", domain)
+		}
+		end := strings.Index(string(source[start:]), "\n\t\t\t))")
+		block := string(source[start+len("Concat(") : start+end])
+
+		var layout []string
+		for _, line := range strings.Split(comment.ReplaceAllString(block, ""), "\n") {
+			field := strings.TrimSuffix(strings.TrimSpace(line), ",")
+			if field != "" {
+				layout = append(layout, field)
+			}
+		}
+		if strings.Join(layout, " | ") != strings.Join(expected_layout, " | ") {
+			t.Errorf("%s: contract hashes\n%s\nbut ChannelState encodes\n%s", domain, strings.Join(layout, " | "), strings.Join(expected_layout, " | "))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+# Generates state_vectors.json, the golden vectors of the canonical state encoding.
+# The encoding is rebuilt independently of the Go code, following the Concat(...) of the
+# state_update_hash and close_channel_hash in payment_contract.py.
+#
+# usage: python3 generate_state_vectors.py > state_vectors.json
+import hashlib
+import json
+
+PROTOCOL_VERSION = 1  # must match PROTOCOL_VERSION in payment_contract.py
+
+
+def itob(value: int) -> bytes:
+	return value.to_bytes(8, "big")
+
+
+def encode_htlcs(htlcs) -> bytes:
+	data = b""
+	for htlc in htlcs:
+		data += bytes([0 if htlc["offered_by_alice"] else 1])
+		data += itob(htlc["amount"])
+		data += bytes.fromhex(htlc["hashlock"])
+		data += itob(htlc["timelock"])
+	return data
+
+
+def encode_state(vector) -> bytes:
+	domain = vector["domain"].encode()
+	data = domain
+	data += itob(PROTOCOL_VERSION)
+	data += bytes.fromhex(vector["genesis_hash"])
+	data += b"," + itob(vector["app_id"])
+	data += b"," + itob(vector["asset_id"])
+	data += b"," + itob(vector["alice_balance"])
+	data += b"," + itob(vector["bob_balance"])
+	data += b"," + itob(vector["timestamp"])
+	if vector["domain"] == "STATE_UPDATE":
+		data += b"," + encode_htlcs(vector["htlcs"])
+	data += b"END_" + domain
+	return data
+
+
+TESTNET_GENESIS_HASH = "4863b518a4b3c84ec810f22d4f1081cb0f71f059a7ac20dec62f7f70e5093a22"
+MAINNET_GENESIS_HASH = "c061c4d8fc1dbdded2d7604be4568e3f6d041987ac37bde4b620b5ab39248adf"
+
+vectors = [
+	{
+		"name": "algo channel without htlcs",
+		"domain": "STATE_UPDATE",
+		"genesis_hash": TESTNET_GENESIS_HASH,
+		"app_id": 1234,
+		"asset_id": 0,
+		"alice_balance": 1_500_000,
+		"bob_balance": 500_000,
+		"timestamp": 1_690_000_000_000_000_000,
+		"htlcs": [],
+	},
+	{
+		"name": "asset channel with htlcs in both directions",
+		"domain": "STATE_UPDATE",
+		"genesis_hash": MAINNET_GENESIS_HASH,
+		"app_id": 987_654_321,
+		"asset_id": 31_566_704,
+		"alice_balance": 70,
+		"bob_balance": 20,
+		"timestamp": 1_690_000_123_456_789_000,
+		"htlcs": [
+			{
+				"offered_by_alice": True,
+				"amount": 7,
+				"hashlock": hashlib.sha256(b"preimage alice").hexdigest(),
+				"timelock": 31_000_000,
+			},
+			{
+				"offered_by_alice": False,
+				"amount": 3,
+				"hashlock": hashlib.sha256(b"preimage bob").hexdigest(),
+				"timelock": 31_000_040,
+			},
+		],
+	},
+	{
+		"name": "maximum values",
+		"domain": "STATE_UPDATE",
+		"genesis_hash": "ff" * 32,
+		"app_id": 2**64 - 1,
+		"asset_id": 2**64 - 1,
+		"alice_balance": 2**64 - 1,
+		"bob_balance": 0,
+		"timestamp": 2**63 - 1,
+		"htlcs": [],
+	},
+	{
+		"name": "cooperative close",
+		"domain": "CLOSE_CHANNEL",
+		"genesis_hash": TESTNET_GENESIS_HASH,
+		"app_id": 1234,
+		"asset_id": 0,
+		"alice_balance": 1_500_000,
+		"bob_balance": 500_000,
+		"timestamp": 1_690_000_000_000_000_000,
+		"htlcs": [],
+	},
+]
+
+for vector in vectors:
+	encoded = encode_state(vector)
+	vector["encoded"] = encoded.hex()
+	vector["hash"] = hashlib.sha3_256(encoded).hexdigest()
+
+print(json.dumps(vectors, indent=2))
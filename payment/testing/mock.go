@@ -0,0 +1,64 @@
+package testing
+
+import (
+	"net/http/httptest"
+	"os"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/dancodery/algorand-state-channels/payment/simulator"
+)
+
+const (
+	MOCK_SANDBOX_ACCOUNTS       = 3
+	MOCK_SANDBOX_ACCOUNT_AMOUNT = 4_000_000_000_000_000
+)
+
+// MockSandbox stands in for the algod and kmd containers of the sandbox. Its kmd holds the
+// default wallet with funded accounts, so GetSandboxAccounts and FundAccount work against it.
+type MockSandbox struct {
+	Ledger *simulator.Ledger
+	KMD    *simulator.KMD
+
+	wallet_id    string
+	algod_server *httptest.Server
+	kmd_server   *httptest.Server
+}
+
+// StartMockSandbox serves a fresh simulated network and kmd on local ports
+func StartMockSandbox() *MockSandbox {
+	sandbox := &MockSandbox{
+		Ledger: simulator.NewLedger(),
+		KMD:    simulator.NewKMD(),
+	}
+	sandbox.wallet_id = sandbox.KMD.CreateWallet(KMD_WALLET_NAME, KMD_WALLET_PASSWORD)
+	for i := 0; i < MOCK_SANDBOX_ACCOUNTS; i++ {
+		sandbox.ImportAccount(sandbox.Ledger.NewAccount(MOCK_SANDBOX_ACCOUNT_AMOUNT))
+	}
+	sandbox.algod_server = httptest.NewServer(sandbox.Ledger)
+	sandbox.kmd_server = httptest.NewServer(sandbox.KMD)
+	return sandbox
+}
+
+func (s *MockSandbox) AlgodAddress() string {
+	return s.algod_server.URL
+}
+
+func (s *MockSandbox) KmdAddress() string {
+	return s.kmd_server.URL
+}
+
+// Setenv points GetAlgodClient and GetKmdClient at the mock sandbox
+func (s *MockSandbox) Setenv() {
+	os.Setenv("ALGOD_ADDRESS", s.AlgodAddress())
+	os.Setenv("KMD_ADDRESS", s.KmdAddress())
+}
+
+// ImportAccount adds the key of an account to the default wallet
+func (s *MockSandbox) ImportAccount(account crypto.Account) {
+	s.KMD.ImportKey(s.wallet_id, account)
+}
+
+func (s *MockSandbox) Close() {
+	s.algod_server.Close()
+	s.kmd_server.Close()
+}
@@ -0,0 +1,133 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+)
+
+// withdrawHash is the hash of a withdraw authorization. It binds the new state to the withdrawn amount and the withdrawer.
+func withdrawHash(
+	appID uint64,
+	assetID uint64,
+	aliceBalance uint64,
+	bobBalance uint64,
+	genesisHash []byte,
+	timestamp int64,
+	htlcs []HTLC,
+	amount uint64,
+	withdrawer types.Address,
+) [32]byte {
+	data_raw := make([]byte, 0)
+	data_raw = append(data_raw, []byte("WITHDRAW")...)
+	data_raw = append(data_raw, domainSeparator(genesisHash)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(appID)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(assetID)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(aliceBalance)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(bobBalance)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(uint64(timestamp))...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, EncodeHTLCs(htlcs)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, uint64ToBytes(amount)...)
+	data_raw = append(data_raw, []byte(",")...)
+	data_raw = append(data_raw, withdrawer[:]...)
+	data_raw = append(data_raw, []byte("END_WITHDRAW")...)
+	return sha3.Sum256(data_raw)
+}
+
+// SignWithdraw signs the authorization for withdrawer to take amount out of the channel
+func SignWithdraw(
+	appID uint64,
+	assetID uint64,
+	account Signer,
+	aliceBalance uint64,
+	bobBalance uint64,
+	genesisHash []byte,
+	timestamp int64,
+	htlcs []HTLC,
+	amount uint64,
+	withdrawer string,
+) ([]byte, error) {
+	withdrawer_address, err := types.DecodeAddress(withdrawer)
+	if err != nil {
+		return nil, err
+	}
+	data_hashed := withdrawHash(appID, assetID, aliceBalance, bobBalance, genesisHash, timestamp, htlcs, amount, withdrawer_address)
+	return account.SignBytes(data_hashed[:])
+}
+
+// VerifyWithdraw verifies a withdraw authorization signed by algo_address
+func VerifyWithdraw(
+	appID uint64,
+	assetID uint64,
+	aliceBalance uint64,
+	bobBalance uint64,
+	genesisHash []byte,
+	signature []byte,
+	algo_address string,
+	timestamp int64,
+	htlcs []HTLC,
+	amount uint64,
+	withdrawer string,
+) bool {
+	withdrawer_address, err := types.DecodeAddress(withdrawer)
+	if err != nil {
+		fmt.Printf("Error decoding address: %v\n", err)
+		return false
+	}
+	data_hashed := withdrawHash(appID, assetID, aliceBalance, bobBalance, genesisHash, timestamp, htlcs, amount, withdrawer_address)
+
+	decoded_address, err := types.DecodeAddress(algo_address)
+	if err != nil {
+		fmt.Printf("Error decoding address: %v\n", err)
+		return false
+	}
+
+	pub_key := ed25519.PublicKey(decoded_address[:])
+	return ed25519.Verify(pub_key, data_hashed[:], signature)
+}
+
+// WithdrawFromChannel takes amount out of an open channel. The contract pays it to the sender of the withdraw authorization.
+// It returns the fee paid by the group.
+func WithdrawFromChannel(
+	algod_client *algod.Client,
+	sender_account Signer,
+	// for signed hash
+	genesis_hash []byte,
+	app_id uint64,
+	alice_balance uint64,
+	bob_balance uint64,
+	timestamp uint64,
+	htlcs []HTLC,
+	withdraw_amount uint64,
+	// END for signed hash
+	asset_id uint64,
+	alice_signature []byte,
+	bob_signature []byte,
+) (uint64, error) {
+	state := ChannelState{
+		Domain:       STATE_UPDATE_DOMAIN,
+		GenesisHash:  genesis_hash,
+		AppID:        app_id,
+		AssetID:      asset_id,
+		AliceBalance: alice_balance,
+		BobBalance:   bob_balance,
+		Timestamp:    int64(timestamp),
+		HTLCs:        htlcs,
+	}
+	totalDeposit, fee, err := NewChannelClient(algod_client, app_id, sender_account).Withdraw(state, alice_signature, bob_signature, withdraw_amount)
+	if err != nil {
+		return fee, err
+	}
+	fmt.Printf("Withdrew %d from channel %d, total deposit: %d\n", withdraw_amount, app_id, totalDeposit)
+	return fee, nil
+}
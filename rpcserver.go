@@ -2,15 +2,13 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/binary"
 	"fmt"
-	"log"
-	"os"
+	"sort"
 	"strconv"
 	"time"
 
-	"github.com/algorand/go-algorand-sdk/v2/crypto"
-	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
 	"github.com/dancodery/algorand-state-channels/asrpc"
 	"github.com/dancodery/algorand-state-channels/payment"
 	"github.com/dancodery/algorand-state-channels/payment/testing"
@@ -35,33 +33,29 @@ func newRpcServer(s *server) *rpcServer {
 func (r *rpcServer) Reset(ctx context.Context, in *asrpc.ResetRequest) (*asrpc.ResetResponse, error) {
 	timestamp_start := timestamppb.Now()
 
+	// the channel states are needed to close the channels, only throwaway accounts may forget them
+	if !r.server.dev_mode {
+		return nil, fmt.Errorf("reset is only allowed in dev mode")
+	}
+
+	r.server.mu.Lock()
 	r.server.payment_channels_onchain_states = make(map[string]paymentChannelInfo)
 	r.server.payment_channels_offchain_states_log = make(map[string]map[int64]paymentChannelOffChainState)
+	r.server.htlc_preimages = make(map[[32]byte][]byte)
+	r.server.forwarded_htlcs = make(map[[32]byte]forwardedHTLC)
+	r.server.pending_payments = make(map[[32]byte]*pendingPayment)
+	r.server.invoices = make(map[[32]byte]*invoice)
+	r.server.pending_capacity_changes = make(map[string]pendingCapacityChange)
+	r.server.mu.Unlock()
 	r.server.algod_client = testing.GetAlgodClient()
 
-	// new: generate account from seed
-	seed_phrase := os.Getenv("SEED_PHRASE")
-
-	if seed_phrase == "" {
-		r.server.algo_account = crypto.GenerateAccount()
-	} else {
-		private_key, err := mnemonic.ToPrivateKey(seed_phrase)
-		if err != nil {
-			log.Fatalf("failed to generate account from seed: %v\n", err)
-			return nil, err
-		}
-		r.server.algo_account, err = crypto.AccountFromPrivateKey(private_key)
-		if err != nil {
-			log.Fatalf("failed to generate account from seed: %v\n", err)
-			return nil, err
-		}
-	}
-
 	fmt.Printf("\nReset executed\n")
-	fmt.Printf("My node ALGO address is: %v\n", r.server.algo_account.Address.String())
 
-	// fund account
-	testing.FundAccount(r.server.algod_client, r.server.algo_account.Address.String(), 10_000_000_000)
+	// a new throwaway account
+	err := r.server.loadDevAccount()
+	if err != nil {
+		return nil, err
+	}
 
 	timestamp_end := timestamppb.Now()
 
@@ -77,7 +71,7 @@ func (r *rpcServer) Reset(ctx context.Context, in *asrpc.ResetRequest) (*asrpc.R
 func (r *rpcServer) GetInfo(ctx context.Context, in *asrpc.GetInfoRequest) (*asrpc.GetInfoResponse, error) {
 	timestamp_start := timestamppb.Now()
 
-	algo_address := r.server.algo_account.Address.String()
+	algo_address := r.server.account().Address().String()
 	algo_balance, err := r.server.getAlgoBalance(algo_address)
 	if err != nil {
 		return nil, err
@@ -99,84 +93,126 @@ func (r *rpcServer) GetInfo(ctx context.Context, in *asrpc.GetInfoRequest) (*asr
 
 func (r *rpcServer) OpenChannel(ctx context.Context, in *asrpc.OpenChannelRequest) (*asrpc.OpenChannelResponse, error) {
 	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
 
-	// 1. Create payment app
-	appID := payment.CreatePaymentApp(
+	// 1. Create and fund payment app in one atomic group, with algos or with the asset of the channel
+	appID, blockchain_fee, err := payment.CreateAndFundPaymentApp(
 		r.server.algod_client,
-		r.server.algo_account,
+		payment.WithPhaseRecorder(r.server.account(), recorder),
 		in.PartnerNode.AlgoAddress,
 		in.PenaltyReserve,
-		in.DisputeWindow)
-
-	// 2. Fund payment app
-	payment.SetupPaymentApp(
-		r.server.algod_client,
-		appID,
-		r.server.algo_account,
+		in.DisputeWindow,
+		in.AssetId,
 		in.FundingAmount)
+	if err != nil {
+		fmt.Printf("Error creating payment channel app: %v\n", err)
+		return nil, err
+	}
+
+	r.server.recordChannelFee(appID, in.PartnerNode.AlgoAddress, blockchain_fee)
 
 	fmt.Printf("\nCreated payment channel app with app_id: %v and funding amount: %v\n", appID, in.FundingAmount)
+	if in.AssetId != 0 {
+		fmt.Printf("The payment channel is denominated in asset %v\n", in.AssetId)
+	}
 
-	// 3. send notification to partner node
-	partner_response, err := sendRequest(in.PartnerNode.Host, P2PRequest{Command: "open_channel_request", Args: [][]byte{[]byte(strconv.Itoa(int(appID)))}})
+	// 2. send notification to partner node
+	end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	partner_response, err := r.server.sendRequestWithTimeout(in.PartnerNode.Host, P2PRequest{Command: "open_channel_request", Args: [][]byte{[]byte(strconv.Itoa(int(appID)))}}, OPEN_CHANNEL_TIMEOUT)
+	end_round_trip()
+
+	// 3. read partner node's response, refund my deposit if the partner node did not accept the channel
+	if err != nil || partner_response.Message != "approve" {
+		var open_err error
+		switch {
+		case err != nil:
+			fmt.Printf("Error sending open channel request to partner node: %v\n", err)
+			open_err = err
+		case partner_response.Message == "reject":
+			fmt.Printf("Partner node rejected open channel request\n")
+			open_err = fmt.Errorf("partner node rejected open channel request")
+		default:
+			fmt.Printf("Partner node sent invalid response to open channel request\n")
+			open_err = fmt.Errorf("partner node sent invalid response to open channel request")
+		}
+
+		partner_accepted, abort_fee, abort_err := r.server.abortChannelOpen(appID, recorder)
+		blockchain_fee += abort_fee
+		if !partner_accepted {
+			if abort_err != nil {
+				fmt.Printf("Error aborting channel open: %v\n", abort_err)
+				return nil, fmt.Errorf("%v, refunding app_id %d failed: %v", open_err, appID, abort_err)
+			}
+			return nil, open_err
+		}
+		// the partner node accepted on chain but its answer got lost
+		fmt.Printf("Partner node accepted the payment channel on chain\n")
+	}
+
+	// 4. derive my signing key and read the one the partner node registered when accepting
+	my_signer, err := payment.DeriveChannelSigner(payment.WithPhaseRecorder(r.server.account(), recorder), appID)
+	if err != nil {
+		fmt.Printf("Error deriving channel signing key: %v\n", err)
+		return nil, err
+	}
+	app_state, err := payment.ReadChannelAppState(r.server.algod_client, appID)
 	if err != nil {
-		fmt.Printf("Error sending open channel request to partner node: %v\n", err)
+		fmt.Printf("Error reading smart contract from blockchain: %v\n", err)
 		return nil, err
 	}
+	bob_signing_key := app_state.BobSigningKey.String()
 
-	// 4. read partner node's response
-	switch partner_response.Message {
-	case "approve":
-		// save the payment channel on chain state
-		onchain_state := &paymentChannelInfo{
-			app_id:     appID,
-			partner_ip: in.PartnerNode.Host,
+	// save the payment channel on chain state
+	onchain_state := &paymentChannelInfo{
+		app_id:     appID,
+		partner_ip: in.PartnerNode.Host,
 
-			alice_address: r.server.algo_account.Address.String(),
-			bob_address:   in.PartnerNode.AlgoAddress,
+		alice_address: r.server.account().Address().String(),
+		bob_address:   in.PartnerNode.AlgoAddress,
 
-			alice_onchain_balance: in.FundingAmount,
-			bob_onchain_balance:   0,
+		alice_signing_key: my_signer.Address().String(),
+		bob_signing_key:   bob_signing_key,
+		my_signer:         my_signer,
 
-			total_deposit:   in.FundingAmount,
-			penalty_reserve: in.PenaltyReserve,
-			dispute_window:  in.DisputeWindow,
-		}
-		r.server.payment_channels_onchain_states[in.PartnerNode.AlgoAddress] = *onchain_state
+		asset_id: in.AssetId,
 
-		r.server.UpdateWatchtowerState()
+		alice_onchain_balance: in.FundingAmount,
+		bob_onchain_balance:   0,
 
-		// save the payment channel off chain state
-		off_chain_state := &paymentChannelOffChainState{
-			timestamp: time.Now().UnixNano(),
+		total_deposit:   in.FundingAmount,
+		penalty_reserve: in.PenaltyReserve,
+		dispute_window:  in.DisputeWindow,
+	}
+	r.server.mu.Lock()
+	r.server.payment_channels_onchain_states[in.PartnerNode.AlgoAddress] = *onchain_state
+	r.server.mu.Unlock()
 
-			alice_balance: onchain_state.alice_onchain_balance,
-			bob_balance:   onchain_state.bob_onchain_balance,
+	r.server.UpdateWatchtowerState()
 
-			algorand_port: 4161,
-			app_id:        onchain_state.app_id,
-		}
+	// save the payment channel off chain state
+	off_chain_state := &paymentChannelOffChainState{
+		timestamp: time.Now().UnixNano(),
 
-		if r.server.payment_channels_offchain_states_log[in.PartnerNode.AlgoAddress] == nil {
-			r.server.payment_channels_offchain_states_log[in.PartnerNode.AlgoAddress] = make(map[int64]paymentChannelOffChainState)
-		}
-		r.server.payment_channels_offchain_states_log[in.PartnerNode.AlgoAddress][off_chain_state.timestamp] = *off_chain_state
+		alice_balance: onchain_state.alice_onchain_balance,
+		bob_balance:   onchain_state.bob_onchain_balance,
 
-		// print all payment channel states
-		fmt.Printf("All Current Payment Channels: %+v\n\n", r.server.payment_channels_onchain_states)
-	case "reject":
-		fmt.Printf("Partner node rejected open channel request\n")
-		return nil, fmt.Errorf("partner node rejected open channel request")
-	default:
-		fmt.Printf("Partner node sent invalid response to open channel request\n")
-		return nil, fmt.Errorf("partner node sent invalid response to open channel request")
+		genesis_hash: r.server.genesis_hash,
+		app_id:       onchain_state.app_id,
 	}
+	r.server.saveOffChainState(in.PartnerNode.AlgoAddress, *off_chain_state)
+
+	// print all payment channel states
+	r.server.mu.Lock()
+	fmt.Printf("All Current Payment Channels: %+v\n\n", r.server.payment_channels_onchain_states)
+	r.server.mu.Unlock()
 
 	timestamp_end := timestamppb.Now()
 
 	runtime_recording := &asrpc.RuntimeRecording{
 		TimestampStart: timestamp_start,
 		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
 	}
 
 	return &asrpc.OpenChannelResponse{
@@ -187,40 +223,35 @@ func (r *rpcServer) OpenChannel(ctx context.Context, in *asrpc.OpenChannelReques
 
 func (r *rpcServer) Pay(ctx context.Context, in *asrpc.PayRequest) (*asrpc.PayResponse, error) {
 	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
 
-	// 1. get on chain state
-	onchain_state, ok := r.server.payment_channels_onchain_states[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
+	if len(in.PaymentHash) != 0 && len(in.PaymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be 32 bytes")
 	}
-
-	// 2. retrieve old balances
-	payment_log, ok := r.server.payment_channels_offchain_states_log[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
+	if len(in.Memo) > MAX_MEMO_LENGTH {
+		return nil, fmt.Errorf("memo must not be longer than %d bytes", MAX_MEMO_LENGTH)
 	}
-	latestOffChainState, err := getLatestOffChainState(payment_log)
+
+	// 1. get on chain state and old balances, the channel stays locked until the new state is saved
+	unlock_channel := r.server.lockChannel(in.AlgoAddress)
+	defer unlock_channel()
+	onchain_state, latestOffChainState, me_alice, err := r.server.loadChannelState(in.AlgoAddress)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return nil, err
 	}
 
-	var me_alice bool
 	var my_balance uint64
 	var counterparty_balance uint64
-	if onchain_state.alice_address == r.server.algo_account.Address.String() {
-		me_alice = true
+	if me_alice {
 		my_balance = latestOffChainState.alice_balance
 		counterparty_balance = latestOffChainState.bob_balance
 	} else {
-		me_alice = false
 		my_balance = latestOffChainState.bob_balance
 		counterparty_balance = latestOffChainState.alice_balance
 	}
 
-	// 3. calculate new balances and timestamp
+	// 2. calculate new balances and timestamp
 	new_my_balance := my_balance - in.Amount
 	new_counterparty_balance := counterparty_balance + in.Amount
 	timestamp_now := time.Now().UnixNano()
@@ -238,21 +269,33 @@ func (r *rpcServer) Pay(ctx context.Context, in *asrpc.PayRequest) (*asrpc.PayRe
 		new_bob_balance = new_my_balance
 	}
 
-	// 4. sign new state
+	// 3. sign new state
 
 	var my_signature []byte
 	my_signature, err = payment.SignState(
 		onchain_state.app_id,
-		r.server.algo_account,
+		onchain_state.asset_id,
+		payment.WithPhaseRecorder(onchain_state.my_signer, recorder),
 		new_alice_balance,
 		new_bob_balance,
-		4161,
-		timestamp_now)
+		r.server.genesis_hash,
+		timestamp_now,
+		latestOffChainState.htlcs)
 	if err != nil {
 		fmt.Printf("Error signing state: %v\n", err)
 	}
 
-	// 5. send new state to partner node
+	// the memo is authenticated separately, it is not part of the on chain state
+	var memo_signature []byte
+	if in.Memo != "" {
+		memo_signature, err = payment.SignMemo(onchain_state.app_id, payment.WithPhaseRecorder(onchain_state.my_signer, recorder), r.server.genesis_hash, timestamp_now, []byte(in.Memo))
+		if err != nil {
+			fmt.Printf("Error signing memo: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// 4. send new state to partner node
 	newAliceBalanceBytes := make([]byte, 8)
 	newBobBalanceBytes := make([]byte, 8)
 
@@ -262,94 +305,105 @@ func (r *rpcServer) Pay(ctx context.Context, in *asrpc.PayRequest) (*asrpc.PayRe
 	timestampBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(timestampBytes, uint64(timestamp_now))
 
-	server_response, err := sendRequest(onchain_state.partner_ip, P2PRequest{Command: "pay_request", Args: [][]byte{
-		[]byte(r.server.algo_account.Address.String()), // 1. my address
-		newAliceBalanceBytes,                           // 2. my new balance
-		newBobBalanceBytes,                             // 3. partner's new balance
-		timestampBytes,                                 // 4. timestamp
-		my_signature,                                   // 5. my signature
+	end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	server_response, err := r.server.sendRequest(onchain_state.partner_ip, P2PRequest{Command: "pay_request", Args: [][]byte{
+		[]byte(r.server.account().Address().String()), // 1. my address
+		newAliceBalanceBytes,                          // 2. my new balance
+		newBobBalanceBytes,                            // 3. partner's new balance
+		timestampBytes,                                // 4. timestamp
+		my_signature,                                  // 5. my signature
+		in.PaymentHash,                                // 6. payment hash of the paid invoice
+		[]byte(in.Memo),                               // 7. memo
+		memo_signature,                                // 8. my signature of the memo
 	}})
+	end_round_trip()
 	if err != nil {
 		fmt.Printf("Error sending pay request to partner node: %v\n", err)
 		return nil, err
 	}
 
-	// 6. read partner node's response
+	// 5. read partner node's response
 	fmt.Printf("Payment partner node's response: %v\n", server_response.Message)
 	if server_response.Message != "approve" {
 		fmt.Printf("Partner node rejected pay request\n")
 		return nil, fmt.Errorf("partner node rejected pay request")
 	}
 
-	// 7. verify partner node's signature
+	// 6. verify partner node's signature
 	partner_signature := server_response.Data[0]
 
+	end_verification := recorder.Start(payment.PHASE_PARTNER_VERIFICATION)
 	partner_verified := payment.VerifyState(
 		onchain_state.app_id,
+		onchain_state.asset_id,
 		new_alice_balance,
 		new_bob_balance,
-		4161,
+		r.server.genesis_hash,
 		partner_signature,
-		in.AlgoAddress,
-		timestamp_now)
+		onchain_state.signingKey(in.AlgoAddress),
+		timestamp_now,
+		latestOffChainState.htlcs)
+	end_verification()
 	if !partner_verified {
 		fmt.Printf("Partner node's signature is invalid\n")
 		return nil, fmt.Errorf("partner node's signature is invalid")
 	}
 
-	// 8. save new state
+	// 7. save new state
 	off_chain_state := &paymentChannelOffChainState{
 		timestamp: timestamp_now,
 
 		alice_balance: new_alice_balance,
 		bob_balance:   new_bob_balance,
+		htlcs:         latestOffChainState.htlcs,
+
+		memo:           in.Memo,
+		memo_signature: memo_signature,
 
 		alice_signature: my_signature,
 		bob_signature:   partner_signature,
 
-		algorand_port: 4161,
-		app_id:        onchain_state.app_id,
+		genesis_hash: r.server.genesis_hash,
+		app_id:       onchain_state.app_id,
 	}
 
-	if r.server.payment_channels_offchain_states_log[in.AlgoAddress] == nil {
-		r.server.payment_channels_offchain_states_log[in.AlgoAddress] = make(map[int64]paymentChannelOffChainState)
-	}
-	r.server.payment_channels_offchain_states_log[in.AlgoAddress][timestamp_now] = *off_chain_state
+	r.server.saveOffChainState(in.AlgoAddress, *off_chain_state)
 
-	// 9. update on chain state
+	// 8. update on chain state
 	fmt.Printf("Processed payment of %v microalgos\n", in.Amount)
-	fmt.Printf("Alice new balance: %v\n", r.server.payment_channels_offchain_states_log[in.AlgoAddress][timestamp_now].alice_balance)
-	fmt.Printf("Bob new balance: %v\n\n", r.server.payment_channels_offchain_states_log[in.AlgoAddress][timestamp_now].bob_balance)
+	fmt.Printf("Alice new balance: %v\n", off_chain_state.alice_balance)
+	fmt.Printf("Bob new balance: %v\n\n", off_chain_state.bob_balance)
+
+	// 9. the partner node proves that it received the payment for its invoice
+	var preimage []byte
+	if len(in.PaymentHash) > 0 {
+		if len(server_response.Data) < 2 || payment.HashPreimage(server_response.Data[1]) != [32]byte(in.PaymentHash) {
+			fmt.Printf("Partner node did not reveal the preimage of the invoice\n")
+			return nil, fmt.Errorf("partner node did not reveal the preimage of the invoice")
+		}
+		preimage = server_response.Data[1]
+	}
 
 	timestamp_end := timestamppb.Now()
 
 	runtime_recording := &asrpc.RuntimeRecording{
 		TimestampStart: timestamp_start,
 		TimestampEnd:   timestamp_end,
+		PhaseSpans:     phaseSpans(recorder),
 	}
 
 	return &asrpc.PayResponse{
 		RuntimeRecording: runtime_recording,
+		Preimage:         preimage,
 	}, nil
 }
 
 func (r *rpcServer) InitiateCloseChannel(ctx context.Context, in *asrpc.InitiateCloseChannelRequest) (*asrpc.InitiateCloseChannelResponse, error) {
 	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
 
-	// 1. get on chain state
-	onchain_state, ok := r.server.payment_channels_onchain_states[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
-	}
-
-	// 2. retrieve latest off chain state
-	payment_log, ok := r.server.payment_channels_offchain_states_log[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
-	}
-	latestOffChainState, err := getLatestOffChainState(payment_log)
+	// 1. get on chain state and latest off chain state
+	onchain_state, latestOffChainState, _, err := r.server.loadChannelState(in.AlgoAddress)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return nil, err
@@ -361,16 +415,22 @@ func (r *rpcServer) InitiateCloseChannel(ctx context.Context, in *asrpc.Initiate
 		return nil, fmt.Errorf("not enough balance to close channel")
 	}
 
-	payment.InitiateCloseChannel(
+	blockchain_fee, err := payment.InitiateCloseChannel(
 		r.server.algod_client,
-		r.server.algo_account,
-		4161,
+		payment.WithPhaseRecorder(r.server.account(), recorder),
+		r.server.genesis_hash,
 		onchain_state.app_id,
 		latestOffChainState.alice_balance,
 		latestOffChainState.bob_balance,
 		uint64(latestOffChainState.timestamp),
+		latestOffChainState.htlcs,
 		latestOffChainState.alice_signature,
 		latestOffChainState.bob_signature)
+	if err != nil {
+		fmt.Printf("Error initiating channel closure: %v\n", err)
+		return nil, err
+	}
+	r.server.recordChannelFee(onchain_state.app_id, in.AlgoAddress, blockchain_fee)
 
 	fmt.Printf("Initiated channel closure for app_id: %v\n\n", onchain_state.app_id)
 
@@ -379,6 +439,8 @@ func (r *rpcServer) InitiateCloseChannel(ctx context.Context, in *asrpc.Initiate
 	runtime_recording := &asrpc.RuntimeRecording{
 		TimestampStart: timestamp_start,
 		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
 	}
 	return &asrpc.InitiateCloseChannelResponse{
 		RuntimeRecording: runtime_recording,
@@ -387,37 +449,57 @@ func (r *rpcServer) InitiateCloseChannel(ctx context.Context, in *asrpc.Initiate
 
 func (r *rpcServer) FinalizeCloseChannel(ctx context.Context, in *asrpc.FinalizeCloseChannelRequest) (*asrpc.FinalizeCloseChannelResponse, error) {
 	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
 
 	// 1. get on chain state
-	onchain_state, ok := r.server.payment_channels_onchain_states[in.AlgoAddress]
+	onchain_state, ok := r.server.getOnChainState(in.AlgoAddress)
 	if !ok {
 		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
 		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
 	}
 
 	var counterparty_address string
-	if r.server.algo_account.Address.String() == onchain_state.alice_address {
+	if r.server.account().Address().String() == onchain_state.alice_address {
 		counterparty_address = onchain_state.bob_address
 	} else {
 		counterparty_address = onchain_state.alice_address
 	}
 
-	// 2. call finalize close channel
-	payment.FinalizeCloseChannel(
+	// 2. look up the pending htlcs of the state committed on chain
+	app_state, err := payment.ReadChannelAppState(r.server.algod_client, onchain_state.app_id)
+	if err != nil {
+		fmt.Printf("Error reading smart contract from blockchain: %v\n", err)
+		return nil, err
+	}
+	onchain_htlcs := r.server.getOnChainHTLCs(in.AlgoAddress, int64(app_state.LatestTimestamp))
+
+	// 3. call finalize close channel
+	blockchain_fee, err := payment.FinalizeCloseChannel(
 		r.server.algod_client,
-		r.server.algo_account,
+		payment.WithPhaseRecorder(r.server.account(), recorder),
 		counterparty_address,
-		onchain_state.app_id)
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		onchain_htlcs)
+	if err != nil {
+		fmt.Printf("Error finalizing channel closure: %v\n", err)
+		return nil, err
+	}
+	r.server.recordChannelFee(onchain_state.app_id, in.AlgoAddress, blockchain_fee)
 	fmt.Printf("Finalized channel closure for app_id: %v\n\n", onchain_state.app_id)
 
-	// 3. delete on chain state
+	// 4. delete on chain state
+	r.server.mu.Lock()
 	delete(r.server.payment_channels_onchain_states, in.AlgoAddress)
+	r.server.mu.Unlock()
 
 	timestamp_end := timestamppb.Now()
 
 	runtime_recording := &asrpc.RuntimeRecording{
 		TimestampStart: timestamp_start,
 		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
 	}
 	return &asrpc.FinalizeCloseChannelResponse{
 		RuntimeRecording: runtime_recording,
@@ -426,87 +508,81 @@ func (r *rpcServer) FinalizeCloseChannel(ctx context.Context, in *asrpc.Finalize
 
 func (r *rpcServer) CooperativeCloseChannel(ctx context.Context, in *asrpc.CooperativeCloseChannelRequest) (*asrpc.CooperativeCloseChannelResponse, error) {
 	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
 
-	// 1. get on chain state
-	onchain_state, ok := r.server.payment_channels_onchain_states[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
-	}
-
-	// 2. retrieve latest off chain state
-	payment_log, ok := r.server.payment_channels_offchain_states_log[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
-	}
-	latestOffChainState, err := getLatestOffChainState(payment_log)
+	// 1. get on chain state and latest off chain state, no update may follow the state I close with
+	unlock_channel := r.server.lockChannel(in.AlgoAddress)
+	defer unlock_channel()
+	onchain_state, latestOffChainState, is_alice, err := r.server.loadChannelState(in.AlgoAddress)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return nil, err
 	}
 
-	// 3. check if alice or bob have not enough balance to close channel
+	// 2. check if alice or bob have not enough balance to close channel
 	if latestOffChainState.alice_balance < 1000 || latestOffChainState.bob_balance < 1000 {
 		fmt.Printf("Error: not enough balance to close channel\n\n")
 		return nil, fmt.Errorf("not enough balance to close channel")
 	}
+	if len(latestOffChainState.htlcs) > 0 {
+		fmt.Printf("Error: channel has pending htlcs\n\n")
+		return nil, fmt.Errorf("channel has %d pending htlcs, settle or fail them first", len(latestOffChainState.htlcs))
+	}
 
-	// 4. sign cooperative close state
+	// 3. sign cooperative close state
 	var my_signature []byte
 	my_signature, err = payment.SignClose(
 		onchain_state.app_id,
-		r.server.algo_account,
+		onchain_state.asset_id,
+		payment.WithPhaseRecorder(onchain_state.my_signer, recorder),
 		latestOffChainState.alice_balance,
 		latestOffChainState.bob_balance,
-		4161,
+		r.server.genesis_hash,
 		latestOffChainState.timestamp,
 	)
 	if err != nil {
 		fmt.Printf("Error signing state: %v\n", err)
 	}
 
-	// 5. send cooperative close request to partner node
-	server_response, err := sendRequest(onchain_state.partner_ip, P2PRequest{Command: "close_channel_request", Args: [][]byte{
-		[]byte(r.server.algo_account.Address.String()), // 1. my address
+	// 4. send cooperative close request to partner node
+	end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	server_response, err := r.server.sendRequest(onchain_state.partner_ip, P2PRequest{Command: "close_channel_request", Args: [][]byte{
+		[]byte(r.server.account().Address().String()), // 1. my address
 		my_signature, // 2. my signature
 	}})
+	end_round_trip()
 	if err != nil {
 		fmt.Printf("Error sending pay request to partner node: %v\n", err)
 		return nil, err
 	}
 
-	// 6. read partner node's response
+	// 5. read partner node's response
 	fmt.Printf("Payment partner node's response: %v\n", server_response.Message)
 	if server_response.Message != "approve" {
 		fmt.Printf("Partner node rejected pay request\n")
 		return nil, fmt.Errorf("partner node rejected pay request")
 	}
 
-	// 7. verify partner node's signature
+	// 6. verify partner node's signature
 	partner_signature := server_response.Data[0]
 
+	end_verification := recorder.Start(payment.PHASE_PARTNER_VERIFICATION)
 	partner_verified := payment.VerifyClose(
 		onchain_state.app_id,
+		onchain_state.asset_id,
 		latestOffChainState.alice_balance,
 		latestOffChainState.bob_balance,
-		4161,
+		r.server.genesis_hash,
 		partner_signature,
-		in.AlgoAddress,
+		onchain_state.signingKey(in.AlgoAddress),
 		latestOffChainState.timestamp,
 	)
+	end_verification()
 	if !partner_verified {
 		fmt.Printf("Error: partner node's signature is not valid\n")
 		return nil, fmt.Errorf("partner node's signature is not valid")
 	}
 
-	var is_alice bool
-	if onchain_state.alice_address == r.server.algo_account.Address.String() {
-		is_alice = true
-	} else {
-		is_alice = false
-	}
-
 	var alice_signature []byte
 	var bob_signature []byte
 	if is_alice {
@@ -517,29 +593,39 @@ func (r *rpcServer) CooperativeCloseChannel(ctx context.Context, in *asrpc.Coope
 		bob_signature = my_signature
 	}
 
-	// 8. call cooperative close channel
-	payment.CooperativeCloseChannel(
+	// 7. call cooperative close channel
+	blockchain_fee, err := payment.CooperativeCloseChannel(
 		r.server.algod_client,
-		r.server.algo_account,
+		payment.WithPhaseRecorder(r.server.account(), recorder),
 		in.AlgoAddress,
-		4161,
+		r.server.genesis_hash,
 		onchain_state.app_id,
 		latestOffChainState.alice_balance,
 		latestOffChainState.bob_balance,
 		uint64(latestOffChainState.timestamp),
+		onchain_state.asset_id,
 		alice_signature,
 		bob_signature)
+	if err != nil {
+		fmt.Printf("Error closing channel cooperatively: %v\n", err)
+		return nil, err
+	}
+	r.server.recordChannelFee(onchain_state.app_id, in.AlgoAddress, blockchain_fee)
 
 	fmt.Printf("Cooperative channel closure for app_id: %v\n\n", onchain_state.app_id)
 
-	// 9. delete payment channel from on chain state
+	// 8. delete payment channel from on chain state
+	r.server.mu.Lock()
 	delete(r.server.payment_channels_onchain_states, in.AlgoAddress)
+	r.server.mu.Unlock()
 
 	timestamp_end := timestamppb.Now()
 
 	runtime_recording := &asrpc.RuntimeRecording{
 		TimestampStart: timestamp_start,
 		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
 	}
 	return &asrpc.CooperativeCloseChannelResponse{
 		RuntimeRecording: runtime_recording,
@@ -548,27 +634,27 @@ func (r *rpcServer) CooperativeCloseChannel(ctx context.Context, in *asrpc.Coope
 
 func (r *rpcServer) TryToCheat(ctx context.Context, in *asrpc.TryToCheatRequest) (*asrpc.TryToCheatResponse, error) {
 	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
 
 	// 1. get on chain state
+	r.server.mu.Lock()
 	onchain_state, ok := r.server.payment_channels_onchain_states[in.AlgoAddress]
-	if !ok {
+	payment_log, log_ok := r.server.payment_channels_offchain_states_log[in.AlgoAddress]
+	if !ok || !log_ok {
+		r.server.mu.Unlock()
 		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
 		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
 	}
 
 	// 2. retrieve off chain state with highest balance
-	payment_log, ok := r.server.payment_channels_offchain_states_log[in.AlgoAddress]
-	if !ok {
-		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
-		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
-	}
 	var is_alice bool
-	if onchain_state.alice_address == r.server.algo_account.Address.String() {
+	if onchain_state.alice_address == r.server.account().Address().String() {
 		is_alice = true
 	} else {
 		is_alice = false
 	}
 	highesBalanceOffChainState, err := getHighestBalanceOffChainState(is_alice, payment_log)
+	r.server.mu.Unlock()
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return nil, err
@@ -581,16 +667,22 @@ func (r *rpcServer) TryToCheat(ctx context.Context, in *asrpc.TryToCheatRequest)
 	}
 
 	// 4. intiate close channel
-	payment.InitiateCloseChannel(
+	blockchain_fee, err := payment.InitiateCloseChannel(
 		r.server.algod_client,
-		r.server.algo_account,
-		4161,
+		payment.WithPhaseRecorder(r.server.account(), recorder),
+		r.server.genesis_hash,
 		onchain_state.app_id,
 		highesBalanceOffChainState.alice_balance,
 		highesBalanceOffChainState.bob_balance,
 		uint64(highesBalanceOffChainState.timestamp),
+		highesBalanceOffChainState.htlcs,
 		highesBalanceOffChainState.alice_signature,
 		highesBalanceOffChainState.bob_signature)
+	if err != nil {
+		fmt.Printf("Error initiating channel closure: %v\n", err)
+		return nil, err
+	}
+	r.server.recordChannelFee(onchain_state.app_id, in.AlgoAddress, blockchain_fee)
 
 	fmt.Printf("Try to cheat for app_id: %v\n", onchain_state.app_id)
 	fmt.Printf("Alice cheating balance: %v\n", highesBalanceOffChainState.alice_balance)
@@ -601,8 +693,548 @@ func (r *rpcServer) TryToCheat(ctx context.Context, in *asrpc.TryToCheatRequest)
 	runtime_recording := &asrpc.RuntimeRecording{
 		TimestampStart: timestamp_start,
 		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
 	}
 	return &asrpc.TryToCheatResponse{
 		RuntimeRecording: runtime_recording,
 	}, nil
 }
+
+func (r *rpcServer) AddHTLC(ctx context.Context, in *asrpc.AddHTLCRequest) (*asrpc.AddHTLCResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	// 1. get on chain state
+	onchain_state, ok := r.server.getOnChainState(in.AlgoAddress)
+	if !ok {
+		fmt.Printf("Error: payment channel with partner node %v does not exist\n", in.AlgoAddress)
+		return nil, fmt.Errorf("payment channel with partner node %v does not exist", in.AlgoAddress)
+	}
+	if len(in.PaymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be 32 bytes")
+	}
+	if in.Expiry <= onchain_state.dispute_window {
+		return nil, fmt.Errorf("expiry must be larger than the dispute window of %d rounds", onchain_state.dispute_window)
+	}
+
+	// 2. compute the timelock
+	current_round, err := r.server.getCurrentRound()
+	if err != nil {
+		return nil, err
+	}
+	var hashlock [32]byte
+	copy(hashlock[:], in.PaymentHash)
+
+	// 3. lock the amount into a new htlc
+	htlc, err := r.server.addOutgoingHTLC(in.AlgoAddress, in.Amount, hashlock, current_round+in.Expiry, nil, recorder)
+	if err != nil {
+		fmt.Printf("Error adding htlc: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("Added htlc of %v microalgos with hashlock %x, expiring at round %v\n\n", htlc.Amount, htlc.Hashlock, htlc.Timelock)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.AddHTLCResponse{
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) SettleHTLC(ctx context.Context, in *asrpc.SettleHTLCRequest) (*asrpc.SettleHTLCResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	htlc, err := r.server.settleIncomingHTLC(in.AlgoAddress, in.Preimage, recorder)
+	if err != nil {
+		fmt.Printf("Error settling htlc: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("Settled htlc of %v microalgos with hashlock %x\n\n", htlc.Amount, htlc.Hashlock)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.SettleHTLCResponse{
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) FailHTLC(ctx context.Context, in *asrpc.FailHTLCRequest) (*asrpc.FailHTLCResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	if len(in.PaymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be 32 bytes")
+	}
+	var hashlock [32]byte
+	copy(hashlock[:], in.PaymentHash)
+
+	htlc, err := r.server.failHTLC(in.AlgoAddress, hashlock, recorder)
+	if err != nil {
+		fmt.Printf("Error failing htlc: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("Failed htlc of %v microalgos with hashlock %x\n\n", htlc.Amount, htlc.Hashlock)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.FailHTLCResponse{
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) SendPayment(ctx context.Context, in *asrpc.SendPaymentRequest) (*asrpc.SendPaymentResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	// 1. check the route, onions are decrypted with the key of the node account
+	if !r.server.holdsAccountKey() {
+		return nil, fmt.Errorf("multi-hop payments need the key of the node account, the kmd and authorized signer backends do not hand it out")
+	}
+	hops := len(in.Route)
+	if hops == 0 || in.Route[hops-1].AlgoAddress != in.DestinationAddress {
+		return nil, fmt.Errorf("route must end at the destination %v", in.DestinationAddress)
+	}
+	first_hop := in.Route[0].AlgoAddress
+	if _, ok := r.server.getOnChainState(first_hop); !ok {
+		fmt.Printf("Error: payment channel with partner node %v does not exist\n", first_hop)
+		return nil, fmt.Errorf("payment channel with partner node %v does not exist", first_hop)
+	}
+	if in.Amount == 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	// 2. use the given payment hash, or pay spontaneously with a preimage only the destination learns
+	var hashlock [32]byte
+	var keysend_preimage []byte
+	if len(in.PaymentHash) == 0 {
+		keysend_preimage = make([]byte, 32)
+		_, err := rand.Read(keysend_preimage)
+		if err != nil {
+			return nil, err
+		}
+		hashlock = payment.HashPreimage(keysend_preimage)
+	} else if len(in.PaymentHash) == 32 {
+		copy(hashlock[:], in.PaymentHash)
+	} else {
+		return nil, fmt.Errorf("payment hash must be 32 bytes")
+	}
+	r.server.mu.Lock()
+	_, ok := r.server.pending_payments[hashlock]
+	r.server.mu.Unlock()
+	if ok {
+		return nil, fmt.Errorf("payment with hash %x is already pending", hashlock)
+	}
+
+	// 3. compute amounts and timelocks backwards from the destination, every intermediate hop adds its fee and timelock delta
+	current_round, err := r.server.getCurrentRound()
+	if err != nil {
+		return nil, err
+	}
+	final_expiry := in.FinalExpiry
+	if final_expiry == 0 {
+		final_expiry = 2 * DEFAULT_TIMELOCK_DELTA
+	}
+	htlc_amounts := make([]uint64, hops)   // amount of the htlc arriving at each hop
+	htlc_timelocks := make([]uint64, hops) // timelock of the htlc arriving at each hop
+	htlc_amounts[hops-1] = in.Amount
+	htlc_timelocks[hops-1] = current_round + final_expiry
+	for i := hops - 2; i >= 0; i-- {
+		end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+		policy, err := r.server.queryForwardingPolicy(in.Route[i].Host)
+		end_round_trip()
+		if err != nil {
+			fmt.Printf("Error querying forwarding policy: %v\n", err)
+			return nil, err
+		}
+		htlc_amounts[i] = htlc_amounts[i+1] + policy.fee(htlc_amounts[i+1])
+		htlc_timelocks[i] = htlc_timelocks[i+1] + policy.timelock_delta
+	}
+
+	// 4. build the onion with one payload per hop
+	hop_addresses := make([]string, hops)
+	payloads := make([]*hopPayload, hops)
+	for i := 0; i < hops; i++ {
+		hop_addresses[i] = in.Route[i].AlgoAddress
+		if i == hops-1 {
+			payloads[i] = &hopPayload{
+				amount:   in.Amount,
+				timelock: htlc_timelocks[i],
+				preimage: keysend_preimage,
+			}
+		} else {
+			payloads[i] = &hopPayload{
+				next_address: in.Route[i+1].AlgoAddress,
+				amount:       htlc_amounts[i+1],
+				timelock:     htlc_timelocks[i+1],
+			}
+		}
+	}
+	onion, err := buildOnion(hop_addresses, payloads)
+	if err != nil {
+		fmt.Printf("Error building onion: %v\n", err)
+		return nil, err
+	}
+
+	// 5. offer the first htlc and wait until it is settled or failed
+	pending := &pendingPayment{
+		first_hop: first_hop,
+		result:    make(chan []byte, 1),
+	}
+	r.server.mu.Lock()
+	r.server.pending_payments[hashlock] = pending
+	r.server.mu.Unlock()
+	_, err = r.server.addOutgoingHTLC(first_hop, htlc_amounts[0], hashlock, htlc_timelocks[0], onion, recorder)
+	if err != nil {
+		r.server.mu.Lock()
+		delete(r.server.pending_payments, hashlock)
+		r.server.mu.Unlock()
+		fmt.Printf("Error adding htlc: %v\n", err)
+		return nil, err
+	}
+
+	var preimage []byte
+	select {
+	case preimage = <-pending.result:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("payment with hash %x is still pending", hashlock)
+	}
+	if preimage == nil {
+		fmt.Printf("Multi-hop payment with hash %x failed\n", hashlock)
+		return nil, fmt.Errorf("payment with hash %x failed", hashlock)
+	}
+
+	fee := htlc_amounts[0] - in.Amount
+	fmt.Printf("Sent %v microalgos to %v over %d hops, paying %v microalgos in fees\n\n", in.Amount, in.DestinationAddress, hops, fee)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.SendPaymentResponse{
+		Preimage:         preimage,
+		Fee:              fee,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) AddInvoice(ctx context.Context, in *asrpc.AddInvoiceRequest) (*asrpc.AddInvoiceResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	// invoices are signed with the key of the node account
+	if !r.server.holdsAccountKey() {
+		return nil, fmt.Errorf("invoices need the key of the node account, the kmd and authorized signer backends do not hand it out")
+	}
+	if in.Amount == 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if len(in.Memo) > MAX_MEMO_LENGTH {
+		return nil, fmt.Errorf("memo must not be longer than %d bytes", MAX_MEMO_LENGTH)
+	}
+	new_invoice, err := r.server.addInvoice(in.Amount, in.Memo, in.Expiry)
+	if err != nil {
+		fmt.Printf("Error adding invoice: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("Added invoice of %v microalgos with payment hash %x\n\n", new_invoice.amount, new_invoice.payment_hash)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	return &asrpc.AddInvoiceResponse{
+		PaymentHash:      new_invoice.payment_hash[:],
+		PaymentRequest:   new_invoice.payment_request,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) LookupInvoice(ctx context.Context, in *asrpc.LookupInvoiceRequest) (*asrpc.LookupInvoiceResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	if len(in.PaymentHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be 32 bytes")
+	}
+	r.server.mu.Lock()
+	defer r.server.mu.Unlock()
+	existing_invoice, ok := r.server.invoices[[32]byte(in.PaymentHash)]
+	if !ok {
+		return nil, fmt.Errorf("no invoice with payment hash %x", in.PaymentHash)
+	}
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	return &asrpc.LookupInvoiceResponse{
+		Invoice:          existing_invoice.toRPC(),
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) ListInvoices(ctx context.Context, in *asrpc.ListInvoicesRequest) (*asrpc.ListInvoicesResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	r.server.mu.Lock()
+	invoices := make([]*asrpc.Invoice, 0, len(r.server.invoices))
+	for _, existing_invoice := range r.server.invoices {
+		invoices = append(invoices, existing_invoice.toRPC())
+	}
+	r.server.mu.Unlock()
+	sort.Slice(invoices, func(i, j int) bool {
+		return invoices[i].CreationDate.AsTime().Before(invoices[j].CreationDate.AsTime())
+	})
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	return &asrpc.ListInvoicesResponse{
+		Invoices:         invoices,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) PayInvoice(ctx context.Context, in *asrpc.PayInvoiceRequest) (*asrpc.PayInvoiceResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	// 1. decode and verify the payment request
+	payment_request, err := decodePaymentRequest(in.PaymentRequest)
+	if err != nil {
+		fmt.Printf("Error decoding payment request: %v\n", err)
+		return nil, err
+	}
+	if payment_request.expired() {
+		return nil, fmt.Errorf("invoice with payment hash %x expired", payment_request.payment_hash)
+	}
+	fmt.Printf("Paying invoice of %v microalgos to %v, memo: %q\n", payment_request.amount, payment_request.node_address, payment_request.memo)
+
+	// 2. pay over the direct channel, or over the given route
+	var preimage []byte
+	var fee uint64
+	var phase_spans []*asrpc.PhaseSpan
+	if len(in.Route) == 0 {
+		pay_response, err := r.Pay(ctx, &asrpc.PayRequest{
+			AlgoAddress: payment_request.node_address,
+			Amount:      payment_request.amount,
+			PaymentHash: payment_request.payment_hash[:],
+			Memo:        payment_request.memo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		preimage = pay_response.Preimage
+		phase_spans = pay_response.RuntimeRecording.PhaseSpans
+	} else {
+		send_payment_response, err := r.SendPayment(ctx, &asrpc.SendPaymentRequest{
+			DestinationAddress: payment_request.node_address,
+			Amount:             payment_request.amount,
+			Route:              in.Route,
+			PaymentHash:        payment_request.payment_hash[:],
+		})
+		if err != nil {
+			return nil, err
+		}
+		preimage = send_payment_response.Preimage
+		fee = send_payment_response.Fee
+		phase_spans = send_payment_response.RuntimeRecording.PhaseSpans
+	}
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		PhaseSpans:     phase_spans,
+	}
+	return &asrpc.PayInvoiceResponse{
+		Preimage:         preimage,
+		Fee:              fee,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) ListPayments(ctx context.Context, in *asrpc.ListPaymentsRequest) (*asrpc.ListPaymentsResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	payments := make([]*asrpc.Payment, 0)
+	r.server.mu.Lock()
+	for partner_address, payment_log := range r.server.payment_channels_offchain_states_log {
+		if in.AlgoAddress != "" && in.AlgoAddress != partner_address {
+			continue
+		}
+		onchain_state, ok := r.server.payment_channels_onchain_states[partner_address]
+		if !ok {
+			continue
+		}
+		me_alice := onchain_state.alice_address == r.server.account().Address().String()
+		payments = append(payments, getPaymentHistory(partner_address, me_alice, payment_log)...)
+	}
+	r.server.mu.Unlock()
+	sort.Slice(payments, func(i, j int) bool {
+		return payments[i].Timestamp.AsTime().Before(payments[j].Timestamp.AsTime())
+	})
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	return &asrpc.ListPaymentsResponse{
+		Payments:         payments,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) DepositToChannel(ctx context.Context, in *asrpc.DepositToChannelRequest) (*asrpc.DepositToChannelResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	// 1. agree on the new state, deposit on chain and let the partner confirm it
+	blockchain_fee, err := r.server.depositToChannel(in.AlgoAddress, in.Amount, recorder)
+	if err != nil {
+		fmt.Printf("Error depositing to channel: %v\n", err)
+		return nil, err
+	}
+
+	// 2. read the new capacity
+	onchain_state, _ := r.server.getOnChainState(in.AlgoAddress)
+
+	fmt.Printf("Deposited %v microalgos into app_id %v, total deposit is %v\n\n", in.Amount, onchain_state.app_id, onchain_state.total_deposit)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.DepositToChannelResponse{
+		TotalDeposit:     onchain_state.total_deposit,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) WithdrawFromChannel(ctx context.Context, in *asrpc.WithdrawFromChannelRequest) (*asrpc.WithdrawFromChannelResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	// 1. agree on the withdraw authorization, withdraw on chain and let the partner confirm it
+	blockchain_fee, err := r.server.withdrawFromChannel(in.AlgoAddress, in.Amount, recorder)
+	if err != nil {
+		fmt.Printf("Error withdrawing from channel: %v\n", err)
+		return nil, err
+	}
+
+	// 2. read the new capacity
+	onchain_state, _ := r.server.getOnChainState(in.AlgoAddress)
+
+	fmt.Printf("Withdrew %v microalgos from app_id %v, total deposit is %v\n\n", in.Amount, onchain_state.app_id, onchain_state.total_deposit)
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.WithdrawFromChannelResponse{
+		TotalDeposit:     onchain_state.total_deposit,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) AbortChannelOpen(ctx context.Context, in *asrpc.AbortChannelOpenRequest) (*asrpc.AbortChannelOpenResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	// refund my deposit of a channel the partner node has not accepted
+	_, blockchain_fee, err := r.server.abortChannelOpen(in.AppId, recorder)
+	if err != nil {
+		fmt.Printf("Error aborting channel open: %v\n", err)
+		return nil, err
+	}
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.AbortChannelOpenResponse{
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+func (r *rpcServer) CleanupClosedChannels(ctx context.Context, in *asrpc.CleanupClosedChannelsRequest) (*asrpc.CleanupClosedChannelsResponse, error) {
+	timestamp_start := timestamppb.Now()
+	recorder := payment.NewPhaseRecorder()
+
+	// delete my closed channel apps to free their minimum balance
+	deleted_app_ids, blockchain_fee, err := r.server.cleanupClosedChannels(recorder)
+	if err != nil {
+		fmt.Printf("Error cleaning up closed channels: %v\n", err)
+		return nil, err
+	}
+
+	fmt.Printf("Deleted %d closed payment channel apps\n\n", len(deleted_app_ids))
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+		BlockchainFee:  blockchain_fee,
+		PhaseSpans:     phaseSpans(recorder),
+	}
+	return &asrpc.CleanupClosedChannelsResponse{
+		DeletedAppIds:    deleted_app_ids,
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
+
+// phaseSpans converts the spans of recorder for a RuntimeRecording
+func phaseSpans(recorder *payment.PhaseRecorder) []*asrpc.PhaseSpan {
+	phase_spans := make([]*asrpc.PhaseSpan, 0)
+	for _, span := range recorder.Spans() {
+		phase_spans = append(phase_spans, &asrpc.PhaseSpan{
+			Name:           span.Name,
+			TimestampStart: timestamppb.New(span.Start),
+			TimestampEnd:   timestamppb.New(span.End),
+		})
+	}
+	return phase_spans
+}
@@ -0,0 +1,660 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/dancodery/algorand-state-channels/payment"
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	simFundingAmount  = 10_000_000
+	simPenaltyReserve = 100_000
+	simDisputeWindow  = 10
+	simMinFee         = 1_000
+)
+
+func (sim *simulation) appState(app_id uint64) payment.ChannelAppState {
+	app_state, err := payment.ReadChannelAppState(sim.alice.server.algod_client, app_id)
+	if err != nil {
+		sim.t.Fatalf("reading app state: %v", err)
+	}
+	return app_state
+}
+
+func (sim *simulation) isClosed(app_id uint64) bool {
+	closed, err := payment.IsChannelAppClosed(sim.alice.server.algod_client, app_id)
+	if err != nil {
+		sim.t.Fatalf("reading app account: %v", err)
+	}
+	return closed
+}
+
+func (sim *simulation) mustPay(payer *simulationNode, payee *simulationNode, amount uint64) {
+	sim.t.Helper()
+	if err := sim.pay(payer, payee, amount); err != nil {
+		sim.t.Fatalf("paying %d from %s to %s (seed %d): %v", amount, payer.host, payee.host, sim.seed, err)
+	}
+}
+
+func TestSimulationOpenChannel(t *testing.T) {
+	sim := newSimulation(t, 1, linkConditions{latency: time.Millisecond})
+	app_id := sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	alice_info, err := sim.alice.channelInfo(sim.bob)
+	if err != nil {
+		t.Fatalf("alice: %v", err)
+	}
+	bob_info, err := sim.bob.channelInfo(sim.alice)
+	if err != nil {
+		t.Fatalf("bob: %v", err)
+	}
+	if alice_info.app_id != app_id || bob_info.app_id != app_id {
+		t.Errorf("nodes keep the apps %d and %d, expected %d", alice_info.app_id, bob_info.app_id, app_id)
+	}
+	// each node reaches the other under the host the connection came from
+	if alice_info.partner_ip != sim.bob.host || bob_info.partner_ip != sim.alice.host {
+		t.Errorf("partner hosts are %q and %q", alice_info.partner_ip, bob_info.partner_ip)
+	}
+	if alice_info.bob_signing_key != bob_info.bob_signing_key || alice_info.alice_signing_key != bob_info.alice_signing_key {
+		t.Errorf("nodes disagree on the signing keys")
+	}
+
+	app_state := sim.appState(app_id)
+	if !app_state.BobAccepted || app_state.TotalDeposit != simFundingAmount {
+		t.Errorf("bob accepted: %v, total deposit %d", app_state.BobAccepted, app_state.TotalDeposit)
+	}
+	for _, state := range []*paymentChannelOffChainState{sim.alice.latestState(sim.bob), sim.bob.latestState(sim.alice)} {
+		if state == nil || state.alice_balance != simFundingAmount || state.bob_balance != 0 {
+			t.Errorf("initial state is %+v", state)
+		}
+	}
+}
+
+func TestSimulationManyPayments(t *testing.T) {
+	const seed = 7
+	sim := newSimulation(t, seed, linkConditions{latency: 200 * time.Microsecond, jitter: time.Millisecond})
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	random := rand.New(rand.NewSource(seed))
+	alice_balance, bob_balance := uint64(simFundingAmount), uint64(0)
+	for i := 0; i < 60; i++ {
+		amount := uint64(1_000 + random.Int63n(100_000))
+		// the payer has to keep the penalty reserve
+		if random.Intn(3) == 0 && bob_balance >= simPenaltyReserve+amount {
+			sim.mustPay(sim.bob, sim.alice, amount)
+			bob_balance -= amount
+			alice_balance += amount
+		} else if alice_balance >= simPenaltyReserve+amount {
+			sim.mustPay(sim.alice, sim.bob, amount)
+			alice_balance -= amount
+			bob_balance += amount
+		}
+		sim.assertConverged()
+	}
+
+	latest_state := sim.alice.latestState(sim.bob)
+	if latest_state.alice_balance != alice_balance || latest_state.bob_balance != bob_balance {
+		t.Errorf("latest balances are %d and %d, expected %d and %d (seed %d)",
+			latest_state.alice_balance, latest_state.bob_balance, alice_balance, bob_balance, seed)
+	}
+	if len(sim.bob.server.payment_channels_offchain_states_log[sim.alice.address()]) < 2 {
+		t.Errorf("bob did not log the payments")
+	}
+}
+
+func TestSimulationConcurrentPayments(t *testing.T) {
+	sim := newSimulation(t, 8, linkConditions{latency: 200 * time.Microsecond, jitter: time.Millisecond})
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	// payments of the same node over a channel wait for each other instead of building on the same state
+	const payments = 10
+	errs := make(chan error, payments)
+	for i := 0; i < payments; i++ {
+		go func() {
+			errs <- sim.pay(sim.alice, sim.bob, 100_000)
+		}()
+	}
+	for i := 0; i < payments; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("concurrent payment failed (seed %d): %v", sim.seed, err)
+		}
+	}
+	sim.assertConverged()
+	if bob_state := sim.bob.latestState(sim.alice); bob_state.bob_balance != payments*100_000 {
+		t.Errorf("bob's balance is %d, expected %d", bob_state.bob_balance, payments*100_000)
+	}
+}
+
+func TestSimulationCooperativeClose(t *testing.T) {
+	sim := newSimulation(t, 3, linkConditions{latency: time.Millisecond})
+	app_id := sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+	sim.mustPay(sim.alice, sim.bob, 3_000_000)
+	sim.mustPay(sim.bob, sim.alice, 500_000)
+
+	bob_before := sim.balance(sim.bob)
+	_, err := sim.alice.rpc.CooperativeCloseChannel(context.Background(), &asrpc.CooperativeCloseChannelRequest{AlgoAddress: sim.bob.address()})
+	if err != nil {
+		t.Fatalf("closing channel: %v", err)
+	}
+
+	if !sim.isClosed(app_id) {
+		t.Errorf("app account was not closed")
+	}
+	if payout := sim.balance(sim.bob) - bob_before; payout != 2_500_000-simMinFee {
+		t.Errorf("bob was paid %d, expected %d", payout, 2_500_000-simMinFee)
+	}
+	if _, err := sim.alice.channelInfo(sim.bob); err == nil {
+		t.Errorf("alice still keeps the closed channel")
+	}
+	if _, err := sim.bob.channelInfo(sim.alice); err == nil {
+		t.Errorf("bob still keeps the closed channel")
+	}
+}
+
+func TestSimulationUnilateralCloseAndFinalize(t *testing.T) {
+	sim := newSimulation(t, 4, linkConditions{})
+	app_id := sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+	sim.mustPay(sim.alice, sim.bob, 4_000_000)
+
+	_, err := sim.alice.rpc.InitiateCloseChannel(context.Background(), &asrpc.InitiateCloseChannelRequest{AlgoAddress: sim.bob.address()})
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+	// alice closed with the latest state, bob's watchtower has nothing to dispute
+	sim.clock.AdvanceRounds(1)
+	app_state := sim.appState(app_id)
+	if !app_state.IsClosing() || app_state.LatestAliceBalance != 6_000_000 || app_state.LatestBobBalance != 4_000_000 {
+		t.Fatalf("closing state is %d/%d, closing: %v", app_state.LatestAliceBalance, app_state.LatestBobBalance, app_state.IsClosing())
+	}
+
+	sim.clock.AdvanceRounds(simDisputeWindow)
+	alice_before := sim.balance(sim.alice)
+	_, err = sim.bob.rpc.FinalizeCloseChannel(context.Background(), &asrpc.FinalizeCloseChannelRequest{AlgoAddress: sim.alice.address()})
+	if err != nil {
+		t.Fatalf("finalizing: %v", err)
+	}
+	if !sim.isClosed(app_id) {
+		t.Errorf("app account was not closed")
+	}
+	if payout := sim.balance(sim.alice) - alice_before; payout != 6_000_000-simMinFee {
+		t.Errorf("alice was paid %d, expected %d", payout, 6_000_000-simMinFee)
+	}
+
+	// alice's watchtower forgets the channel bob finalized
+	sim.clock.AdvanceRounds(1)
+	if _, err := sim.alice.channelInfo(sim.bob); err == nil {
+		t.Errorf("alice still keeps the finalized channel")
+	}
+}
+
+func TestSimulationTryToCheatIsDisputed(t *testing.T) {
+	sim := newSimulation(t, 5, linkConditions{latency: time.Millisecond})
+	app_id := sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+	sim.mustPay(sim.alice, sim.bob, 5_000_000)
+	sim.mustPay(sim.bob, sim.alice, 2_000_000)
+
+	// bob closes with the state in which he held the most
+	_, err := sim.bob.rpc.TryToCheat(context.Background(), &asrpc.TryToCheatRequest{AlgoAddress: sim.alice.address()})
+	if err != nil {
+		t.Fatalf("trying to cheat: %v", err)
+	}
+	if app_state := sim.appState(app_id); app_state.LatestBobBalance != 5_000_000 {
+		t.Fatalf("bob closed with a balance of %d, expected 5000000", app_state.LatestBobBalance)
+	}
+
+	// alice's watchtower replaces it with the latest state, bob loses the penalty reserve to her
+	sim.clock.AdvanceRounds(1)
+	app_state := sim.appState(app_id)
+	if app_state.LatestAliceBalance != 7_000_000+simPenaltyReserve || app_state.LatestBobBalance != 3_000_000-simPenaltyReserve {
+		t.Fatalf("balances after the dispute are %d and %d", app_state.LatestAliceBalance, app_state.LatestBobBalance)
+	}
+	if latest_state := sim.alice.latestState(sim.bob); app_state.LatestTimestamp != uint64(latest_state.timestamp) {
+		t.Errorf("on-chain state is from %d, the latest state from %d", app_state.LatestTimestamp, latest_state.timestamp)
+	}
+
+	// alice kept the channel to finalize it once the dispute window passed
+	sim.clock.AdvanceRounds(simDisputeWindow)
+	bob_before := sim.balance(sim.bob)
+	_, err = sim.alice.rpc.FinalizeCloseChannel(context.Background(), &asrpc.FinalizeCloseChannelRequest{AlgoAddress: sim.bob.address()})
+	if err != nil {
+		t.Fatalf("finalizing: %v", err)
+	}
+	if !sim.isClosed(app_id) {
+		t.Errorf("app account was not closed")
+	}
+	if payout := sim.balance(sim.bob) - bob_before; payout != 3_000_000-simPenaltyReserve-simMinFee {
+		t.Errorf("bob was paid %d, expected %d", payout, 3_000_000-simPenaltyReserve-simMinFee)
+	}
+}
+
+func TestSimulationDroppedMessages(t *testing.T) {
+	sim := newSimulation(t, 6, linkConditions{})
+	app_id := sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	// a lost request changes neither node
+	sim.network.setLink(linkConditions{drop_requests: 1})
+	if err := sim.pay(sim.alice, sim.bob, 1_000_000); err == nil {
+		t.Fatalf("payment succeeded although its request was dropped")
+	}
+	sim.network.setLink(linkConditions{})
+	if bob_state := sim.bob.latestState(sim.alice); bob_state.bob_balance != 0 {
+		t.Fatalf("bob applied a dropped payment, his balance is %d", bob_state.bob_balance)
+	}
+	sim.mustPay(sim.alice, sim.bob, 1_000_000)
+	sim.assertConverged()
+
+	// a lost response leaves alice one state behind bob
+	sim.network.setLink(linkConditions{drop_responses: 1})
+	if err := sim.pay(sim.alice, sim.bob, 1_000_000); err == nil {
+		t.Fatalf("payment succeeded although its response was dropped")
+	}
+	sim.network.setLink(linkConditions{})
+	alice_state, bob_state := sim.alice.latestState(sim.bob), sim.bob.latestState(sim.alice)
+	if alice_state.bob_balance != 1_000_000 || bob_state.bob_balance != 2_000_000 {
+		t.Fatalf("bob's balance is %d in alice's and %d in bob's state", alice_state.bob_balance, bob_state.bob_balance)
+	}
+	if sim.network.dropped_requests != 1 || sim.network.dropped_responses != 1 {
+		t.Errorf("network dropped %d requests and %d responses", sim.network.dropped_requests, sim.network.dropped_responses)
+	}
+
+	// bob's watchtower enforces the state alice never received, closing with her latest state costs her the penalty reserve
+	_, err := sim.alice.rpc.InitiateCloseChannel(context.Background(), &asrpc.InitiateCloseChannelRequest{AlgoAddress: sim.bob.address()})
+	if err != nil {
+		t.Fatalf("initiating close: %v", err)
+	}
+	sim.clock.AdvanceRounds(1)
+	app_state := sim.appState(app_id)
+	if app_state.LatestTimestamp != uint64(bob_state.timestamp) || app_state.LatestBobBalance != 2_000_000+simPenaltyReserve {
+		t.Errorf("on-chain state is from %d with bob's balance %d, bob's latest state from %d", app_state.LatestTimestamp, app_state.LatestBobBalance, bob_state.timestamp)
+	}
+}
+
+func TestSimulationAuthorizedSignerRejectsInvoicesAndMultiHopPayments(t *testing.T) {
+	sim := newSimulation(t, 9, linkConditions{})
+	// alice signs for her account as an authorized signer, which does not hand out the key of the account
+	key, err := sim.alice.server.algo_account.(payment.KeyExporter).ExportPrivateKey()
+	if err != nil {
+		t.Fatalf("exporting alice's key: %v", err)
+	}
+	authorized_signer, err := payment.NewAuthorizedSigner(sim.alice.server.algod_client, sim.alice.address(), []ed25519.PrivateKey{key}, nil)
+	if err != nil {
+		t.Fatalf("making authorized signer: %v", err)
+	}
+	sim.alice.server.algo_account = authorized_signer
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	_, err = sim.alice.rpc.AddInvoice(context.Background(), &asrpc.AddInvoiceRequest{Amount: 1_000_000})
+	if err == nil {
+		t.Errorf("alice added an invoice she cannot sign for her account")
+	}
+	_, err = sim.alice.rpc.SendPayment(context.Background(), &asrpc.SendPaymentRequest{
+		DestinationAddress: sim.bob.address(),
+		Amount:             1_000_000,
+		Route:              []*asrpc.StateChannelNodeAddress{{Host: sim.bob.host, AlgoAddress: sim.bob.address()}},
+	})
+	if err == nil {
+		t.Errorf("alice sent a multi-hop payment")
+	}
+
+	// invoices of other nodes are still paid over the direct channel
+	bob_invoice, err := sim.bob.rpc.AddInvoice(context.Background(), &asrpc.AddInvoiceRequest{Amount: 1_000_000})
+	if err != nil {
+		t.Fatalf("adding bob's invoice: %v", err)
+	}
+	_, err = sim.alice.rpc.PayInvoice(context.Background(), &asrpc.PayInvoiceRequest{PaymentRequest: bob_invoice.PaymentRequest})
+	if err != nil {
+		t.Fatalf("paying bob's invoice: %v", err)
+	}
+	sim.assertConverged()
+}
+
+func TestSimulationRetiredChannelsAreRefused(t *testing.T) {
+	sim := newSimulation(t, 10, linkConditions{})
+	key, err := sim.alice.server.algo_account.(payment.KeyExporter).ExportPrivateKey()
+	if err != nil {
+		t.Fatalf("exporting alice's key: %v", err)
+	}
+	alice_account, err := crypto.AccountFromPrivateKey(key)
+	if err != nil {
+		t.Fatalf("loading alice's account: %v", err)
+	}
+	algod_client := sim.alice.server.algod_client
+	sp, err := algod_client.SuggestedParams().Do(context.Background())
+	if err != nil {
+		t.Fatalf("getting suggested params: %v", err)
+	}
+	signer := transaction.BasicAccountTransactionSigner{Account: alice_account}
+
+	// alice deployed a channel app of the first contract version, which has no abi
+	retired_version := payment.RetiredContractVersions()[0]
+	create_method, err := payment.LatestContractVersion().Interface.GetMethodByName("create")
+	if err != nil {
+		t.Fatalf("looking up create method: %v", err)
+	}
+	create_atc := transaction.AtomicTransactionComposer{}
+	err = create_atc.AddMethodCall(transaction.AddMethodCallParams{
+		Method:          create_method,
+		MethodArgs:      []interface{}{sim.bob.server.algo_account.Address(), uint64(simPenaltyReserve), uint64(simDisputeWindow), uint64(0), alice_account.Address, sp.GenesisHash},
+		Sender:          alice_account.Address,
+		SuggestedParams: sp,
+		OnComplete:      types.NoOpOC,
+		ApprovalProgram: retired_version.ApprovalProgram,
+		ClearProgram:    retired_version.ClearProgram,
+		GlobalSchema:    types.StateSchema{NumUint: payment.NUM_UINTS, NumByteSlice: payment.NUM_BYTE_SLICES},
+		ExtraPages:      uint32((len(retired_version.ApprovalProgram) + len(retired_version.ClearProgram) - 1) / 2048),
+		Signer:          signer,
+	})
+	if err != nil {
+		t.Fatalf("composing create call: %v", err)
+	}
+	create_result, err := create_atc.Execute(algod_client, context.Background(), 4)
+	if err != nil {
+		t.Fatalf("creating retired channel app: %v", err)
+	}
+	app_id := create_result.MethodResults[0].TransactionInfo.ApplicationIndex
+
+	// an empty app does not hold up the node
+	err = sim.alice.server.checkRetiredChannels(sim.alice.address())
+	if err != nil {
+		t.Fatalf("empty retired channel app refused: %v", err)
+	}
+
+	// a funded one does, the node cannot call it to close the channel
+	funding_txn, err := transaction.MakePaymentTxn(sim.alice.address(), crypto.GetApplicationAddress(app_id).String(), simFundingAmount, nil, "", sp)
+	if err != nil {
+		t.Fatalf("making funding payment: %v", err)
+	}
+	funding_atc := transaction.AtomicTransactionComposer{}
+	err = funding_atc.AddTransaction(transaction.TransactionWithSigner{Txn: funding_txn, Signer: signer})
+	if err != nil {
+		t.Fatalf("composing funding payment: %v", err)
+	}
+	_, err = funding_atc.Execute(algod_client, context.Background(), 4)
+	if err != nil {
+		t.Fatalf("funding retired channel app: %v", err)
+	}
+	err = sim.alice.server.checkRetiredChannels(sim.alice.address())
+	if err == nil {
+		t.Errorf("funded channel app of the retired contract version was not refused")
+	}
+}
+
+func TestSimulationMalformedHTLCRequestsAreRejected(t *testing.T) {
+	sim := newSimulation(t, 11, linkConditions{})
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+	alice_info, err := sim.alice.channelInfo(sim.bob)
+	if err != nil {
+		t.Fatalf("alice: %v", err)
+	}
+	current_round, err := sim.alice.server.getCurrentRound()
+	if err != nil {
+		t.Fatalf("reading round: %v", err)
+	}
+	initial_state := sim.bob.latestState(sim.alice)
+	new_timestamp := initial_state.timestamp + 1
+
+	// alice offers an htlc whose amount wraps her new balance around to the old one
+	htlcs := []payment.HTLC{{
+		OfferedByAlice: true,
+		Amount:         ^uint64(0) - 1_000_000 + 1,
+		Timelock:       current_round + 2*simDisputeWindow,
+	}}
+	signature, err := payment.SignState(alice_info.app_id, alice_info.asset_id, alice_info.my_signer,
+		simFundingAmount+1_000_000, 0, sim.alice.server.genesis_hash, new_timestamp, htlcs)
+	if err != nil {
+		t.Fatalf("signing state: %v", err)
+	}
+	args := [][]byte{
+		[]byte(sim.alice.address()),
+		uint64ToBytes(simFundingAmount + 1_000_000),
+		uint64ToBytes(0),
+		uint64ToBytes(uint64(new_timestamp)),
+		payment.EncodeHTLCs(htlcs),
+		signature,
+	}
+	if response, _ := sim.bob.server.processHTLCRequest("add_htlc_request", args); response.Message != "reject" {
+		t.Errorf("bob accepted an htlc of %d", htlcs[0].Amount)
+	}
+
+	// balances and timestamps shorter than 8 bytes are rejected instead of panicking
+	args[1] = []byte{1}
+	if response, _ := sim.bob.server.processHTLCRequest("add_htlc_request", args); response.Message != "reject" {
+		t.Errorf("bob accepted a balance of 1 byte")
+	}
+	deposit_args := append(append([][]byte{}, args...), []byte{1})
+	if response := sim.bob.server.processDepositRequest(deposit_args); response.Message != "reject" {
+		t.Errorf("bob accepted a deposit of 1 byte")
+	}
+	if response := sim.bob.server.processWithdrawRequest(append(deposit_args, signature)); response.Message != "reject" {
+		t.Errorf("bob accepted a withdrawal of 1 byte")
+	}
+	if state := sim.bob.latestState(sim.alice); state.timestamp != initial_state.timestamp || len(state.htlcs) != 0 {
+		t.Errorf("bob saved the state %+v", state)
+	}
+}
+
+func TestSimulationUnlockWhileServingAndResetOutsideDevMode(t *testing.T) {
+	sim := newSimulation(t, 12, linkConditions{latency: time.Millisecond})
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	// bob keeps his account in the keystore and unlocks it again while alice pays him
+	key, err := sim.bob.server.algo_account.(payment.KeyExporter).ExportPrivateKey()
+	if err != nil {
+		t.Fatalf("exporting bob's key: %v", err)
+	}
+	bob_mnemonic, err := mnemonic.FromPrivateKey(key)
+	if err != nil {
+		t.Fatalf("encoding bob's key: %v", err)
+	}
+	_, err = sim.bob.rpc.Create(context.Background(), &asrpc.CreateRequest{Password: testKeystorePassword, Mnemonic: bob_mnemonic})
+	if err != nil {
+		t.Fatalf("creating bob's keystore: %v", err)
+	}
+	unlocked := make(chan error)
+	go func() {
+		_, err := sim.bob.rpc.Unlock(context.Background(), &asrpc.UnlockRequest{Password: testKeystorePassword})
+		unlocked <- err
+	}()
+	for paying := true; paying; {
+		sim.mustPay(sim.alice, sim.bob, 10_000)
+		select {
+		case err := <-unlocked:
+			if err != nil {
+				t.Fatalf("unlocking bob's keystore: %v", err)
+			}
+			paying = false
+		default:
+		}
+	}
+	sim.mustPay(sim.alice, sim.bob, 10_000)
+	sim.assertConverged()
+
+	// outside of dev mode the channels are kept, they are needed to close them
+	sim.bob.server.dev_mode = false
+	_, err = sim.bob.rpc.Reset(context.Background(), &asrpc.ResetRequest{})
+	if err == nil {
+		t.Errorf("bob reset his node outside of dev mode")
+	}
+	if _, err := sim.bob.channelInfo(sim.alice); err != nil {
+		t.Errorf("bob forgot the channel: %v", err)
+	}
+}
+
+func TestSimulationListPaymentsIncludesHTLCPayments(t *testing.T) {
+	sim := newSimulation(t, 13, linkConditions{latency: time.Millisecond})
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	// a direct payment, a payment over an htlc and an invoice
+	sim.mustPay(sim.alice, sim.bob, 1_000_000)
+	_, err := sim.alice.rpc.SendPayment(context.Background(), &asrpc.SendPaymentRequest{
+		DestinationAddress: sim.bob.address(),
+		Amount:             2_000_000,
+		Route:              []*asrpc.StateChannelNodeAddress{{Host: sim.bob.host, AlgoAddress: sim.bob.address()}},
+	})
+	if err != nil {
+		t.Fatalf("sending htlc payment: %v", err)
+	}
+	bob_invoice, err := sim.bob.rpc.AddInvoice(context.Background(), &asrpc.AddInvoiceRequest{Amount: 500_000})
+	if err != nil {
+		t.Fatalf("adding bob's invoice: %v", err)
+	}
+	_, err = sim.alice.rpc.PayInvoice(context.Background(), &asrpc.PayInvoiceRequest{PaymentRequest: bob_invoice.PaymentRequest})
+	if err != nil {
+		t.Fatalf("paying bob's invoice: %v", err)
+	}
+	sim.assertConverged()
+
+	expected_amounts := []uint64{1_000_000, 2_000_000, 500_000}
+	for _, node := range []*simulationNode{sim.alice, sim.bob} {
+		response, err := node.rpc.ListPayments(context.Background(), &asrpc.ListPaymentsRequest{})
+		if err != nil {
+			t.Fatalf("%s listing payments: %v", node.host, err)
+		}
+		if len(response.Payments) != len(expected_amounts) {
+			t.Fatalf("%s lists %d payments, expected %d", node.host, len(response.Payments), len(expected_amounts))
+		}
+		for i, listed_payment := range response.Payments {
+			if listed_payment.Amount != expected_amounts[i] || listed_payment.Outgoing != (node == sim.alice) {
+				t.Errorf("%s lists payment %d of %d, outgoing %v", node.host, i, listed_payment.Amount, listed_payment.Outgoing)
+			}
+		}
+	}
+}
+
+func TestSimulationHTLCSettleAndFail(t *testing.T) {
+	sim := newSimulation(t, 14, linkConditions{latency: time.Millisecond})
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	// bob settles the first htlc with its preimage
+	preimage := []byte("preimage of the settled htlc....")
+	hashlock := payment.HashPreimage(preimage)
+	_, err := sim.alice.rpc.AddHTLC(context.Background(), &asrpc.AddHTLCRequest{
+		AlgoAddress: sim.bob.address(),
+		Amount:      1_000_000,
+		PaymentHash: hashlock[:],
+		Expiry:      2 * simDisputeWindow,
+	})
+	if err != nil {
+		t.Fatalf("adding htlc: %v", err)
+	}
+	if state := sim.bob.latestState(sim.alice); len(state.htlcs) != 1 || state.alice_balance != simFundingAmount-1_000_000 {
+		t.Fatalf("bob holds %d htlcs and alice's balance %d", len(state.htlcs), state.alice_balance)
+	}
+	_, err = sim.bob.rpc.SettleHTLC(context.Background(), &asrpc.SettleHTLCRequest{AlgoAddress: sim.alice.address(), Preimage: preimage})
+	if err != nil {
+		t.Fatalf("settling htlc: %v", err)
+	}
+	sim.assertConverged()
+	if state := sim.alice.latestState(sim.bob); len(state.htlcs) != 0 || state.bob_balance != 1_000_000 {
+		t.Fatalf("alice holds %d htlcs and bob's balance %d after the settlement", len(state.htlcs), state.bob_balance)
+	}
+
+	// alice cannot take back the second htlc before it expires, bob fails it and refunds her
+	failed_hashlock := payment.HashPreimage([]byte("preimage of the failed htlc....."))
+	_, err = sim.alice.rpc.AddHTLC(context.Background(), &asrpc.AddHTLCRequest{
+		AlgoAddress: sim.bob.address(),
+		Amount:      2_000_000,
+		PaymentHash: failed_hashlock[:],
+		Expiry:      2 * simDisputeWindow,
+	})
+	if err != nil {
+		t.Fatalf("adding htlc: %v", err)
+	}
+	_, err = sim.alice.rpc.FailHTLC(context.Background(), &asrpc.FailHTLCRequest{AlgoAddress: sim.bob.address(), PaymentHash: failed_hashlock[:]})
+	if err == nil {
+		t.Errorf("alice refunded her htlc before it expired")
+	}
+	_, err = sim.bob.rpc.FailHTLC(context.Background(), &asrpc.FailHTLCRequest{AlgoAddress: sim.alice.address(), PaymentHash: failed_hashlock[:]})
+	if err != nil {
+		t.Fatalf("failing htlc: %v", err)
+	}
+	sim.assertConverged()
+	if state := sim.alice.latestState(sim.bob); len(state.htlcs) != 0 || state.alice_balance != simFundingAmount-1_000_000 || state.bob_balance != 1_000_000 {
+		t.Errorf("alice holds %d htlcs and the balances %d/%d after the refund", len(state.htlcs), state.alice_balance, state.bob_balance)
+	}
+}
+
+func TestSimulationMultiHopPayment(t *testing.T) {
+	sim := newSimulation(t, 15, linkConditions{latency: time.Millisecond})
+	carol := sim.startNode("carol")
+	sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+	sim.openChannelBetween(sim.bob, carol, simFundingAmount, simPenaltyReserve, simDisputeWindow)
+
+	// alice pays carol over bob, who keeps a forwarding fee
+	response, err := sim.alice.rpc.SendPayment(context.Background(), &asrpc.SendPaymentRequest{
+		DestinationAddress: carol.address(),
+		Amount:             1_000_000,
+		Route: []*asrpc.StateChannelNodeAddress{
+			{Host: sim.bob.host, AlgoAddress: sim.bob.address()},
+			{Host: carol.host, AlgoAddress: carol.address()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("sending multi-hop payment: %v", err)
+	}
+	if response.Fee == 0 || len(response.Preimage) != 32 {
+		t.Errorf("alice paid a fee of %d and learned a preimage of %d bytes", response.Fee, len(response.Preimage))
+	}
+	sim.assertConverged()
+
+	alice_state := sim.alice.latestState(sim.bob)
+	if len(alice_state.htlcs) != 0 || alice_state.bob_balance != 1_000_000+response.Fee {
+		t.Errorf("bob received %d from alice with %d htlcs pending, expected %d", alice_state.bob_balance, len(alice_state.htlcs), 1_000_000+response.Fee)
+	}
+	bob_state, carol_state := sim.bob.latestState(carol), carol.latestState(sim.bob)
+	if bob_state.timestamp != carol_state.timestamp || len(carol_state.htlcs) != 0 {
+		t.Fatalf("bob and carol hold states from %d and %d, carol with %d htlcs", bob_state.timestamp, carol_state.timestamp, len(carol_state.htlcs))
+	}
+	if carol_state.alice_balance != simFundingAmount-1_000_000 || carol_state.bob_balance != 1_000_000 {
+		t.Errorf("bob and carol hold %d and %d, expected %d and 1000000", carol_state.alice_balance, carol_state.bob_balance, simFundingAmount-1_000_000)
+	}
+}
+
+func TestSimulationDepositAndWithdraw(t *testing.T) {
+	sim := newSimulation(t, 16, linkConditions{latency: time.Millisecond})
+	app_id := sim.openChannel(simFundingAmount, simPenaltyReserve, simDisputeWindow)
+	sim.mustPay(sim.alice, sim.bob, 3_000_000)
+
+	// alice's deposit adds to her balance
+	deposit, err := sim.alice.rpc.DepositToChannel(context.Background(), &asrpc.DepositToChannelRequest{AlgoAddress: sim.bob.address(), Amount: 2_000_000})
+	if err != nil {
+		t.Fatalf("depositing: %v", err)
+	}
+	if deposit.TotalDeposit != simFundingAmount+2_000_000 || sim.appState(app_id).TotalDeposit != deposit.TotalDeposit {
+		t.Errorf("total deposit is %d, the app holds %d", deposit.TotalDeposit, sim.appState(app_id).TotalDeposit)
+	}
+	sim.assertConverged()
+	if state := sim.alice.latestState(sim.bob); state.alice_balance != 9_000_000 || state.bob_balance != 3_000_000 {
+		t.Errorf("balances after the deposit are %d and %d", state.alice_balance, state.bob_balance)
+	}
+
+	// bob withdraws part of his balance to his account
+	bob_before := sim.balance(sim.bob)
+	withdrawal, err := sim.bob.rpc.WithdrawFromChannel(context.Background(), &asrpc.WithdrawFromChannelRequest{AlgoAddress: sim.alice.address(), Amount: 1_000_000})
+	if err != nil {
+		t.Fatalf("withdrawing: %v", err)
+	}
+	if withdrawal.TotalDeposit != simFundingAmount+1_000_000 || sim.appState(app_id).TotalDeposit != withdrawal.TotalDeposit {
+		t.Errorf("total deposit is %d, the app holds %d", withdrawal.TotalDeposit, sim.appState(app_id).TotalDeposit)
+	}
+	sim.assertConverged()
+	if state := sim.bob.latestState(sim.alice); state.alice_balance != 9_000_000 || state.bob_balance != 2_000_000 {
+		t.Errorf("balances after the withdrawal are %d and %d", state.alice_balance, state.bob_balance)
+	}
+	if received := sim.balance(sim.bob) - bob_before; received > 1_000_000 || received < 1_000_000-10*simMinFee {
+		t.Errorf("bob's account received %d", received)
+	}
+
+	// payments continue on the new capacity
+	sim.mustPay(sim.bob, sim.alice, 2_000_000-simPenaltyReserve)
+	sim.assertConverged()
+}
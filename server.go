@@ -3,7 +3,6 @@ package main
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
@@ -11,16 +10,19 @@ import (
 	"log"
 	"net"
 	"os"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
 	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
-	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/dancodery/algorand-state-channels/asrpc"
 	"github.com/dancodery/algorand-state-channels/payment"
 	"github.com/dancodery/algorand-state-channels/payment/testing"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type paymentChannelInfo struct {
@@ -30,6 +32,13 @@ type paymentChannelInfo struct {
 	alice_address string
 	bob_address   string
 
+	// keys signing the off-chain states, registered in the app separately from the funding accounts
+	alice_signing_key string
+	bob_signing_key   string
+	my_signer         payment.Signer
+
+	asset_id uint64 // asa the channel is denominated in, 0 for algo
+
 	alice_onchain_balance uint64
 	bob_onchain_balance   uint64
 
@@ -38,79 +47,179 @@ type paymentChannelInfo struct {
 	dispute_window  uint64
 }
 
+// signingKey returns the key signing the off-chain states of the channel party with the given algo address
+func (c paymentChannelInfo) signingKey(algo_address string) string {
+	if algo_address == c.alice_address {
+		return c.alice_signing_key
+	}
+	return c.bob_signing_key
+}
+
 type paymentChannelOffChainState struct {
 	timestamp int64 // unix timestamp in nanoseconds
 
 	alice_balance uint64
 	bob_balance   uint64
+	htlcs         []payment.HTLC // pending conditional payments
+
+	memo           string // optional description the payer attached to the payment
+	memo_signature []byte // payer's signature of the memo
 
 	alice_signature []byte
 	bob_signature   []byte
 
-	algorand_port int
-	app_id        uint64
+	genesis_hash []byte // network the state is signed for
+	app_id       uint64
 }
 
 type server struct {
 	algod_client *algod.Client
-	algo_account crypto.Account
+	algo_account payment.Signer
+	wallet_mu    sync.RWMutex // guards algo_account and unlocked, Create and Unlock set them while requests are served
+	unlock_mu    sync.Mutex   // serializes Create and Unlock
+
+	// the maps are shared by rpc calls, requests of partner nodes, the watchtower and the forwarding of htlcs,
+	// mu guards them and is only held while reading or changing them
+	mu            sync.Mutex
+	channel_locks map[string]*sync.Mutex // held from loading to saving an update of a channel, by partner address
 
 	payment_channels_onchain_states      map[string]paymentChannelInfo
 	payment_channels_offchain_states_log map[string]map[int64]paymentChannelOffChainState
+	htlc_preimages                       map[[32]byte][]byte     // known preimages by hashlock
+	channel_fees                         map[uint64]*channelFees // blockchain fees I paid, by app_id
+
+	forwarding_policy forwardingPolicy
+	forwarded_htlcs   map[[32]byte]forwardedHTLC   // htlcs I forwarded, by hashlock
+	pending_payments  map[[32]byte]*pendingPayment // multi-hop payments I sent, by hashlock
+
+	invoices                 map[[32]byte]*invoice            // my invoices, by payment hash
+	pending_capacity_changes map[string]pendingCapacityChange // deposits and withdrawals of partner nodes awaiting confirmation, by partner address
+	node_host                string                           // host under which partner nodes reach me
+
+	genesis_hash []byte // network I run on, every signed message and peer request is bound to it
+
+	dev_mode      bool   // allows throwaway accounts and SEED_PHRASE instead of the keystore
+	keystore_path string // encrypted account of the node
+	unlocked      bool   // channels can only be operated once the account was loaded
 
 	peer_port     int
 	grpc_port     int
+	peer_network  peerNetwork
 	peer_listener net.Listener
 	grpc_listener net.Listener
 	rpcServer     *rpcServer
+
+	clock clock         // paces the watchtower
+	quit  chan struct{} // closed by stop
 }
 
-func initializeServer(peerPort int, grpcPort int) (*server, error) {
+func initializeServer(loaded_config *config) (*server, error) {
 	s := &server{
-		peer_port: peerPort,
-		grpc_port: grpcPort,
+		peer_port:    loaded_config.PeerPort,
+		grpc_port:    loaded_config.GRPCPort,
+		peer_network: tcpNetwork{},
+
+		clock: systemClock{},
+		quit:  make(chan struct{}),
 
+		dev_mode:      loaded_config.DevMode,
+		keystore_path: loaded_config.KeystorePath,
+
+		forwarding_policy: forwardingPolicy{
+			base_fee:       loaded_config.FeeBase,
+			fee_ppm:        loaded_config.FeePPM,
+			timelock_delta: loaded_config.TimelockDelta,
+		},
+		forwarded_htlcs:  make(map[[32]byte]forwardedHTLC),
+		pending_payments: make(map[[32]byte]*pendingPayment),
+
+		invoices:                 make(map[[32]byte]*invoice),
+		pending_capacity_changes: make(map[string]pendingCapacityChange),
+		node_host:                loaded_config.Host,
+
+		channel_locks:                        make(map[string]*sync.Mutex),
 		payment_channels_onchain_states:      make(map[string]paymentChannelInfo),
 		payment_channels_offchain_states_log: make(map[string]map[int64]paymentChannelOffChainState),
+		htlc_preimages:                       make(map[[32]byte][]byte),
+		channel_fees:                         make(map[uint64]*channelFees),
 	}
 
 	s.rpcServer = newRpcServer(s)
 	s.algod_client = testing.GetAlgodClient()
 
-	// new: generate account from seed
+	genesis_hash, err := payment.GetGenesisHash(s.algod_client)
+	if err != nil {
+		fmt.Printf("Error reading genesis hash from algod: %v\n", err)
+		return nil, err
+	}
+	s.genesis_hash = genesis_hash
+
+	// keys kept in kmd or given for a rekeyed or multisig account are available right away, only dev mode falls back to SEED_PHRASE or a throwaway account,
+	// otherwise the keystore has to be unlocked
+	if loaded_config.SignerBackend == SIGNER_BACKEND_KMD {
+		err := s.loadKMDSigner(loaded_config.KMDWalletName, loaded_config.KMDWalletPassword, loaded_config.KMDAccount, loaded_config.KMDChannelSeed)
+		if err != nil {
+			return nil, err
+		}
+	} else if loaded_config.SignerBackend == SIGNER_BACKEND_AUTHORIZED {
+		err := s.loadAuthorizedSigner(loaded_config)
+		if err != nil {
+			return nil, err
+		}
+	} else if s.dev_mode {
+		err := s.loadDevAccount()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fmt.Printf("The wallet is locked, create or unlock the keystore %s with ascli create or ascli unlock\n", s.keystore_path)
+	}
+
+	// channels of retired contract versions can no longer be operated
+	if s.isUnlocked() {
+		err := s.checkRetiredChannels(s.account().Address().String())
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// loadDevAccount loads the account from the SEED_PHRASE env variable or generates a new one and funds it
+func (s *server) loadDevAccount() error {
 	seed_phrase := os.Getenv("SEED_PHRASE")
 
 	if seed_phrase == "" {
-		s.algo_account = crypto.GenerateAccount()
+		s.setAccount(payment.NewAccountSigner(crypto.GenerateAccount()))
 	} else {
 		private_key, err := mnemonic.ToPrivateKey(seed_phrase)
 		if err != nil {
 			log.Fatalf("failed to generate account from seed: %v\n", err)
-			return nil, err
+			return err
 		}
-		s.algo_account, err = crypto.AccountFromPrivateKey(private_key)
+		account, err := crypto.AccountFromPrivateKey(private_key)
 		if err != nil {
 			log.Fatalf("failed to generate account from seed: %v\n", err)
-			return nil, err
+			return err
 		}
+		s.setAccount(payment.NewAccountSigner(account))
 	}
 
-	fmt.Printf("My node ALGO address is: %v\n", s.algo_account.Address.String())
+	fmt.Printf("My node ALGO address is: %v\n", s.account().Address().String())
 
 	// fund account
-	testing.FundAccount(s.algod_client, s.algo_account.Address.String(), 10_000_000_000)
+	testing.FundAccount(s.algod_client, s.account().Address().String(), 10_000_000_000)
 
-	return s, nil
+	return nil
 }
 
 func (s *server) startListening() error {
-	// save listeners
-	peer_listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.peer_port))
+	err := s.listenForPeers()
 	if err != nil {
-		log.Fatalf("Error listening: %v\n", err)
 		return err
 	}
-	s.peer_listener = peer_listener
 
 	grpc_listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.grpc_port))
 	if err != nil {
@@ -118,12 +227,25 @@ func (s *server) startListening() error {
 		return err
 	}
 	s.grpc_listener = grpc_listener
+	return nil
+}
+
+// listenForPeers accepts the connections of partner nodes until the node is stopped
+func (s *server) listenForPeers() error {
+	peer_listener, err := s.peer_network.Listen(s.peer_port)
+	if err != nil {
+		log.Fatalf("Error listening: %v\n", err)
+		return err
+	}
+	s.peer_listener = peer_listener
 
-	// start listening
 	go func() {
 		fmt.Printf("Listening for peers on port %d\n", s.peer_port)
 		for {
 			conn, err := s.peer_listener.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
 			if err != nil {
 				log.Fatalf("Error accepting: %v\n", err)
 				return
@@ -134,21 +256,23 @@ func (s *server) startListening() error {
 	return nil
 }
 
+// stop closes the peer listener and ends the watchtower
+func (s *server) stop() {
+	close(s.quit)
+	if s.peer_listener != nil {
+		s.peer_listener.Close()
+	}
+}
+
 func (s *server) handleConnection(conn net.Conn) {
 	defer conn.Close()
 
 	// Get the IP address of the connection partner
-	partner_ip := conn.RemoteAddr().(*net.TCPAddr).IP.String()
-
-	client_request_data := make([]byte, 2<<10) // 2KB
-	n, err := conn.Read(client_request_data)
-	if err != nil {
-		log.Fatalf("Error reading: %v\n", err)
-		return // handles next connection
-	}
+	partner_ip := peerHost(conn.RemoteAddr())
 
+	// requests carrying onions do not fit into a single fixed size read
 	var client_request P2PRequest
-	err = json.Unmarshal(client_request_data[:n], &client_request)
+	err := json.NewDecoder(conn).Decode(&client_request)
 	if err != nil {
 		log.Fatalf("Error unmarshalling: %v\n", err)
 		return
@@ -156,6 +280,29 @@ func (s *server) handleConnection(conn net.Conn) {
 
 	// process request
 	var server_response P2PResponse
+	var after_response func() // runs once the response was sent, e.g. to forward an htlc
+	if !s.isUnlocked() {
+		fmt.Printf("Rejecting %s, the wallet is locked\n", client_request.Command)
+		s.writeP2PResponse(conn, P2PResponse{Message: "reject"})
+		return
+	}
+	if client_request.ProtocolVersion != payment.PROTOCOL_VERSION || !bytes.Equal(client_request.GenesisHash, s.genesis_hash) {
+		fmt.Printf("Rejecting %s of protocol version %d on network %x, I run version %d on network %x\n",
+			client_request.Command, client_request.ProtocolVersion, client_request.GenesisHash, payment.PROTOCOL_VERSION, s.genesis_hash)
+		s.writeP2PResponse(conn, P2PResponse{Message: "reject"})
+		return
+	}
+	// an update of the partner node is rejected while I update the same channel, only one of both could build on the latest state
+	var unlock_channel func()
+	if updatesChannel(client_request) {
+		var ok bool
+		unlock_channel, ok = s.tryLockChannel(string(client_request.Args[0]))
+		if !ok {
+			fmt.Printf("Rejecting %s, I am updating the channel myself\n", client_request.Command)
+			s.writeP2PResponse(conn, P2PResponse{Message: "reject"})
+			return
+		}
+	}
 	switch client_request.Command {
 	case "open_channel_request":
 		app_id, err := strconv.ParseUint(string(client_request.Args[0]), 10, 64)
@@ -171,25 +318,56 @@ func (s *server) handleConnection(conn net.Conn) {
 			return
 		}
 
+		app_state, err := payment.DecodeChannelAppState(blockchain_app_info)
+		if err != nil {
+			fmt.Printf("Error decoding channel app state: %v\n", err)
+			server_response.Message = "reject"
+			break
+		}
+
 		// check if smart contract is valid
-		smart_contract_valid := s.doOpenChannelSecurityChecks(blockchain_app_info)
+		smart_contract_valid := s.doOpenChannelSecurityChecks(blockchain_app_info, app_state)
 		if !smart_contract_valid {
 			server_response.Message = "reject"
 			break
 		}
 
+		// derive my signing key of the channel, it can be recovered from my account
+		my_signer, err := payment.DeriveChannelSigner(s.account(), app_id)
+		if err != nil {
+			fmt.Printf("Error deriving channel signing key: %v\n", err)
+			server_response.Message = "reject"
+			break
+		}
+
+		// record my acceptance and signing key on chain, alice can no longer abort the channel afterwards
+		accept_fee, err := payment.AcceptChannel(s.algod_client, s.account(), app_id, my_signer.Address())
+		if err != nil {
+			fmt.Printf("Error accepting channel: %v\n", err)
+			server_response.Message = "reject"
+			break
+		}
+		s.recordChannelFee(app_id, app_state.AliceAddress.String(), accept_fee)
+
 		// save the new payment channel state
-		s.savePaymentChannelOnChainState(partner_ip, app_id, blockchain_app_info.Params.GlobalState)
+		s.savePaymentChannelOnChainState(partner_ip, app_state, my_signer)
 
 		fmt.Printf("\nThe payment channel with app_id %d was opened successfully.\n", app_id)
 
+		s.mu.Lock()
 		fmt.Printf("All Current Payment Channels: %+v\n\n", s.payment_channels_onchain_states)
+		s.mu.Unlock()
 
 		s.UpdateWatchtowerState()
 
 		server_response.Message = "approve"
 
 	case "pay_request":
+		if len(client_request.Args) < 5 || !areUint64Args(client_request.Args, 1, 2, 3) {
+			fmt.Println("Error: malformed pay_request")
+			server_response.Message = "reject"
+			break
+		}
 		counterparty_address := string(client_request.Args[0])
 		alice_new_balance := binary.BigEndian.Uint64(client_request.Args[1])
 		bob_new_balance := binary.BigEndian.Uint64(client_request.Args[2])
@@ -197,31 +375,15 @@ func (s *server) handleConnection(conn net.Conn) {
 
 		channel_partner_signature := client_request.Args[4]
 
-		// 1. load onchain state
-		onchain_state, ok := s.payment_channels_onchain_states[counterparty_address]
-		if !ok {
-			fmt.Printf("Error: payment channel with address %s does not exist\n", counterparty_address)
-			server_response.Message = "reject"
-			break
-		}
-
-		var me_alice bool
-		if onchain_state.alice_address == s.algo_account.Address.String() {
-			me_alice = true
-		} else {
-			me_alice = false
-		}
-
-		// 2. load latest off chain state
-		payment_log, ok := s.payment_channels_offchain_states_log[counterparty_address]
-		if !ok {
-			fmt.Printf("Error: payment channel with address %s does not exist\n", counterparty_address)
+		// 1. load on chain and latest off chain state
+		onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			server_response.Message = "reject"
 			break
 		}
-		latestOffChainState, err := getLatestOffChainState(payment_log)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+		if s.capacityChangePending(counterparty_address) {
+			fmt.Println("Error: a deposit or withdrawal is pending")
 			server_response.Message = "reject"
 			break
 		}
@@ -242,7 +404,7 @@ func (s *server) handleConnection(conn net.Conn) {
 		}
 		last_timestamp := latestOffChainState.timestamp
 
-		// 3. verify that all new parameters are beneficial for me
+		// 2. verify that all new parameters are beneficial for me
 		counterparty_balance_diff := int64(last_counterparty_balance) - int64(counterparty_new_balance)
 		my_balance_diff := int64(last_my_balance) - int64(my_new_balance)
 
@@ -265,15 +427,43 @@ func (s *server) handleConnection(conn net.Conn) {
 			break
 		}
 
-		// 4. verify channel partner signature
+		// the payment may pay one of my invoices
+		var paid_invoice *invoice
+		if len(client_request.Args) > 5 && len(client_request.Args[5]) > 0 {
+			var payment_hash [32]byte
+			copy(payment_hash[:], client_request.Args[5])
+			paid_invoice, err = s.payableInvoice(payment_hash, uint64(counterparty_balance_diff))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				server_response.Message = "reject"
+				break
+			}
+		}
+
+		// the payer may attach a memo, which must be signed by the payer
+		var memo []byte
+		var memo_signature []byte
+		if len(client_request.Args) > 7 && len(client_request.Args[6]) > 0 {
+			memo = client_request.Args[6]
+			memo_signature = client_request.Args[7]
+			if len(memo) > MAX_MEMO_LENGTH || !payment.VerifyMemo(onchain_state.app_id, memo_signature, onchain_state.signingKey(counterparty_address), s.genesis_hash, new_timestamp, memo) {
+				fmt.Println("Error: invalid payment memo")
+				server_response.Message = "reject"
+				break
+			}
+		}
+
+		// 3. verify channel partner signature
 		channel_partner_signature_correct := payment.VerifyState(
 			onchain_state.app_id,
+			onchain_state.asset_id,
 			alice_new_balance,
 			bob_new_balance,
-			4161,
+			s.genesis_hash,
 			channel_partner_signature,
-			counterparty_address,
+			onchain_state.signingKey(counterparty_address),
 			new_timestamp,
+			latestOffChainState.htlcs,
 		)
 		if !channel_partner_signature_correct {
 			fmt.Println("Error: invalid channel partner signature")
@@ -281,18 +471,21 @@ func (s *server) handleConnection(conn net.Conn) {
 			break
 		}
 
-		// 5. sign the state as well
+		// 4. sign the state as well
 		my_signature, err := payment.SignState(
 			onchain_state.app_id,
-			s.algo_account,
+			onchain_state.asset_id,
+			onchain_state.my_signer,
 			alice_new_balance,
 			bob_new_balance,
-			4161,
+			s.genesis_hash,
 			new_timestamp,
+			latestOffChainState.htlcs,
 		)
 		if err != nil {
-			log.Fatalf("Error signing state: %v\n", err)
-			return
+			fmt.Printf("Error signing state: %v\n", err)
+			server_response.Message = "reject"
+			break
 		}
 
 		var alice_signature []byte
@@ -305,32 +498,40 @@ func (s *server) handleConnection(conn net.Conn) {
 			bob_signature = my_signature
 		}
 
-		// 6. save new state
+		// 5. save new state
 		off_chain_state := &paymentChannelOffChainState{
 			timestamp: new_timestamp,
 
 			alice_balance: alice_new_balance,
 			bob_balance:   bob_new_balance,
+			htlcs:         latestOffChainState.htlcs,
+
+			memo:           string(memo),
+			memo_signature: memo_signature,
 
 			alice_signature: alice_signature,
 			bob_signature:   bob_signature,
 
-			algorand_port: 4161,
-			app_id:        onchain_state.app_id,
+			genesis_hash: s.genesis_hash,
+			app_id:       onchain_state.app_id,
 		}
 
-		if s.payment_channels_offchain_states_log[counterparty_address] == nil {
-			s.payment_channels_offchain_states_log[counterparty_address] = make(map[int64]paymentChannelOffChainState)
-		}
-		s.payment_channels_offchain_states_log[counterparty_address][new_timestamp] = *off_chain_state
+		s.saveOffChainState(counterparty_address, *off_chain_state)
 
-		// 7. send response to client
+		// 6. send response to client, the preimage proves that the invoice was paid
 		server_response.Message = "approve"
 		server_response.Data = [][]byte{
 			my_signature,
 		}
+		if paid_invoice != nil {
+			s.settleInvoice(paid_invoice, uint64(counterparty_balance_diff))
+			server_response.Data = append(server_response.Data, paid_invoice.preimage)
+		}
 
 		fmt.Printf("Process payment_request of %d microalgos\n", counterparty_balance_diff)
+		if len(memo) > 0 {
+			fmt.Printf("Memo: %q\n", memo)
+		}
 		fmt.Printf("Alice new balance: %d\n", alice_new_balance)
 		fmt.Printf("Bob new balance: %d\n\n", bob_new_balance)
 
@@ -338,30 +539,28 @@ func (s *server) handleConnection(conn net.Conn) {
 		counterparty_address := string(client_request.Args[0])
 		channel_partner_signature := client_request.Args[1]
 
-		// 1. load onchain state
-		onchain_state, ok := s.payment_channels_onchain_states[counterparty_address]
-		if !ok {
-			fmt.Printf("Error: payment channel with address %s does not exist\n", counterparty_address)
+		// 1. load on chain and latest off chain state
+		onchain_state, latestOffChainState, _, err := s.loadChannelState(counterparty_address)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			server_response.Message = "reject"
 			break
 		}
-
-		// 2. load latest off chain state
-		latestOffChainState, err := getLatestOffChainState(s.payment_channels_offchain_states_log[counterparty_address])
-		if err != nil {
-			fmt.Printf("Error getting latest off chain state: %v\n", err)
+		if len(latestOffChainState.htlcs) > 0 {
+			fmt.Println("Error: channel has pending htlcs")
 			server_response.Message = "reject"
 			break
 		}
 
-		// 3. verify channel partner signature
+		// 2. verify channel partner signature
 		channel_partner_signature_correct := payment.VerifyClose(
 			onchain_state.app_id,
+			onchain_state.asset_id,
 			latestOffChainState.alice_balance,
 			latestOffChainState.bob_balance,
-			4161,
+			s.genesis_hash,
 			channel_partner_signature,
-			counterparty_address,
+			onchain_state.signingKey(counterparty_address),
 			latestOffChainState.timestamp,
 		)
 		if !channel_partner_signature_correct {
@@ -370,33 +569,78 @@ func (s *server) handleConnection(conn net.Conn) {
 			break
 		}
 
-		// 4. sign the state as well
+		// 3. sign the state as well
 		my_signature, err := payment.SignClose(
 			onchain_state.app_id,
-			s.algo_account,
+			onchain_state.asset_id,
+			onchain_state.my_signer,
 			latestOffChainState.alice_balance,
 			latestOffChainState.bob_balance,
-			4161,
+			s.genesis_hash,
 			latestOffChainState.timestamp,
 		)
 		if err != nil {
-			log.Fatalf("Error signing state: %v\n", err)
-			return
+			fmt.Printf("Error signing state: %v\n", err)
+			server_response.Message = "reject"
+			break
 		}
 
-		// 5. send response to client
+		// 4. send response to client
 		server_response.Message = "approve"
 		server_response.Data = [][]byte{
 			my_signature,
 		}
 
+		// 5. delete payment channel from on chain state, my partner closes the channel with both signatures
+		s.forgetChannel(counterparty_address, onchain_state.app_id)
+
 		fmt.Printf("Processed close_channel_request with app_id %d\n", onchain_state.app_id)
+	case "add_htlc_request", "settle_htlc_request", "fail_htlc_request":
+		var htlc *payment.HTLC
+		server_response, htlc = s.processHTLCRequest(client_request.Command, client_request.Args)
+		if htlc != nil {
+			after_response = s.htlcForwardingHook(client_request.Command, *htlc, client_request.Args)
+		}
+	case "deposit_request":
+		server_response = s.processDepositRequest(client_request.Args)
+	case "withdraw_request":
+		server_response = s.processWithdrawRequest(client_request.Args)
+	case "deposit_confirmation", "withdraw_confirmation":
+		server_response = s.processCapacityConfirmation(client_request.Args)
+	case "forwarding_policy_request":
+		server_response.Message = "approve"
+		server_response.Data = [][]byte{
+			uint64ToBytes(s.forwarding_policy.base_fee),
+			uint64ToBytes(s.forwarding_policy.fee_ppm),
+			uint64ToBytes(s.forwarding_policy.timelock_delta),
+		}
 	case "pay_response":
 		fmt.Println("pay_response")
 	default:
 		fmt.Println("Received unknown command")
 	}
+	if unlock_channel != nil {
+		unlock_channel()
+	}
+
+	s.writeP2PResponse(conn, server_response)
+
+	if after_response != nil {
+		go after_response()
+	}
+}
 
+// updatesChannel tells whether a request changes or closes the channel with the partner node, whose address is its first argument
+func updatesChannel(client_request P2PRequest) bool {
+	switch client_request.Command {
+	case "pay_request", "close_channel_request", "add_htlc_request", "settle_htlc_request", "fail_htlc_request",
+		"deposit_request", "withdraw_request", "deposit_confirmation", "withdraw_confirmation":
+		return len(client_request.Args) > 0
+	}
+	return false
+}
+
+func (s *server) writeP2PResponse(conn net.Conn, server_response P2PResponse) {
 	// conver P2PResponse to json
 	server_response_data, err := json.Marshal(server_response)
 	if err != nil {
@@ -430,6 +674,78 @@ func getLatestOffChainState(payment_log map[int64]paymentChannelOffChainState) (
 	return &latest_offchain_state, nil
 }
 
+// getPaymentHistory lists the payments of a channel, which are the state updates that only moved balances
+// and the settled htlcs of invoices and multi-hop payments. Added htlcs are still pending, failed ones were refunded.
+func getPaymentHistory(partner_address string, me_alice bool, payment_log map[int64]paymentChannelOffChainState) []*asrpc.Payment {
+	timestamps := make([]int64, 0, len(payment_log))
+	for timestamp := range payment_log {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	payments := make([]*asrpc.Payment, 0)
+	for i := 1; i < len(timestamps); i++ {
+		previous_state := payment_log[timestamps[i-1]]
+		state := payment_log[timestamps[i]]
+		if previous_state.alice_balance+previous_state.bob_balance+htlcsTotal(previous_state.htlcs) != state.alice_balance+state.bob_balance+htlcsTotal(state.htlcs) {
+			continue // deposit or withdrawal
+		}
+
+		var amount uint64
+		var outgoing bool
+		if !bytes.Equal(payment.EncodeHTLCs(previous_state.htlcs), payment.EncodeHTLCs(state.htlcs)) {
+			// a settled htlc paid the receiver, the offerer got the amount of a failed one back
+			htlc, ok := removedHTLC(previous_state.htlcs, state.htlcs)
+			if !ok {
+				continue // htlc added
+			}
+			if htlc.OfferedByAlice && state.bob_balance != previous_state.bob_balance+htlc.Amount {
+				continue
+			}
+			if !htlc.OfferedByAlice && state.alice_balance != previous_state.alice_balance+htlc.Amount {
+				continue
+			}
+			amount = htlc.Amount
+			outgoing = htlc.OfferedByAlice == me_alice
+		} else {
+			var my_previous_balance, my_balance uint64
+			if me_alice {
+				my_previous_balance, my_balance = previous_state.alice_balance, state.alice_balance
+			} else {
+				my_previous_balance, my_balance = previous_state.bob_balance, state.bob_balance
+			}
+			if my_previous_balance == my_balance {
+				continue
+			}
+
+			outgoing = my_balance < my_previous_balance
+			amount = my_balance - my_previous_balance
+			if outgoing {
+				amount = my_previous_balance - my_balance
+			}
+		}
+		payments = append(payments, &asrpc.Payment{
+			PartnerAddress: partner_address,
+			Timestamp:      timestamppb.New(time.Unix(0, state.timestamp)),
+			Amount:         amount,
+			Outgoing:       outgoing,
+			Memo:           state.memo,
+			AliceBalance:   state.alice_balance,
+			BobBalance:     state.bob_balance,
+		})
+	}
+	return payments
+}
+
+// htlcsTotal sums the amounts locked in htlcs
+func htlcsTotal(htlcs []payment.HTLC) uint64 {
+	total := uint64(0)
+	for _, htlc := range htlcs {
+		total += htlc.Amount
+	}
+	return total
+}
+
 func getHighestBalanceOffChainState(is_alice bool, payment_log map[int64]paymentChannelOffChainState) (*paymentChannelOffChainState, error) {
 	highest_balance := uint64(0)
 	var highest_balance_offchain_state paymentChannelOffChainState
@@ -455,147 +771,82 @@ func getHighestBalanceOffChainState(is_alice bool, payment_log map[int64]payment
 	return &highest_balance_offchain_state, nil
 }
 
-func parseInt(s string) int {
-	var i int
-	_, err := fmt.Sscanf(s, "%d", &i)
-	if err != nil {
-		log.Fatalf("Error parsing int: %v\n", err)
-	}
-	return i
-}
-
-func (s *server) doOpenChannelSecurityChecks(blockchain_app_info models.Application) bool {
-	// 1. verify smart contracts with local copy
-	expected_approval_program, expected_clearstate_program := payment.CompilePaymentPrograms(s.algod_client)
-
-	requested_approval_program := blockchain_app_info.Params.ApprovalProgram
-	requested_clearstate_program := blockchain_app_info.Params.ClearStateProgram
-
-	smart_contracs_equal := bytes.Equal(requested_approval_program, expected_approval_program) &&
-		bytes.Equal(requested_clearstate_program, expected_clearstate_program)
-	if !smart_contracs_equal {
+func (s *server) doOpenChannelSecurityChecks(blockchain_app_info models.Application, app_state payment.ChannelAppState) bool {
+	// 1. verify that the smart contracts are a whitelisted contract version
+	contract_version, whitelisted := payment.LookupContractVersion(blockchain_app_info.Params.ApprovalProgram, blockchain_app_info.Params.ClearStateProgram)
+	if !whitelisted {
+		fmt.Println("smart contracts of the app are no known contract version")
 		return false
 	}
+	fmt.Printf("Channel app %d runs contract version %d\n", blockchain_app_info.Id, contract_version.Version)
 
 	// 2. verify that my address is bob_address
-	my_address := s.algo_account.Address.String()
-	bob_address_value := GetValueOfGlobalState(blockchain_app_info.Params.GlobalState, "bob_address")
-	if bob_address_value == nil {
-		fmt.Println("bob_address not found in global state")
-		return false
-	}
-	bob_address, err := types.EncodeAddress(bob_address_value)
-	if err != nil {
-		log.Fatalf("Error encoding address: %v\n", err)
-	}
-	algo_addresses_equal := my_address == bob_address
-	if !algo_addresses_equal {
+	my_address := s.account().Address()
+	if app_state.BobAddress != my_address {
 		return false
 	}
 
-	// 3. verify that dispute_window is above min_dispute_window and below max_dispute_window
-	dispute_window_value := GetValueOfGlobalState(blockchain_app_info.Params.GlobalState, "dispute_window")
-	if dispute_window_value == nil {
-		fmt.Println("dispute_window not found in global state")
+	// 3. verify that the channel states are signed for my network
+	if !bytes.Equal(app_state.GenesisHash, s.genesis_hash) {
+		fmt.Println("genesis_hash of the app does not match my network")
 		return false
 	}
-	dispute_window := parseInt(string(dispute_window_value))
-	min_dispute_window := 2
-	max_dispute_window := 10_000
-	dispute_window_check := dispute_window >= min_dispute_window && dispute_window <= max_dispute_window
+
+	// 4. verify that dispute_window is above min_dispute_window and below max_dispute_window
+	min_dispute_window := uint64(2)
+	max_dispute_window := uint64(10_000)
+	dispute_window_check := app_state.DisputeWindow >= min_dispute_window && app_state.DisputeWindow <= max_dispute_window
 	if !dispute_window_check {
 		return false
 	}
 
-	// 4. verify that penalty is above min_threshold and below max_threshold
-	penalty_value := GetValueOfGlobalState(blockchain_app_info.Params.GlobalState, "penalty_reserve")
-	if penalty_value == nil {
-		fmt.Println("penalty_reserve not found in global state")
-		return false
-	}
-	penalty_reserve := parseInt(string(penalty_value))
-	min_penalty_reserve := 100
-	max_penalty_reserve := 100_000_000
-	penalty_reserve_check := penalty_reserve >= min_penalty_reserve && penalty_reserve <= max_penalty_reserve
+	// 5. verify that penalty is above min_threshold and below max_threshold
+	min_penalty_reserve := uint64(100)
+	max_penalty_reserve := uint64(100_000_000)
+	penalty_reserve_check := app_state.PenaltyReserve >= min_penalty_reserve && app_state.PenaltyReserve <= max_penalty_reserve
 	if !penalty_reserve_check {
 		return false
 	}
 
-	return true
-}
-
-func GetValueOfGlobalState(global_state []models.TealKeyValue, key string) []byte {
-	for _, teal_key_value := range global_state {
-		// decode base64 for teal_key_value.Key
-		decoded_key, err := base64.StdEncoding.DecodeString(teal_key_value.Key)
+	// 6. opt into the asset of the channel, otherwise I could not receive my payout
+	asset_id := app_state.AssetID
+	if asset_id != 0 && !payment.IsOptedInAsset(s.algod_client, my_address.String(), asset_id) {
+		opt_in_fee, err := payment.OptInAsset(s.algod_client, s.account(), asset_id)
 		if err != nil {
-			log.Fatalf("Error decoding base64: %v\n", err)
-		}
-
-		if string(decoded_key) == key {
-			switch teal_key_value.Value.Type {
-			case 1: // it's bytes, probably an algo address
-				decoded_value, err := base64.StdEncoding.DecodeString(teal_key_value.Value.Bytes)
-				if err != nil {
-					log.Fatalf("Error decoding base64: %v\n", err)
-				}
-				return decoded_value
-			case 2: // it's uint64
-				return []byte(strconv.FormatUint(teal_key_value.Value.Uint, 10))
-			default:
-				log.Fatalf("Unknown type: %v\n", teal_key_value.Value.Type)
-			}
+			fmt.Printf("Error opting into asset %d: %v\n", asset_id, err)
+			return false
 		}
+		s.recordChannelFee(app_state.AppID, app_state.AliceAddress.String(), opt_in_fee)
 	}
-	return nil
+
+	return true
 }
 
-func (s *server) savePaymentChannelOnChainState(partner_ip string, appID uint64, global_state []models.TealKeyValue) {
+func (s *server) savePaymentChannelOnChainState(partner_ip string, app_state payment.ChannelAppState, my_signer payment.Signer) {
 	onchain_state := &paymentChannelInfo{
 		partner_ip: partner_ip,
-		app_id:     appID,
-	}
+		app_id:     app_state.AppID,
 
-	for _, teal_key_value := range global_state {
-		// decode base64 for teal_key_value.Key
-		decoded_key, err := base64.StdEncoding.DecodeString(teal_key_value.Key)
-		if err != nil {
-			log.Fatalf("Error decoding base64: %v\n", err)
-		}
+		alice_address: app_state.AliceAddress.String(),
+		bob_address:   app_state.BobAddress.String(),
 
-		switch teal_key_value.Value.Type {
-		case 1: // it's bytes, probably an algo address
-			decoded_value, err := base64.StdEncoding.DecodeString(teal_key_value.Value.Bytes)
-			if err != nil {
-				log.Fatalf("Error decoding base64: %v\n", err)
-			}
-			address, err := types.EncodeAddress(decoded_value)
-			if err != nil {
-				log.Fatalf("Error encoding address: %v\n", err)
-			}
+		alice_signing_key: app_state.AliceSigningKey.String(),
+		bob_signing_key:   my_signer.Address().String(), // registered on chain after app_state was read
+		my_signer:         my_signer,
 
-			switch string(decoded_key) {
-			case "alice_address":
-				onchain_state.alice_address = address
-			case "bob_address":
-				onchain_state.bob_address = address
-			}
-		case 2: // it's uint64
-
-			switch string(decoded_key) {
-			case "dispute_window":
-				onchain_state.dispute_window = teal_key_value.Value.Uint
-			case "total_deposit":
-				onchain_state.total_deposit = teal_key_value.Value.Uint
-			case "penalty_reserve":
-				onchain_state.penalty_reserve = teal_key_value.Value.Uint
-			case "latest_alice_balance":
-				onchain_state.alice_onchain_balance = teal_key_value.Value.Uint
-			case "latest_bob_balance":
-				onchain_state.bob_onchain_balance = teal_key_value.Value.Uint
-			}
-		}
+		asset_id: app_state.AssetID,
+
+		alice_onchain_balance: app_state.LatestAliceBalance,
+		bob_onchain_balance:   app_state.LatestBobBalance,
+
+		total_deposit:   app_state.TotalDeposit,
+		penalty_reserve: app_state.PenaltyReserve,
+		dispute_window:  app_state.DisputeWindow,
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// save onchain_state in map
 	s.payment_channels_onchain_states[onchain_state.alice_address] = *onchain_state
 
@@ -606,8 +857,8 @@ func (s *server) savePaymentChannelOnChainState(partner_ip string, appID uint64,
 		alice_balance: onchain_state.alice_onchain_balance,
 		bob_balance:   onchain_state.bob_onchain_balance,
 
-		algorand_port: 4161,
-		app_id:        onchain_state.app_id,
+		genesis_hash: s.genesis_hash,
+		app_id:       onchain_state.app_id,
 	}
 
 	if s.payment_channels_offchain_states_log[onchain_state.alice_address] == nil {
@@ -616,6 +867,14 @@ func (s *server) savePaymentChannelOnChainState(partner_ip string, appID uint64,
 	s.payment_channels_offchain_states_log[onchain_state.alice_address][off_chain_state.timestamp] = *off_chain_state
 }
 
+func (s *server) getCurrentRound() (uint64, error) {
+	status, err := s.algod_client.Status().Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return status.LastRound, nil
+}
+
 func (s *server) getAlgoBalance(address string) (uint64, error) {
 	// get balance
 	account_info, err := s.algod_client.AccountInformation(address).Do(context.Background())
@@ -194,6 +194,7 @@ pos commands launch --name run-container ${alice_node} -- docker run -d --name a
                                             -e ALGOD_ADDRESS="http://${sandbox_ip}:4001" \
                                             -e KMD_ADDRESS="http://${sandbox_ip}:4002" \
                                             -e INDEXER_ADDRESS="http://${sandbox_ip}:8980" \
+                                            -e DEV_MODE="true" \
                                                 asc-my-node
                                             # -e SEED_PHRASE="auction palm thumb shuffle aim fade cover glass fire spawn course harbor moon decline shed shop envelope virtual visa attitude hand december portion abstract labor" \
 
@@ -203,6 +204,7 @@ pos commands launch --name run-container ${bob_node} -- docker run -d --name asc
                                             -e ALGOD_ADDRESS="http://${sandbox_ip}:4001" \
                                             -e KMD_ADDRESS="http://${sandbox_ip}:4002" \
                                             -e INDEXER_ADDRESS="http://${sandbox_ip}:8980" \
+                                            -e DEV_MODE="true" \
                                                 asc-my-node
                                             # -e SEED_PHRASE="prize struggle destroy tray harvest wear century length thought diagram rubber page bridge weasel same ocean team index skin volume witness record cinnamon able machine" \
 
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/dancodery/algorand-state-channels/asrpc"
+	"github.com/dancodery/algorand-state-channels/payment"
+	"github.com/dancodery/algorand-state-channels/payment/testing"
+	"golang.org/x/crypto/ed25519"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// methods which work while the wallet is locked
+var lockedMethods = map[string]bool{
+	"/ASRPC/Create": true,
+	"/ASRPC/Unlock": true,
+}
+
+// lockInterceptor refuses all other rpcs until the wallet was unlocked
+func (r *rpcServer) lockInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !r.server.isUnlocked() && !lockedMethods[info.FullMethod] {
+		return nil, errors.New("wallet is locked, create or unlock it first")
+	}
+	return handler(ctx, req)
+}
+
+// account returns the signer of the node account, Create and Unlock may replace it any time
+func (s *server) account() payment.Signer {
+	s.wallet_mu.RLock()
+	defer s.wallet_mu.RUnlock()
+	return s.algo_account
+}
+
+func (s *server) isUnlocked() bool {
+	s.wallet_mu.RLock()
+	defer s.wallet_mu.RUnlock()
+	return s.unlocked
+}
+
+// setAccount unlocks the wallet with the signer of the node account
+func (s *server) setAccount(signer payment.Signer) {
+	s.wallet_mu.Lock()
+	defer s.wallet_mu.Unlock()
+	s.algo_account = signer
+	s.unlocked = true
+}
+
+// loadKMDSigner signs with the given account of a kmd wallet instead of a local key,
+// the channel keys are derived from the seed given as mnemonic
+func (s *server) loadKMDSigner(wallet_name string, wallet_password string, algo_address string, channel_seed_mnemonic string) error {
+	channel_seed, err := mnemonic.ToKey(channel_seed_mnemonic)
+	if err != nil {
+		fmt.Printf("Error reading the kmd channel seed: %v\n", err)
+		return err
+	}
+	kmd_signer, err := payment.NewKMDSigner(testing.GetKmdClient(), wallet_name, wallet_password, algo_address, channel_seed)
+	if err != nil {
+		fmt.Printf("Error loading kmd wallet %s: %v\n", wallet_name, err)
+		return err
+	}
+	s.setAccount(kmd_signer)
+
+	fmt.Printf("Signing with kmd wallet %s, invoices and multi-hop payments are not supported\n", wallet_name)
+	fmt.Printf("My node ALGO address is: %v\n", s.account().Address().String())
+	return nil
+}
+
+// holdsAccountKey tells whether the node holds the key of its account, which signs invoices and decrypts onions.
+// The kmd backend keeps the key in kmd, the authorized backend signs for an account authorized by other keys.
+func (s *server) holdsAccountKey() bool {
+	_, ok := s.account().(payment.KeyExporter)
+	return ok
+}
+
+// loadAuthorizedSigner signs for a rekeyed or multisig funding account with the configured authorizing keys
+func (s *server) loadAuthorizedSigner(loaded_config *config) error {
+	var keys []ed25519.PrivateKey
+	for _, auth_mnemonic := range loaded_config.AuthMnemonics {
+		private_key, err := mnemonic.ToPrivateKey(auth_mnemonic)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, private_key)
+	}
+
+	var multisig *crypto.MultisigAccount
+	if len(loaded_config.MultisigAddresses) > 0 {
+		var member_addresses []types.Address
+		for _, member := range loaded_config.MultisigAddresses {
+			member_address, err := types.DecodeAddress(member)
+			if err != nil {
+				return err
+			}
+			member_addresses = append(member_addresses, member_address)
+		}
+		multisig_account, err := crypto.MultisigAccountWithParams(MULTISIG_VERSION, loaded_config.MultisigThreshold, member_addresses)
+		if err != nil {
+			return err
+		}
+		multisig = &multisig_account
+	}
+
+	authorized_signer, err := payment.NewAuthorizedSigner(s.algod_client, loaded_config.FundingAddress, keys, multisig)
+	if err != nil {
+		fmt.Printf("Error loading funding account %s: %v\n", loaded_config.FundingAddress, err)
+		return err
+	}
+	s.setAccount(authorized_signer)
+
+	fmt.Printf("Signing for funding account %s, invoices and multi-hop payments are not supported\n", loaded_config.FundingAddress)
+	fmt.Printf("My node ALGO address is: %v\n", s.account().Address().String())
+	return nil
+}
+
+func (r *rpcServer) Create(ctx context.Context, in *asrpc.CreateRequest) (*asrpc.CreateResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	r.server.unlock_mu.Lock()
+	defer r.server.unlock_mu.Unlock()
+	if r.server.isUnlocked() && !r.server.dev_mode {
+		return nil, errors.New("wallet is already unlocked")
+	}
+
+	// 1. restore or generate the account
+	var account crypto.Account
+	if in.Mnemonic != "" {
+		private_key, err := mnemonic.ToPrivateKey(in.Mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		account, err = crypto.AccountFromPrivateKey(private_key)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		account = crypto.GenerateAccount()
+	}
+	backup_mnemonic, err := mnemonic.FromPrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	err = r.server.checkRetiredChannels(account.Address.String())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return nil, err
+	}
+
+	// 2. encrypt it into the keystore
+	err = createKeystore(r.server.keystore_path, in.Password, account)
+	if err != nil {
+		fmt.Printf("Error creating keystore: %v\n", err)
+		return nil, err
+	}
+
+	// 3. unlock the wallet
+	r.server.setAccount(payment.NewAccountSigner(account))
+
+	fmt.Printf("Created keystore %s\n", r.server.keystore_path)
+	fmt.Printf("My node ALGO address is: %v\n\n", account.Address.String())
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	response := &asrpc.CreateResponse{
+		AlgoAddress:      account.Address.String(),
+		RuntimeRecording: runtime_recording,
+	}
+	if in.Mnemonic == "" {
+		response.Mnemonic = backup_mnemonic
+	}
+	return response, nil
+}
+
+func (r *rpcServer) Unlock(ctx context.Context, in *asrpc.UnlockRequest) (*asrpc.UnlockResponse, error) {
+	timestamp_start := timestamppb.Now()
+
+	r.server.unlock_mu.Lock()
+	defer r.server.unlock_mu.Unlock()
+	if r.server.isUnlocked() && !r.server.dev_mode {
+		return nil, errors.New("wallet is already unlocked")
+	}
+
+	// decrypt the account from the keystore
+	account, err := loadKeystore(r.server.keystore_path, in.Password)
+	if err != nil {
+		fmt.Printf("Error unlocking keystore: %v\n", err)
+		return nil, err
+	}
+	err = r.server.checkRetiredChannels(account.Address.String())
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return nil, err
+	}
+	r.server.setAccount(payment.NewAccountSigner(account))
+
+	fmt.Printf("Unlocked keystore %s\n", r.server.keystore_path)
+	fmt.Printf("My node ALGO address is: %v\n\n", account.Address.String())
+
+	timestamp_end := timestamppb.Now()
+
+	runtime_recording := &asrpc.RuntimeRecording{
+		TimestampStart: timestamp_start,
+		TimestampEnd:   timestamp_end,
+	}
+	return &asrpc.UnlockResponse{
+		AlgoAddress:      account.Address.String(),
+		RuntimeRecording: runtime_recording,
+	}, nil
+}
@@ -1,121 +1,208 @@
 package main
 
 import (
-	"context"
 	"fmt"
-	"log"
-	"strconv"
 	"time"
 
 	"github.com/dancodery/algorand-state-channels/payment"
 )
 
+// clock paces the polling of the node, tests replace it to step the watchtower along a simulated chain
+type clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// UpdateWatchtowerState checks the channels every WATCHTOWER_INTERVAL until the node is stopped
 func (s *server) UpdateWatchtowerState() {
 	go func() {
 		for {
-			for address, payment_channel_onchain_state := range s.payment_channels_onchain_states {
-				// read smart contract from the blockchain for given app_id
-				blockchain_app_info, err := s.algod_client.GetApplicationByID(payment_channel_onchain_state.app_id).Do(context.Background())
-				if err != nil {
-					log.Fatalf("Error reading smart contract from blockchain: %v\n", err)
-					return
-				}
-				// fmt.Printf("Smart contract info: %+v\n", blockchain_app_info)
-				// check if the channel is in the closing phase
-				timeout_bytes := GetValueOfGlobalState(blockchain_app_info.Params.GlobalState, "timeout")
-				if timeout_bytes == nil {
-					continue
-				}
-				timeout := parseInt(string(timeout_bytes))
-
-				// if closing was initiated
-				if timeout > 0 {
-					// find out if I am alice or bob
-					var is_alice bool
-					if s.algo_account.Address.String() == payment_channel_onchain_state.alice_address {
-						is_alice = true
-					} else {
-						is_alice = false
-					}
-
-					// get latest off chain state
-					payment_log, ok := s.payment_channels_offchain_states_log[address]
-					if !ok {
-						fmt.Printf("Error: payment channel with partner node %v does not exist\n", address)
-						continue
-					}
-					latestOffChainState, err := getLatestOffChainState(payment_log)
-					if err != nil {
-						fmt.Printf("Error: %v\n", err)
-						continue
-					}
-
-					onchain_latest_alice_balance_bytes := GetValueOfGlobalState(blockchain_app_info.Params.GlobalState, "latest_alice_balance")
-					if onchain_latest_alice_balance_bytes == nil {
-						log.Fatalf("Error: latest_alice_balance not found in smart contract\n")
-						continue
-					}
-					onchain_latest_alice_balance, err := strconv.ParseUint((string(onchain_latest_alice_balance_bytes)), 10, 64)
-					if err != nil {
-						log.Fatalf("Error parsing latest_alice_balance: %v\n", err)
-						continue
-					}
-
-					onchain_latest_bob_balance_bytes := GetValueOfGlobalState(blockchain_app_info.Params.GlobalState, "latest_bob_balance")
-					if onchain_latest_bob_balance_bytes == nil {
-						log.Fatalf("Error: latest_bob_balance not found in smart contract\n")
-						continue
-					}
-					onchain_latest_bob_balance, err := strconv.ParseUint((string(onchain_latest_bob_balance_bytes)), 10, 64)
-					if err != nil {
-						log.Fatalf("Error parsing latest_bob_balance: %v\n", err)
-						continue
-					}
-
-					// check if the onchainstate is beneficial for me
-					var onchain_my_balance uint64
-					var offchain_my_balance uint64
-					if is_alice {
-						onchain_my_balance = onchain_latest_alice_balance
-						offchain_my_balance = latestOffChainState.alice_balance
-					} else {
-						onchain_my_balance = onchain_latest_bob_balance
-						offchain_my_balance = latestOffChainState.bob_balance
-					}
-
-					if onchain_my_balance >= offchain_my_balance {
-						// this case is beneficial for me
-						fmt.Printf("Latest balances are beneficial for me, I don't want to dispute\n\n")
-						continue
-					}
-
-					// if the latest balances are not correct, we need to dispute
-					fmt.Printf("Latest balances are not beneficial for me, I want to dispute\n\n")
-
-					payment.RaiseDispute(
-						s.algod_client,
-						s.algo_account,
-						4161,
-						payment_channel_onchain_state.app_id,
-						latestOffChainState.alice_balance,
-						latestOffChainState.bob_balance,
-						uint64(latestOffChainState.timestamp),
-						latestOffChainState.alice_signature,
-						latestOffChainState.bob_signature)
-
-					fmt.Printf("On chain state alice balance: %v\n", onchain_latest_alice_balance)
-					fmt.Printf("On chain state bob balance: %v\n", onchain_latest_bob_balance)
-					fmt.Printf("Disputed real alice balance: %v\n", latestOffChainState.alice_balance)
-					fmt.Printf("Disputed real bob balance: %v\n\n", latestOffChainState.bob_balance)
-
-					// delete the payment channel from the list of payment channels
-					delete(s.payment_channels_onchain_states, address)
-				}
+			select {
+			case <-s.clock.After(WATCHTOWER_INTERVAL):
+			case <-s.quit:
+				return
 			}
-
-			// sleep for 1 second
-			time.Sleep(1 * time.Second)
+			s.watchChannels()
 		}
 	}()
+}
+
+// watchChannels disputes closings of my channels with outdated states and claims the htlcs of beneficial ones
+func (s *server) watchChannels() {
+	// the channels are checked on a copy, so that reading the chain does not hold up the node
+	s.mu.Lock()
+	onchain_states := make(map[string]paymentChannelInfo, len(s.payment_channels_onchain_states))
+	for address, onchain_state := range s.payment_channels_onchain_states {
+		onchain_states[address] = onchain_state
+	}
+	s.mu.Unlock()
+
+	for address, payment_channel_onchain_state := range onchain_states {
+		// forget channels which were closed out, their app may have been deleted already
+		closed, err := payment.IsChannelAppClosed(s.algod_client, payment_channel_onchain_state.app_id)
+		if err != nil {
+			fmt.Printf("Error reading app account from blockchain: %v\n", err)
+			continue
+		}
+		if closed {
+			s.forgetChannel(address, payment_channel_onchain_state.app_id)
+			fmt.Printf("Forgot closed payment channel with app_id %d\n", payment_channel_onchain_state.app_id)
+			continue
+		}
+
+		// read smart contract from the blockchain for given app_id
+		app_state, err := payment.ReadChannelAppState(s.algod_client, payment_channel_onchain_state.app_id)
+		if err != nil {
+			fmt.Printf("Error reading smart contract from blockchain: %v\n", err)
+			continue
+		}
+
+		// if closing was initiated
+		if app_state.IsClosing() {
+			// find out if I am alice or bob
+			var is_alice bool
+			if s.account().Address().String() == payment_channel_onchain_state.alice_address {
+				is_alice = true
+			} else {
+				is_alice = false
+			}
+
+			// get latest off chain state
+			_, latestOffChainState, _, err := s.loadChannelState(address)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+
+			onchain_latest_alice_balance := app_state.LatestAliceBalance
+			onchain_latest_bob_balance := app_state.LatestBobBalance
+
+			// check if the onchainstate is beneficial for me
+			var onchain_my_balance uint64
+			var offchain_my_balance uint64
+			if is_alice {
+				onchain_my_balance = onchain_latest_alice_balance
+				offchain_my_balance = latestOffChainState.alice_balance
+			} else {
+				onchain_my_balance = onchain_latest_bob_balance
+				offchain_my_balance = latestOffChainState.bob_balance
+			}
+
+			// the app only replaces the closing state with a newer one
+			if onchain_my_balance >= offchain_my_balance || uint64(latestOffChainState.timestamp) <= app_state.LatestTimestamp {
+				// this case is beneficial for me
+				fmt.Printf("Latest balances are beneficial for me, I don't want to dispute\n\n")
+
+				// claim the htlcs offered to me for which I know the preimage
+				s.settleOnChainHTLCs(address, payment_channel_onchain_state, is_alice, app_state)
+				continue
+			}
+
+			// if the latest balances are not correct, we need to dispute
+			fmt.Printf("Latest balances are not beneficial for me, I want to dispute\n\n")
+
+			// the app only accepts states signed with the signing keys registered on chain
+			if !s.verifyWithOnChainSigningKeys(payment_channel_onchain_state, *latestOffChainState, app_state) {
+				fmt.Printf("Error: latest state of app_id %d is not signed with the registered signing keys\n", payment_channel_onchain_state.app_id)
+				continue
+			}
+
+			dispute_fee, err := payment.RaiseDispute(
+				s.algod_client,
+				s.account(),
+				s.genesis_hash,
+				payment_channel_onchain_state.app_id,
+				latestOffChainState.alice_balance,
+				latestOffChainState.bob_balance,
+				uint64(latestOffChainState.timestamp),
+				latestOffChainState.htlcs,
+				latestOffChainState.alice_signature,
+				latestOffChainState.bob_signature)
+			if err != nil {
+				fmt.Printf("Error raising dispute: %v\n", err)
+				continue
+			}
+			s.recordChannelFee(payment_channel_onchain_state.app_id, address, dispute_fee)
+
+			fmt.Printf("On chain state alice balance: %v\n", onchain_latest_alice_balance)
+			fmt.Printf("On chain state bob balance: %v\n", onchain_latest_bob_balance)
+			fmt.Printf("Disputed real alice balance: %v\n", latestOffChainState.alice_balance)
+			fmt.Printf("Disputed real bob balance: %v\n\n", latestOffChainState.bob_balance)
+
+			// the channel is kept, so that I can finalize the closing once the dispute window passed
+		}
+	}
+}
+
+// forgetChannel removes the channel with the partner, unless it was replaced by a channel with another app meanwhile
+func (s *server) forgetChannel(partner_address string, app_id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if onchain_state, ok := s.payment_channels_onchain_states[partner_address]; ok && onchain_state.app_id == app_id {
+		delete(s.payment_channels_onchain_states, partner_address)
+	}
+}
+
+// verifyWithOnChainSigningKeys checks both signatures of the state against the signing keys registered in the app
+func (s *server) verifyWithOnChainSigningKeys(payment_channel_onchain_state paymentChannelInfo, off_chain_state paymentChannelOffChainState, app_state payment.ChannelAppState) bool {
+	verify := func(signature []byte, signing_key string) bool {
+		return payment.VerifyState(
+			payment_channel_onchain_state.app_id,
+			payment_channel_onchain_state.asset_id,
+			off_chain_state.alice_balance,
+			off_chain_state.bob_balance,
+			s.genesis_hash,
+			signature,
+			signing_key,
+			off_chain_state.timestamp,
+			off_chain_state.htlcs)
+	}
+	return verify(off_chain_state.alice_signature, app_state.AliceSigningKey.String()) &&
+		verify(off_chain_state.bob_signature, app_state.BobSigningKey.String())
+}
+
+// settleOnChainHTLCs reveals the preimages of all incoming htlcs of the closing state before they expire
+func (s *server) settleOnChainHTLCs(address string, payment_channel_onchain_state paymentChannelInfo, is_alice bool, app_state payment.ChannelAppState) {
+	settled_htlcs := app_state.SettledHTLCs
+
+	onchain_htlcs := s.getOnChainHTLCs(address, int64(app_state.LatestTimestamp))
+	if len(onchain_htlcs) == 0 {
+		return
+	}
+	current_round, err := s.getCurrentRound()
+	if err != nil {
+		fmt.Printf("Error getting current round: %v\n", err)
+		return
+	}
+
+	for i, htlc := range onchain_htlcs {
+		already_settled := settled_htlcs&(1<<uint(i)) != 0
+		if already_settled || htlc.OfferedByAlice == is_alice || current_round > htlc.Timelock {
+			continue
+		}
+		preimage, ok := s.knownPreimage(htlc.Hashlock)
+		if !ok {
+			continue
+		}
+
+		settle_fee, err := payment.SettleHTLC(
+			s.algod_client,
+			s.account(),
+			payment_channel_onchain_state.app_id,
+			onchain_htlcs,
+			uint64(i),
+			preimage)
+		if err != nil {
+			fmt.Printf("Error settling htlc with hashlock %x: %v\n", htlc.Hashlock, err)
+			continue
+		}
+		s.recordChannelFee(payment_channel_onchain_state.app_id, address, settle_fee)
 
+		fmt.Printf("Settled htlc of %v microalgos with hashlock %x on chain\n\n", htlc.Amount, htlc.Hashlock)
+	}
 }
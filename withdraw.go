@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dancodery/algorand-state-channels/payment"
+)
+
+// withdrawFromChannel lets the channel partner co-sign a withdraw authorization taking amount from my balance,
+// withdraws the amount on chain and tells the partner about the confirmed withdrawal. It returns the fee of the withdrawal.
+// The phases of the withdrawal are recorded into recorder, which may be nil.
+func (s *server) withdrawFromChannel(counterparty_address string, amount uint64, recorder *payment.PhaseRecorder) (uint64, error) {
+	if amount == 0 {
+		return 0, errors.New("withdraw amount must be positive")
+	}
+	unlock_channel := s.lockChannel(counterparty_address)
+	defer unlock_channel()
+	onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		return 0, err
+	}
+	my_address := s.account().Address().String()
+
+	// 1. take the amount from my balance
+	new_alice_balance := latestOffChainState.alice_balance
+	new_bob_balance := latestOffChainState.bob_balance
+	new_htlcs := latestOffChainState.htlcs
+	var my_balance uint64
+	if me_alice {
+		my_balance = new_alice_balance
+	} else {
+		my_balance = new_bob_balance
+	}
+	if my_balance < amount+onchain_state.penalty_reserve {
+		return 0, fmt.Errorf("balance of %d is too low to withdraw %d and keep the penalty reserve", my_balance, amount)
+	}
+	if me_alice {
+		new_alice_balance -= amount
+	} else {
+		new_bob_balance -= amount
+	}
+	timestamp_now := time.Now().UnixNano()
+
+	// 2. sign new state and withdraw authorization
+	my_signer := payment.WithPhaseRecorder(onchain_state.my_signer, recorder)
+	my_signature, err := payment.SignState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		my_signer,
+		new_alice_balance,
+		new_bob_balance,
+		s.genesis_hash,
+		timestamp_now,
+		new_htlcs)
+	if err != nil {
+		return 0, err
+	}
+	my_withdraw_signature, err := payment.SignWithdraw(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		my_signer,
+		new_alice_balance,
+		new_bob_balance,
+		s.genesis_hash,
+		timestamp_now,
+		new_htlcs,
+		amount,
+		my_address)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3. let the partner co-sign both
+	end_round_trip := recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	server_response, err := s.sendRequest(onchain_state.partner_ip, P2PRequest{
+		Command: "withdraw_request",
+		Args: [][]byte{
+			[]byte(my_address),                   // 1. my address
+			uint64ToBytes(new_alice_balance),     // 2. alice's new balance
+			uint64ToBytes(new_bob_balance),       // 3. bob's new balance
+			uint64ToBytes(uint64(timestamp_now)), // 4. timestamp
+			payment.EncodeHTLCs(new_htlcs),       // 5. pending htlcs
+			my_signature,                         // 6. my signature
+			uint64ToBytes(amount),                // 7. withdraw amount
+			my_withdraw_signature,                // 8. my withdraw signature
+		},
+	})
+	end_round_trip()
+	if err != nil {
+		return 0, err
+	}
+	if server_response.Message != "approve" || len(server_response.Data) < 2 {
+		return 0, errors.New("partner node rejected withdraw_request")
+	}
+	partner_signature := server_response.Data[0]
+	partner_withdraw_signature := server_response.Data[1]
+	end_verification := recorder.Start(payment.PHASE_PARTNER_VERIFICATION)
+	partner_verified := payment.VerifyState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		new_alice_balance,
+		new_bob_balance,
+		s.genesis_hash,
+		partner_signature,
+		onchain_state.signingKey(counterparty_address),
+		timestamp_now,
+		new_htlcs) &&
+		payment.VerifyWithdraw(
+			onchain_state.app_id,
+			onchain_state.asset_id,
+			new_alice_balance,
+			new_bob_balance,
+			s.genesis_hash,
+			partner_withdraw_signature,
+			onchain_state.signingKey(counterparty_address),
+			timestamp_now,
+			new_htlcs,
+			amount,
+			my_address)
+	end_verification()
+	if !partner_verified {
+		return 0, errors.New("partner node's signature is invalid")
+	}
+
+	off_chain_state := paymentChannelOffChainState{
+		timestamp: timestamp_now,
+
+		alice_balance: new_alice_balance,
+		bob_balance:   new_bob_balance,
+		htlcs:         new_htlcs,
+
+		genesis_hash: s.genesis_hash,
+		app_id:       onchain_state.app_id,
+	}
+	alice_withdraw_signature, bob_withdraw_signature := my_withdraw_signature, partner_withdraw_signature
+	if me_alice {
+		off_chain_state.alice_signature = my_signature
+		off_chain_state.bob_signature = partner_signature
+	} else {
+		off_chain_state.alice_signature = partner_signature
+		off_chain_state.bob_signature = my_signature
+		alice_withdraw_signature, bob_withdraw_signature = partner_withdraw_signature, my_withdraw_signature
+	}
+
+	// 4. withdraw on chain
+	fee, err := payment.WithdrawFromChannel(
+		s.algod_client,
+		payment.WithPhaseRecorder(s.account(), recorder),
+		s.genesis_hash,
+		onchain_state.app_id,
+		new_alice_balance,
+		new_bob_balance,
+		uint64(timestamp_now),
+		new_htlcs,
+		amount,
+		onchain_state.asset_id,
+		alice_withdraw_signature,
+		bob_withdraw_signature)
+	if err == nil {
+		s.recordChannelFee(onchain_state.app_id, counterparty_address, fee)
+
+		// 5. save new state and capacity, older states no longer add up to the total deposit
+		s.saveOffChainState(counterparty_address, off_chain_state)
+		onchain_state.total_deposit -= amount
+		s.mu.Lock()
+		s.payment_channels_onchain_states[counterparty_address] = onchain_state
+		s.mu.Unlock()
+		s.UpdateWatchtowerState()
+	}
+
+	// 6. let the partner check the withdrawal on chain, it drops the pending state otherwise
+	end_round_trip = recorder.Start(payment.PHASE_P2P_ROUND_TRIP)
+	server_response, confirmation_err := s.sendRequest(onchain_state.partner_ip, P2PRequest{
+		Command: "withdraw_confirmation",
+		Args:    [][]byte{[]byte(my_address)},
+	})
+	end_round_trip()
+	if err != nil {
+		return fee, err
+	}
+	if confirmation_err != nil {
+		return fee, confirmation_err
+	}
+	if server_response.Message != "approve" {
+		return fee, errors.New("partner node did not confirm the withdrawal")
+	}
+	return fee, nil
+}
+
+// processWithdrawRequest co-signs a state and withdraw authorization taking amount from the channel partner's balance.
+// The state is only saved once the withdrawal is confirmed on chain.
+func (s *server) processWithdrawRequest(args [][]byte) (server_response P2PResponse) {
+	server_response.Message = "reject"
+
+	if len(args) < 8 {
+		fmt.Println("Error: withdraw_request has too few arguments")
+		return
+	}
+	if !areUint64Args(args, 1, 2, 3, 6) {
+		fmt.Println("Error: withdraw_request has malformed balances, timestamp or amount")
+		return
+	}
+	counterparty_address := string(args[0])
+	alice_new_balance := binary.BigEndian.Uint64(args[1])
+	bob_new_balance := binary.BigEndian.Uint64(args[2])
+	new_timestamp := int64(binary.BigEndian.Uint64(args[3]))
+	new_htlcs, err := payment.DecodeHTLCs(args[4])
+	if err != nil {
+		fmt.Printf("Error decoding htlcs: %v\n", err)
+		return
+	}
+	channel_partner_signature := args[5]
+	amount := binary.BigEndian.Uint64(args[6])
+	channel_partner_withdraw_signature := args[7]
+
+	// 1. load on chain and latest off chain state
+	onchain_state, latestOffChainState, me_alice, err := s.loadChannelState(counterparty_address)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if s.capacityChangePending(counterparty_address) {
+		fmt.Println("Error: a deposit or withdrawal is already pending")
+		return
+	}
+
+	// 2. verify that only the partner's balance decreased by the withdrawn amount
+	ok := amount > 0 &&
+		latestOffChainState.timestamp < new_timestamp &&
+		bytes.Equal(payment.EncodeHTLCs(latestOffChainState.htlcs), payment.EncodeHTLCs(new_htlcs))
+	if me_alice {
+		ok = ok && alice_new_balance == latestOffChainState.alice_balance &&
+			bob_new_balance+amount == latestOffChainState.bob_balance &&
+			bob_new_balance >= onchain_state.penalty_reserve
+	} else {
+		ok = ok && bob_new_balance == latestOffChainState.bob_balance &&
+			alice_new_balance+amount == latestOffChainState.alice_balance &&
+			alice_new_balance >= onchain_state.penalty_reserve
+	}
+	if !ok {
+		fmt.Println("Error: invalid new balances")
+		return
+	}
+
+	// 3. verify channel partner signatures
+	channel_partner_signatures_correct := payment.VerifyState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		channel_partner_signature,
+		onchain_state.signingKey(counterparty_address),
+		new_timestamp,
+		new_htlcs,
+	) && payment.VerifyWithdraw(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		channel_partner_withdraw_signature,
+		onchain_state.signingKey(counterparty_address),
+		new_timestamp,
+		new_htlcs,
+		amount,
+		counterparty_address,
+	)
+	if !channel_partner_signatures_correct {
+		fmt.Println("Error: invalid channel partner signature")
+		return
+	}
+
+	// 4. sign the state and the withdraw authorization as well
+	my_signature, err := payment.SignState(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		onchain_state.my_signer,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		new_timestamp,
+		new_htlcs,
+	)
+	if err != nil {
+		fmt.Printf("Error signing state: %v\n", err)
+		return
+	}
+	my_withdraw_signature, err := payment.SignWithdraw(
+		onchain_state.app_id,
+		onchain_state.asset_id,
+		onchain_state.my_signer,
+		alice_new_balance,
+		bob_new_balance,
+		s.genesis_hash,
+		new_timestamp,
+		new_htlcs,
+		amount,
+		counterparty_address,
+	)
+	if err != nil {
+		fmt.Printf("Error signing withdraw authorization: %v\n", err)
+		return
+	}
+
+	// 5. keep the state until the withdrawal is confirmed
+	off_chain_state := paymentChannelOffChainState{
+		timestamp: new_timestamp,
+
+		alice_balance: alice_new_balance,
+		bob_balance:   bob_new_balance,
+		htlcs:         new_htlcs,
+
+		genesis_hash: s.genesis_hash,
+		app_id:       onchain_state.app_id,
+	}
+	if me_alice {
+		off_chain_state.alice_signature = my_signature
+		off_chain_state.bob_signature = channel_partner_signature
+	} else {
+		off_chain_state.alice_signature = channel_partner_signature
+		off_chain_state.bob_signature = my_signature
+	}
+	s.mu.Lock()
+	s.pending_capacity_changes[counterparty_address] = pendingCapacityChange{
+		total_deposit:   onchain_state.total_deposit - amount,
+		off_chain_state: off_chain_state,
+	}
+	s.mu.Unlock()
+
+	fmt.Printf("Processed withdraw_request of %d microalgos with app_id %d\n\n", amount, onchain_state.app_id)
+
+	server_response.Message = "approve"
+	server_response.Data = [][]byte{
+		my_signature,
+		my_withdraw_signature,
+	}
+	return server_response
+}